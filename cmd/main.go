@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	_ "time/tzdata" // embed the IANA timezone database, the alpine image doesn't ship one
 
 	"github.com/labstack/echo/v4"
 	"github.com/niharika88/calendly-api/configs"
@@ -15,6 +16,8 @@ import (
 	_ "github.com/niharika88/calendly-api/docs"
 	"github.com/niharika88/calendly-api/internal/db/repo"
 	"github.com/niharika88/calendly-api/internal/handlers"
+	"github.com/niharika88/calendly-api/internal/mailer"
+	"github.com/niharika88/calendly-api/internal/providers"
 	"github.com/niharika88/calendly-api/internal/services"
 	"github.com/niharika88/calendly-api/pkg/api"
 	echoSwagger "github.com/swaggo/echo-swagger"
@@ -40,13 +43,48 @@ func main() {
 	// initialize repositories
 	userRepo := repo.NewUserRepo(db)
 	availabilityRepo := repo.NewAvailabilityRepo(db)
+	bookingRepo := repo.NewBookingRepo(db)
+	eventTypeRepo := repo.NewEventTypeRepo(db)
+	scheduleRepo := repo.NewScheduleRepo(db)
+	timeOffRepo := repo.NewTimeOffRepo(db)
+	busyBlockRepo := repo.NewBusyBlockRepo(db)
+	calendarConnectionRepo := repo.NewCalendarConnectionRepo(db)
+
+	// initialize calendar providers
+	var enabledProviders []providers.CalendarProvider
+	if cfg.FakeCalendarProvider {
+		fakeProvider, err := providers.NewFakeProvider(cfg.FakeCalendarDir, cfg.FakeCalendarBaseURL)
+		if err != nil {
+			panic(err)
+		}
+		enabledProviders = append(enabledProviders, fakeProvider)
+	}
+	calendarProviders := providers.NewRegistry(enabledProviders...)
+
+	// initialize mailer
+	var mail mailer.Mailer = mailer.NewLogMailer()
+	if cfg.SMTPHost != "" {
+		smtpMailer, err := mailer.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom)
+		if err != nil {
+			panic(err)
+		}
+		mail = smtpMailer
+	}
 
 	// initialize services
 	userService := services.NewUserService(userRepo)
-	availabilityService := services.NewAvailabilityService(availabilityRepo)
+	availabilityService := services.NewAvailabilityService(availabilityRepo, bookingRepo, scheduleRepo, timeOffRepo, busyBlockRepo)
+	eventTypeService := services.NewEventTypeService(eventTypeRepo, bookingRepo, scheduleRepo, availabilityService)
+	invitationService := services.NewInvitationService(mail, cfg.SMTPFrom)
+	bookingService := services.NewBookingService(bookingRepo, userRepo, availabilityService, eventTypeService, invitationService)
+	scheduleService := services.NewScheduleService(scheduleRepo)
+	timeOffService := services.NewTimeOffService(timeOffRepo)
+	calendarService := services.NewCalendarService(userRepo, bookingRepo, availabilityService)
+	busyService := services.NewBusyService(busyBlockRepo)
+	calendarSyncService := services.NewCalendarSyncService(calendarConnectionRepo, userRepo, calendarProviders, cfg.CalendarSyncHorizonDays)
 
 	// initialize handlers
-	h := handlers.NewHandler(userService, availabilityService)
+	h := handlers.NewHandler(userService, availabilityService, bookingService, eventTypeService, scheduleService, timeOffService, calendarService, busyService, calendarSyncService)
 
 	// initialize routes
 	api := router.Group("/api")
@@ -61,10 +99,69 @@ func main() {
 
 	api.POST("/availability/day", h.CreateDayAvailability)
 	api.POST("/availability/date", h.CreateDateAvailability)
+	api.POST("/availability/date/range", h.CreateDateRangeAvailability)
+	api.POST("/availability/rule", h.CreateRuleAvailability)
 	api.DELETE("/availability/day", h.DeleteDayAvailabilities)
 	api.DELETE("/availability/date", h.DeleteDateAvailability)
+	api.DELETE("/availability/date/range", h.DeleteDateRangeAvailability)
+	api.DELETE("/availability/rule", h.DeleteRuleAvailability)
+	api.PUT("/users/:username/availability/day/:day", h.UpsertDayAvailability)
+	api.DELETE("/users/:username/availability/day/:day", h.DeleteSingleDayAvailability)
+	api.GET("/users/:username/availability/day/history", h.GetDayAvailabilityHistory)
+	api.POST("/users/:username/availability/copy", h.CopyAvailability)
 	api.GET("/availability", h.GetUserAvailability)
 	api.GET("/availability/overlap", h.GetScheduleOverlap)
+	api.GET("/availability/next-common", h.GetNextCommonSlots)
+
+	api.POST("/bookings", h.CreateBooking)
+	api.GET("/bookings", h.GetBookings)
+	api.POST("/bookings/:token/cancel", h.CancelBooking)
+	api.POST("/bookings/:token/reschedule", h.RescheduleBooking)
+
+	api.POST("/users/:username/event-types", h.CreateEventType)
+	api.GET("/users/:username/event-types", h.GetEventTypes)
+	api.GET("/users/:username/event-types/:slug", h.GetEventType)
+	api.PUT("/users/:username/event-types/:slug", h.UpdateEventType)
+	api.DELETE("/users/:username/event-types/:slug", h.DeleteEventType)
+	api.GET("/users/:username/event-types/:slug/slots", h.GetEventTypeSlots)
+
+	api.POST("/users/:username/schedules", h.CreateSchedule)
+	api.GET("/users/:username/schedules", h.GetSchedules)
+	api.GET("/users/:username/schedules/:id", h.GetSchedule)
+	api.PUT("/users/:username/schedules/:id", h.UpdateSchedule)
+	api.DELETE("/users/:username/schedules/:id", h.DeleteSchedule)
+
+	api.POST("/users/:username/time-off", h.CreateTimeOff)
+	api.GET("/users/:username/time-off", h.GetTimeOffs)
+	api.GET("/users/:username/time-off/:id", h.GetTimeOff)
+	api.PUT("/users/:username/time-off/:id", h.UpdateTimeOff)
+	api.DELETE("/users/:username/time-off/:id", h.DeleteTimeOff)
+
+	api.GET("/holidays/regions", h.GetHolidayRegions)
+	api.GET("/holidays", h.GetHolidays)
+
+	api.POST("/users/:username/calendar/token", h.RotateCalendarFeedToken)
+	api.GET("/users/:username/calendar.ics", h.GetCalendarFeed)
+	api.GET("/users/:username/freebusy", h.GetFreeBusy)
+	api.POST("/freebusy", h.QueryFreeBusy)
+
+	api.POST("/users/:username/busy/import", h.ImportBusy)
+	api.GET("/users/:username/busy", h.GetBusyBlocks)
+
+	api.POST("/users/:username/calendars", h.CreateCalendarConnection)
+	api.GET("/users/:username/calendars", h.GetCalendarConnections)
+	api.DELETE("/users/:username/calendars/:id", h.DeleteCalendarConnection)
+	api.POST("/users/:username/calendars/:id/sync", h.SyncCalendarConnection)
+
+	// CalDAV lives next to the REST api, trailing slashes of the collections are optional
+	router.Any("/.well-known/caldav", h.CalDAVWellKnown)
+	dav := router.Group(handlers.CalDAVPrefix)
+	for _, path := range []string{"", "/", "/:username", "/:username/", "/:username/:calendar", "/:username/:calendar/", "/:username/:calendar/:object"} {
+		dav.Match(handlers.CalDAVMethods, path, h.CalDAV)
+	}
+
+	// pull the busy time of the connected calendars in the background
+	go calendarSyncService.Run(ctx, cfg.CalendarSyncInterval)
 
 	slog.Info("$$$ Welcome to your pocket calendar app $$$")
 	// print routes
@@ -90,7 +187,7 @@ func customHTTPErrorHandler(err error, c echo.Context) {
 		if errors.Is(err, sql.ErrNoRows) || errors.Is(v.Internal, sql.ErrNoRows) {
 			code = http.StatusNotFound
 			message = "Record not found"
-		} else if pgErr, ok := v.Internal.(pgdriver.Error); ok {
+		} else if pgErr, ok := v.Internal.(pgdriver.Error); ok && v.Code == http.StatusInternalServerError {
 			code = http.StatusBadRequest
 			message = pgErr.Field('D')
 		} else {
@@ -0,0 +1,33 @@
+-- migrate:up
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE TYPE booking_status_enum AS ENUM (
+    'confirmed',
+    'cancelled'
+);
+
+CREATE TABLE bookings (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE, -- host of the booking
+    invitee_name VARCHAR(255) NOT NULL,
+    invitee_email VARCHAR(255) NOT NULL,
+    start_time TIMESTAMPTZ NOT NULL,
+    end_time TIMESTAMPTZ NOT NULL,
+    status booking_status_enum NOT NULL DEFAULT 'confirmed',
+    notes TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (start_time < end_time),
+    -- confirmed bookings of a host can never overlap, the db is the source of truth for
+    -- double booking since two concurrent requests can both pass the availability check
+    CONSTRAINT bookings_no_overlap EXCLUDE USING gist (
+        user_id WITH =,
+        tstzrange(start_time, end_time) WITH &&
+    ) WHERE (status = 'confirmed')
+);
+
+CREATE INDEX bookings_user_id_start_time_idx ON bookings (user_id, start_time);
+
+-- migrate:down
+DROP TABLE IF EXISTS bookings;
+DROP TYPE IF EXISTS booking_status_enum;
@@ -0,0 +1,17 @@
+-- migrate:up
+CREATE TABLE event_types (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE, -- host of the event type
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(255) NOT NULL,
+    description TEXT,
+    duration INT NOT NULL, -- length of the meeting in minutes
+    slot_increment INT NOT NULL, -- minutes between two consecutive bookable start times
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (duration > 0 AND slot_increment > 0),
+    UNIQUE (user_id, slug)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS event_types;
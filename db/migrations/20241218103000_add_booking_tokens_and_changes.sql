@@ -0,0 +1,28 @@
+-- migrate:up
+-- only the sha256 of the invitee's cancel/reschedule token is stored, the token itself is returned once on booking
+ALTER TABLE bookings ADD COLUMN token_hash VARCHAR(64) UNIQUE;
+
+CREATE TYPE booking_change_action_enum AS ENUM (
+    'cancelled',
+    'rescheduled'
+);
+
+CREATE TABLE booking_changes (
+    id UUID PRIMARY KEY,
+    booking_id UUID NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    action booking_change_action_enum NOT NULL,
+    previous_start_time TIMESTAMPTZ NOT NULL,
+    previous_end_time TIMESTAMPTZ NOT NULL,
+    start_time TIMESTAMPTZ, -- new time when rescheduled
+    end_time TIMESTAMPTZ,
+    reason TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX booking_changes_booking_id_idx ON booking_changes (booking_id);
+
+-- migrate:down
+DROP TABLE IF EXISTS booking_changes;
+DROP TYPE IF EXISTS booking_change_action_enum;
+ALTER TABLE bookings DROP COLUMN IF EXISTS token_hash;
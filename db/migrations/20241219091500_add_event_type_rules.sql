@@ -0,0 +1,18 @@
+-- migrate:up
+ALTER TABLE event_types
+    ADD COLUMN min_notice INT NOT NULL DEFAULT 0, -- minutes from now before which nothing can be booked
+    ADD COLUMN booking_horizon INT NOT NULL DEFAULT 0, -- days from now after which nothing can be booked, 0 means no limit
+    ADD COLUMN buffer_before INT NOT NULL DEFAULT 0, -- minutes that have to be free before the meeting
+    ADD COLUMN buffer_after INT NOT NULL DEFAULT 0, -- minutes that have to be free after the meeting
+    ADD CONSTRAINT event_types_rules_check CHECK (min_notice >= 0 AND booking_horizon >= 0 AND buffer_before >= 0 AND buffer_after >= 0);
+
+ALTER TABLE bookings ADD COLUMN event_type_id UUID REFERENCES event_types(id) ON DELETE SET NULL;
+
+-- migrate:down
+ALTER TABLE bookings DROP COLUMN IF EXISTS event_type_id;
+ALTER TABLE event_types
+    DROP CONSTRAINT IF EXISTS event_types_rules_check,
+    DROP COLUMN IF EXISTS min_notice,
+    DROP COLUMN IF EXISTS booking_horizon,
+    DROP COLUMN IF EXISTS buffer_before,
+    DROP COLUMN IF EXISTS buffer_after;
@@ -0,0 +1,20 @@
+-- migrate:up
+ALTER TABLE users
+    ADD COLUMN daily_booking_cap INT NOT NULL DEFAULT 0, -- max confirmed bookings per day (in the user's timezone), 0 means no cap
+    ADD COLUMN weekly_booking_cap INT NOT NULL DEFAULT 0, -- max confirmed bookings per week (monday to sunday), 0 means no cap
+    ADD CONSTRAINT users_booking_caps_check CHECK (daily_booking_cap >= 0 AND weekly_booking_cap >= 0);
+
+ALTER TABLE event_types
+    ADD COLUMN daily_cap INT NOT NULL DEFAULT 0, -- max confirmed bookings of this event type per day, 0 means no cap
+    ADD COLUMN weekly_cap INT NOT NULL DEFAULT 0, -- max confirmed bookings of this event type per week, 0 means no cap
+    ADD CONSTRAINT event_types_caps_check CHECK (daily_cap >= 0 AND weekly_cap >= 0);
+
+-- migrate:down
+ALTER TABLE event_types
+    DROP CONSTRAINT IF EXISTS event_types_caps_check,
+    DROP COLUMN IF EXISTS daily_cap,
+    DROP COLUMN IF EXISTS weekly_cap;
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_booking_caps_check,
+    DROP COLUMN IF EXISTS daily_booking_cap,
+    DROP COLUMN IF EXISTS weekly_booking_cap;
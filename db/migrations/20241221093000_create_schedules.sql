@@ -0,0 +1,51 @@
+-- migrate:up
+CREATE TABLE schedules (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, name)
+);
+
+-- a user has at most one default schedule
+CREATE UNIQUE INDEX schedules_user_id_default_idx ON schedules (user_id) WHERE is_default;
+
+-- existing availability becomes the default "Working hours" schedule of every user
+INSERT INTO schedules (id, user_id, name, is_default)
+SELECT gen_random_uuid(), id, 'Working hours', TRUE FROM users;
+
+ALTER TABLE day_availabilities ADD COLUMN schedule_id UUID REFERENCES schedules(id) ON DELETE CASCADE;
+UPDATE day_availabilities d SET schedule_id = s.id FROM schedules s WHERE s.user_id = d.user_id AND s.is_default;
+ALTER TABLE day_availabilities
+    ALTER COLUMN schedule_id SET NOT NULL,
+    DROP CONSTRAINT day_availabilities_user_id_day_key,
+    ADD CONSTRAINT day_availabilities_schedule_id_day_key UNIQUE (schedule_id, day);
+
+ALTER TABLE date_availabilities ADD COLUMN schedule_id UUID REFERENCES schedules(id) ON DELETE CASCADE;
+UPDATE date_availabilities d SET schedule_id = s.id FROM schedules s WHERE s.user_id = d.user_id AND s.is_default;
+ALTER TABLE date_availabilities
+    ALTER COLUMN schedule_id SET NOT NULL,
+    DROP CONSTRAINT date_availabilities_user_id_date_key,
+    ADD CONSTRAINT date_availabilities_schedule_id_date_key UNIQUE (schedule_id, date);
+
+ALTER TABLE event_types ADD COLUMN schedule_id UUID REFERENCES schedules(id) ON DELETE SET NULL; -- NULL means the user's default schedule
+
+-- migrate:down
+ALTER TABLE event_types DROP COLUMN IF EXISTS schedule_id;
+
+-- only the default schedule fits in the old one schedule per user tables
+DELETE FROM date_availabilities d USING schedules s WHERE s.id = d.schedule_id AND NOT s.is_default;
+ALTER TABLE date_availabilities
+    DROP CONSTRAINT IF EXISTS date_availabilities_schedule_id_date_key,
+    DROP COLUMN IF EXISTS schedule_id,
+    ADD CONSTRAINT date_availabilities_user_id_date_key UNIQUE (user_id, date);
+
+DELETE FROM day_availabilities d USING schedules s WHERE s.id = d.schedule_id AND NOT s.is_default;
+ALTER TABLE day_availabilities
+    DROP CONSTRAINT IF EXISTS day_availabilities_schedule_id_day_key,
+    DROP COLUMN IF EXISTS schedule_id,
+    ADD CONSTRAINT day_availabilities_user_id_day_key UNIQUE (user_id, day);
+
+DROP TABLE IF EXISTS schedules;
@@ -0,0 +1,17 @@
+-- migrate:up
+CREATE TABLE rule_availabilities (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    schedule_id UUID NOT NULL REFERENCES schedules(id) ON DELETE CASCADE,
+    rrule TEXT NOT NULL, -- iCalendar RRULE without DTSTART, e.g. FREQ=WEEKLY;INTERVAL=2;BYDAY=FR
+    start_date DATE NOT NULL, -- DTSTART of the rule, date in the user's timezone
+    exdates DATE[] NOT NULL DEFAULT '{}', -- EXDATEs, dates the rule doesn't occur on
+    slots JSONB NOT NULL, -- same as slots in day_availabilities
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX rule_availabilities_schedule_id_idx ON rule_availabilities (schedule_id);
+
+-- migrate:down
+DROP TABLE IF EXISTS rule_availabilities;
@@ -0,0 +1,19 @@
+-- migrate:up
+CREATE TYPE override_mode_enum AS ENUM (
+    'replace', -- slots replace the availability of the date
+    'add', -- slots are added to the availability of the date
+    'block' -- slots are removed from the availability of the date
+);
+
+ALTER TABLE date_availabilities
+    ADD COLUMN mode override_mode_enum NOT NULL DEFAULT 'replace',
+    DROP CONSTRAINT date_availabilities_schedule_id_date_key,
+    ADD CONSTRAINT date_availabilities_schedule_id_date_mode_key UNIQUE (schedule_id, date, mode);
+
+-- migrate:down
+DELETE FROM date_availabilities WHERE mode != 'replace';
+ALTER TABLE date_availabilities
+    DROP CONSTRAINT IF EXISTS date_availabilities_schedule_id_date_mode_key,
+    DROP COLUMN IF EXISTS mode,
+    ADD CONSTRAINT date_availabilities_schedule_id_date_key UNIQUE (schedule_id, date);
+DROP TYPE IF EXISTS override_mode_enum;
@@ -0,0 +1,23 @@
+-- migrate:up
+CREATE TABLE time_offs (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    start_date DATE NOT NULL, -- dates in the user's timezone
+    end_date DATE NOT NULL,
+    start_minute INT NOT NULL DEFAULT 0, -- minutes since midnight of start_date the time off starts at, 0 is the whole day
+    end_minute INT NOT NULL DEFAULT 1440, -- minutes since midnight of end_date the time off ends at, 1440 is the whole day
+    reason TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT time_offs_range_check CHECK (
+        start_date <= end_date
+        AND start_minute BETWEEN 0 AND 1439
+        AND end_minute BETWEEN 1 AND 1440
+        AND (start_date < end_date OR start_minute < end_minute)
+    )
+);
+
+CREATE INDEX time_offs_user_id_dates_idx ON time_offs (user_id, start_date, end_date);
+
+-- migrate:down
+DROP TABLE IF EXISTS time_offs;
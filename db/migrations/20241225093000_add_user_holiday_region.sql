@@ -0,0 +1,7 @@
+-- migrate:up
+ALTER TABLE users
+    ADD COLUMN holiday_region VARCHAR(16) NOT NULL DEFAULT ''; -- region of the embedded holiday dataset (e.g. DE-BY), its public holidays block availability, empty means none
+
+-- migrate:down
+ALTER TABLE users
+    DROP COLUMN IF EXISTS holiday_region;
@@ -0,0 +1,22 @@
+-- migrate:up
+-- weekly hours are versioned, a version is the set of day availabilities of a schedule with the same effective_from
+-- and is valid from effective_from until effective_to (excluded, NULL means open ended)
+ALTER TABLE day_availabilities
+    ADD COLUMN effective_from DATE NOT NULL DEFAULT DATE '1970-01-01', -- existing weekly hours have always been in effect
+    ADD COLUMN effective_to DATE,
+    ADD CONSTRAINT day_availabilities_effective_dates_check CHECK (effective_to IS NULL OR effective_to > effective_from),
+    DROP CONSTRAINT day_availabilities_schedule_id_day_key,
+    ADD CONSTRAINT day_availabilities_schedule_id_day_effective_from_key UNIQUE (schedule_id, day, effective_from);
+
+ALTER TABLE day_availabilities ALTER COLUMN effective_from DROP DEFAULT;
+
+-- migrate:down
+-- only the versions in effect today fit in the old one version per schedule table
+DELETE FROM day_availabilities
+WHERE effective_from > CURRENT_DATE OR (effective_to IS NOT NULL AND effective_to <= CURRENT_DATE);
+ALTER TABLE day_availabilities
+    DROP CONSTRAINT IF EXISTS day_availabilities_schedule_id_day_effective_from_key,
+    DROP CONSTRAINT IF EXISTS day_availabilities_effective_dates_check,
+    DROP COLUMN IF EXISTS effective_to,
+    DROP COLUMN IF EXISTS effective_from,
+    ADD CONSTRAINT day_availabilities_schedule_id_day_key UNIQUE (schedule_id, day);
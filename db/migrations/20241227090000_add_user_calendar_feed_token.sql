@@ -0,0 +1,7 @@
+-- migrate:up
+ALTER TABLE users
+    ADD COLUMN calendar_feed_token_hash VARCHAR(64); -- sha256 of the secret token of the user's iCalendar feed, null when no feed token was issued
+
+-- migrate:down
+ALTER TABLE users
+    DROP COLUMN IF EXISTS calendar_feed_token_hash;
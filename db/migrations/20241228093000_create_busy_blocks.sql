@@ -0,0 +1,19 @@
+-- migrate:up
+CREATE TABLE busy_blocks (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source VARCHAR(255) NOT NULL, -- where the block comes from, e.g. 'import' for uploaded .ics files
+    uid VARCHAR(1024) NOT NULL, -- UID of the calendar event, every occurrence of a recurring event is a block with the same UID
+    summary TEXT,
+    start_time TIMESTAMPTZ NOT NULL,
+    end_time TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (start_time < end_time)
+);
+
+CREATE INDEX busy_blocks_user_id_start_time_idx ON busy_blocks (user_id, start_time);
+CREATE INDEX busy_blocks_user_id_source_uid_idx ON busy_blocks (user_id, source, uid);
+
+-- migrate:down
+DROP TABLE IF EXISTS busy_blocks;
@@ -0,0 +1,28 @@
+-- migrate:up
+CREATE TABLE calendar_connections (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(64) NOT NULL, -- name of the calendar provider, e.g. 'fake'
+    name VARCHAR(255) NOT NULL,
+    location TEXT NOT NULL, -- provider specific address of the calendar, e.g. the file or url of the fake provider
+    sync_token TEXT, -- token of the provider to only pull the changes since the last sync, null before the first sync
+    last_synced_at TIMESTAMPTZ,
+    full_synced_at TIMESTAMPTZ, -- last sync that pulled every event, recurring events are expanded again from then on
+    last_error TEXT, -- error of the last sync, null when it succeeded
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX calendar_connections_user_id_idx ON calendar_connections (user_id);
+
+-- busy blocks pulled from a connected calendar go away with the connection
+ALTER TABLE busy_blocks
+    ADD COLUMN connection_id UUID REFERENCES calendar_connections(id) ON DELETE CASCADE;
+
+CREATE INDEX busy_blocks_connection_id_uid_idx ON busy_blocks (connection_id, uid);
+
+-- migrate:down
+ALTER TABLE busy_blocks
+    DROP COLUMN IF EXISTS connection_id;
+
+DROP TABLE IF EXISTS calendar_connections;
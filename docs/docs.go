@@ -17,7 +17,7 @@ const docTemplate = `{
     "paths": {
         "/availability": {
             "get": {
-                "description": "handles the retrieval of overall user availability across a range of dates, takes both day/date into account",
+                "description": "handles the retrieval of overall user availability across a range of dates, takes both day/date into account\nuser's slots are interpreted in their own timezone, dates and slots of the response are in ` + "`" + `tz` + "`" + ` (UTC by default)\nslots come from the given ` + "`" + `schedule` + "`" + ` (ID), the user's default schedule otherwise",
                 "consumes": [
                     "application/json"
                 ],
@@ -36,6 +36,12 @@ const docTemplate = `{
                         "in": "query",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "Schedule ID",
+                        "name": "schedule",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "default": "2024-12-15",
@@ -51,6 +57,13 @@ const docTemplate = `{
                         "name": "endDate",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "UTC",
+                        "description": "IANA timezone of the response",
+                        "name": "tz",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -92,7 +105,7 @@ const docTemplate = `{
         },
         "/availability/date": {
             "post": {
-                "description": "handles the creation of date-specific availability\nevery request overrides the existing availability for that date\ndate availability ALWAYS overrides the day availability",
+                "description": "handles the creation of date-specific availability\nevery request overrides the existing date availability of the same mode for that date\n` + "`" + `replace` + "`" + ` (default) date availability ALWAYS overrides the day availability of the same schedule (the user's default one when ` + "`" + `schedule_id` + "`" + ` is not given)\n` + "`" + `add` + "`" + ` slots are added to the availability of the date and ` + "`" + `block` + "`" + ` slots are removed from it (e.g. out 2-3pm next tuesday)\ndate and slots are interpreted in the user's timezone, slots are normalized like day availability",
                 "consumes": [
                     "application/json"
                 ],
@@ -118,7 +131,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/DateAvailability"
+                            "$ref": "#/definitions/DateAvailabilityResponse"
                         }
                     },
                     "400": {
@@ -148,7 +161,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "handles the deletion of date-based availability",
+                "description": "handles the deletion of date-based availability of the schedule, all dates when ` + "`" + `date` + "`" + ` is not given and all modes when ` + "`" + `mode` + "`" + ` is not given",
                 "consumes": [
                     "application/json"
                 ],
@@ -201,9 +214,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/availability/day": {
+        "/availability/date/range": {
             "post": {
-                "description": "handles the creation of day-based availability\nevery request overrides the existing availability for all days\nif day is not provided, no availability is created for that day",
+                "description": "handles the creation of the same date-specific availability on every date from ` + "`" + `start_date` + "`" + ` to ` + "`" + `end_date` + "`" + ` (both inclusive, at most a year)\nonly on the given ` + "`" + `days` + "`" + ` of the week when not empty (e.g. holiday week hours on weekdays only)\nevery date behaves like a date availability of the given mode, all of them are created in one transaction",
                 "consumes": [
                     "application/json"
                 ],
@@ -213,15 +226,15 @@ const docTemplate = `{
                 "tags": [
                     "availability"
                 ],
-                "summary": "Create day availability",
+                "summary": "Create date availability over a range",
                 "parameters": [
                     {
-                        "description": "DayAvailabilityRequest",
+                        "description": "CreateDateRangeAvailabilityRequest",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/CreateDayAvailabilityRequest"
+                            "$ref": "#/definitions/CreateDateRangeAvailabilityRequest"
                         }
                     }
                 ],
@@ -229,10 +242,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/DayAvailability"
-                            }
+                            "$ref": "#/definitions/DateRangeAvailabilityResponse"
                         }
                     },
                     "400": {
@@ -262,7 +272,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "handles the deletion of day-based availability (` + "`" + `date` + "`" + ` param is ignored)",
+                "description": "handles the deletion of the date-based availability of the schedule from ` + "`" + `start_date` + "`" + ` to ` + "`" + `end_date` + "`" + ` (both inclusive, at most a year)\nall modes when ` + "`" + `mode` + "`" + ` is not given, the whole range is deleted in one transaction",
                 "consumes": [
                     "application/json"
                 ],
@@ -272,15 +282,15 @@ const docTemplate = `{
                 "tags": [
                     "availability"
                 ],
-                "summary": "Delete day availability",
+                "summary": "Delete date availability over a range",
                 "parameters": [
                     {
-                        "description": "DeleteUserAvailabilityRequest",
+                        "description": "DeleteDateRangeAvailabilityRequest",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/DeleteUserAvailabilityRequest"
+                            "$ref": "#/definitions/DeleteDateRangeAvailabilityRequest"
                         }
                     }
                 ],
@@ -315,9 +325,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/availability/overlap": {
-            "get": {
-                "description": "handles the retrieval of schedule overlap between two users",
+        "/availability/day": {
+            "post": {
+                "description": "handles the creation of day-based availability\nevery request overrides the existing availability for all days of the schedule (the user's default one when ` + "`" + `schedule_id` + "`" + ` is not given)\nfrom ` + "`" + `effective_from` + "`" + ` on (today by default), the weekly hours applying before are kept and a version starting later still applies from its own date\nif day is not provided, no availability is created for that day\nslots are sorted and overlapping/adjacent ones merged (a day given twice gets the slots of both), ` + "`" + `warnings` + "`" + ` describe every merge",
                 "consumes": [
                     "application/json"
                 ],
@@ -327,47 +337,23 @@ const docTemplate = `{
                 "tags": [
                     "availability"
                 ],
-                "summary": "Get schedule overlap",
+                "summary": "Create day availability",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "First Username",
-                        "name": "firstUsername",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Second Username",
-                        "name": "secondUsername",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "default": "2024-12-15",
-                        "description": "Start Date",
-                        "name": "startDate",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "default": "2024-12-15",
-                        "description": "End Date",
-                        "name": "endDate",
-                        "in": "query",
-                        "required": true
+                        "description": "DayAvailabilityRequest",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreateDayAvailabilityRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/UserDateAvailability"
-                            }
+                            "$ref": "#/definitions/DayAvailabilityResponse"
                         }
                     },
                     "400": {
@@ -395,10 +381,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/health": {
-            "get": {
+            },
+            "delete": {
+                "description": "handles the deletion of day-based availability of the schedule from ` + "`" + `date` + "`" + ` on (today by default), the weekly hours applying before are kept",
                 "consumes": [
                     "application/json"
                 ],
@@ -406,38 +391,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "availability"
                 ],
-                "summary": "healthcheck",
-                "responses": {
-                    "200": {
-                        "description": "OK"
+                "summary": "Delete day availability",
+                "parameters": [
+                    {
+                        "description": "DeleteUserAvailabilityRequest",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/DeleteUserAvailabilityRequest"
+                        }
                     }
-                }
-            }
-        },
-        "/users": {
-            "get": {
-                "description": "handles the retrieval of all users",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "user"
                 ],
-                "summary": "Get all users",
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/User"
-                            }
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -464,9 +434,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "handles the creation of a new user",
+            }
+        },
+        "/availability/next-common": {
+            "get": {
+                "description": "searches forward for the earliest windows where all the users are free (as per their default schedules) for at least ` + "`" + `duration` + "`" + ` minutes\nsearch starts at ` + "`" + `startDate` + "`" + ` (today by default, never in the past) and stops after NEXT_COMMON_HORIZON_DAYS days\nusers are given the same way as for /availability/overlap, windows are rendered in ` + "`" + `tz` + "`" + ` (UTC by default)",
                 "consumes": [
                     "application/json"
                 ],
@@ -474,25 +446,54 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "availability"
                 ],
-                "summary": "Create a user",
+                "summary": "Get next common slots",
                 "parameters": [
                     {
-                        "description": "User",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/User"
-                        }
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Usernames",
+                        "name": "usernames",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 30,
+                        "description": "Meeting length in minutes",
+                        "name": "duration",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Number of windows",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start Date",
+                        "name": "startDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "UTC",
+                        "description": "IANA timezone of the response",
+                        "name": "tz",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/User"
+                            "$ref": "#/definitions/NextCommonSlots"
                         }
                     },
                     "400": {
@@ -522,9 +523,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}": {
+        "/availability/overlap": {
             "get": {
-                "description": "handles the retrieval of a user by ID",
+                "description": "handles the retrieval of schedule overlap between 2 to 10 users, as per their default schedules\nusers are given as ` + "`" + `usernames` + "`" + ` (repeated or comma separated), ` + "`" + `firstUsername` + "`" + `/` + "`" + `secondUsername` + "`" + ` are still supported\nwith ` + "`" + `quorum` + "`" + ` (defaults to all users) windows where at least that many users are free are returned, each window lists who is free\nwindows shorter than ` + "`" + `minDuration` + "`" + ` minutes are left out, a window continuing past midnight counts as one\ndates and slots of the response are in ` + "`" + `tz` + "`" + ` (UTC by default), from ` + "`" + `startDate` + "`" + ` to ` + "`" + `endDate` + "`" + ` (both inclusive, at most a year)",
                 "consumes": [
                     "application/json"
                 ],
@@ -532,23 +533,73 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "availability"
                 ],
-                "summary": "Get a user by ID",
+                "summary": "Get schedule overlap",
                 "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Usernames",
+                        "name": "usernames",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "First Username",
+                        "name": "firstUsername",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Second Username",
+                        "name": "secondUsername",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Min number of free users",
+                        "name": "quorum",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Min length of a window in minutes",
+                        "name": "minDuration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "2024-12-15",
+                        "description": "Start Date",
+                        "name": "startDate",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "2024-12-15",
+                        "description": "End Date",
+                        "name": "endDate",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "UTC",
+                        "description": "IANA timezone of the response",
+                        "name": "tz",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/User"
+                            "$ref": "#/definitions/ScheduleOverlap"
                         }
                     },
                     "400": {
@@ -576,9 +627,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "handles the update of a user",
+            }
+        },
+        "/availability/rule": {
+            "post": {
+                "description": "handles the creation of recurring availability given as an iCalendar RRULE (e.g. every other friday, first monday of the month)\nthe rule starts at ` + "`" + `start_date` + "`" + ` (DTSTART) and skips ` + "`" + `exdates` + "`" + ` (EXDATE), dates and slots are interpreted in the user's timezone\non dates a rule occurs on its slots replace the day availability, date availability still overrides both\nslots are normalized like day availability",
                 "consumes": [
                     "application/json"
                 ],
@@ -586,32 +639,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "availability"
                 ],
-                "summary": "Update a user",
+                "summary": "Create rule availability",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "UpdateUserRequest",
+                        "description": "RuleAvailabilityRequest",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/UpdateUserRequest"
+                            "$ref": "#/definitions/CreateRuleAvailabilityRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/User"
+                            "$ref": "#/definitions/RuleAvailabilityResponse"
                         }
                     },
                     "400": {
@@ -641,7 +687,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "handles the deletion of a user",
+                "description": "handles the deletion of a recurring availability rule",
                 "consumes": [
                     "application/json"
                 ],
@@ -649,24 +695,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "availability"
                 ],
-                "summary": "Delete a user",
+                "summary": "Delete rule availability",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "DeleteRuleAvailabilityRequest",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/DeleteRuleAvailabilityRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "204": {
-                        "description": "No Content",
-                        "schema": {
-                            "$ref": "#/definitions/Response"
-                        }
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -694,92 +739,3744 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "CreateDateAvailabilityRequest": {
-            "type": "object",
-            "required": [
-                "date",
-                "slots",
-                "username"
-            ],
-            "properties": {
-                "date": {
-                    "type": "string",
-                    "example": "2024-12-15T00:00:00Z"
-                },
-                "slots": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/Slot"
-                    }
-                },
+        },
+        "/bookings": {
+            "get": {
+                "description": "handles the retrieval of the host's bookings overlapping with the given range of dates\nevery booking comes with its history of cancellations and reschedules",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "booking"
+                ],
+                "summary": "Get bookings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "2024-12-15",
+                        "description": "Start Date",
+                        "name": "startDate",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "2024-12-15",
+                        "description": "End Date",
+                        "name": "endDate",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "confirmed",
+                            "cancelled"
+                        ],
+                        "type": "string",
+                        "description": "Status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Booking"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "handles the booking of a meeting on the host's calendar\nrequested time must be fully inside the host's availability and not overlap any confirmed booking\nwith ` + "`" + `event_type` + "`" + ` only ` + "`" + `start_time` + "`" + ` is needed, the end comes from the event's duration and its rules (min notice, horizon, buffers) apply\nthe response has a ` + "`" + `token` + "`" + ` the invitee can use to cancel or reschedule, it's never returned again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "booking"
+                ],
+                "summary": "Create a booking",
+                "parameters": [
+                    {
+                        "description": "CreateBookingRequest",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreateBookingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/Booking"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings/{token}/cancel": {
+            "post": {
+                "description": "handles the cancellation of a booking by the invitee using the token returned on booking\nthe original time and the reason are kept in the booking's history",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "booking"
+                ],
+                "summary": "Cancel a booking",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Booking token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "CancelBookingRequest",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CancelBookingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Booking"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings/{token}/reschedule": {
+            "post": {
+                "description": "handles moving a booking to a new start time by the invitee using the token returned on booking\nbooking keeps its duration, the new time must be inside the host's availability\nthe original time and the reason are kept in the booking's history",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "booking"
+                ],
+                "summary": "Reschedule a booking",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Booking token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "RescheduleBookingRequest",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/RescheduleBookingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Booking"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/freebusy": {
+            "post": {
+                "description": "handles an iTIP free/busy request (RFC 5546): the body is an iCalendar object with METHOD:REQUEST and a VFREEBUSY giving DTSTART, DTEND and an ATTENDEE per user\nattendees are users found by the (case insensitive) email of their ` + "`" + `mailto:` + "`" + ` address, the REPLY has a VFREEBUSY per attendee, unknown ones are busy for the whole range",
+                "consumes": [
+                    "text/calendar"
+                ],
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "calendar"
+                ],
+                "summary": "Query free/busy time",
+                "parameters": [
+                    {
+                        "description": "VFREEBUSY request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "iCalendar object",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "healthcheck",
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/holidays": {
+            "get": {
+                "description": "handles the retrieval of the public holidays of a region between given dates, a holiday moved off a weekend is listed on both dates",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "holiday"
+                ],
+                "summary": "Get holidays",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "US",
+                        "description": "Region",
+                        "name": "region",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "2024-12-01",
+                        "description": "Start Date",
+                        "name": "startDate",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "2024-12-31",
+                        "description": "End Date",
+                        "name": "endDate",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Holiday"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/holidays/regions": {
+            "get": {
+                "description": "handles the retrieval of the regions of the embedded holiday dataset, usable as a user's ` + "`" + `holiday_region` + "`" + `",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "holiday"
+                ],
+                "summary": "Get holiday regions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/HolidayRegion"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "description": "handles the retrieval of all users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get all users",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/User"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "handles the creation of a new user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Create a user",
+                "parameters": [
+                    {
+                        "description": "User",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "description": "handles the retrieval of a user by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get a user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "handles the update of a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Update a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "UpdateUserRequest",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "handles the deletion of a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Delete a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/availability/copy": {
+            "post": {
+                "description": "handles copying the availability of the week (monday to sunday) of ` + "`" + `source_week` + "`" + ` onto the next ` + "`" + `weeks` + "`" + ` weeks of the schedule (the user's default one when ` + "`" + `schedule_id` + "`" + ` is not given)\nevery date of the target weeks gets a ` + "`" + `replace` + "`" + ` date availability with the schedule's slots of the same weekday of the source week (weekly hours, rules and date availabilities)\nbookings, time off and busy time are not copied: they don't leave gaps in the target weeks\ndates that already have date availabilities are skipped (` + "`" + `conflict` + "`" + ` skip, the default) or their date availabilities are replaced (` + "`" + `conflict` + "`" + ` overwrite)\ndates in the past, public holidays of the user's holiday region and dates whose source date is a public holiday are always skipped",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "availability"
+                ],
+                "summary": "Copy a week of availability forward",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "CopyAvailabilityRequest",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CopyAvailabilityRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/CopyAvailabilityResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/availability/day/history": {
+            "get": {
+                "description": "handles the retrieval of every version of the weekly hours of the schedule (the user's default one when ` + "`" + `schedule` + "`" + ` is not given)\npast, in effect and future versions are sorted by ` + "`" + `effective_from` + "`" + `, a version applies until its ` + "`" + `effective_to` + "`" + ` (excluded)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "availability"
+                ],
+                "summary": "Get day availability history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schedule ID",
+                        "name": "schedule",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/WeeklyHoursVersion"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/availability/day/{day}": {
+            "put": {
+                "description": "handles setting the slots of a single day of the week without resending the whole week\nthe slots apply from ` + "`" + `effective_from` + "`" + ` on (today by default), the other days keep the weekly hours in effect then\nslots are normalized like day availability",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "availability"
+                ],
+                "summary": "Set availability of a day",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "monday",
+                            "tuesday",
+                            "wednesday",
+                            "thursday",
+                            "friday",
+                            "saturday",
+                            "sunday"
+                        ],
+                        "type": "string",
+                        "description": "Day of the week",
+                        "name": "day",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "UpsertDayAvailabilityRequest",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpsertDayAvailabilityRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/DayAvailabilityResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "handles the deletion of a single day of the week from the weekly hours of the schedule (the user's default one when ` + "`" + `schedule` + "`" + ` is not given)\nfrom ` + "`" + `date` + "`" + ` on (today by default), the other days keep the weekly hours in effect then",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "availability"
+                ],
+                "summary": "Delete availability of a day",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "monday",
+                            "tuesday",
+                            "wednesday",
+                            "thursday",
+                            "friday",
+                            "saturday",
+                            "sunday"
+                        ],
+                        "type": "string",
+                        "description": "Day of the week",
+                        "name": "day",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schedule ID",
+                        "name": "schedule",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date the day stops to be available",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/busy": {
+            "get": {
+                "description": "handles the retrieval of the user's busy blocks between given dates (of the user's timezone)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "busy"
+                ],
+                "summary": "Get busy blocks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "2024-12-15",
+                        "description": "Start Date",
+                        "name": "startDate",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "2024-12-21",
+                        "description": "End Date",
+                        "name": "endDate",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/BusyBlock"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/busy/import": {
+            "post": {
+                "description": "handles the import of the events of an iCalendar (.ics) file as busy blocks of the user, they are subtracted from availability and overlap\nthe file is sent as the ` + "`" + `file` + "`" + ` field of a multipart form or as the raw request body (text/calendar)\nrecurring events (RRULE, RDATE, EXDATE, RECURRENCE-ID) are expanded from today on for ` + "`" + `BUSY_IMPORT_HORIZON_DAYS` + "`" + ` days, all-day and floating events are in the user's timezone\ntransparent and cancelled events don't block time, importing an event again (same UID) replaces its busy blocks",
+                "consumes": [
+                    "multipart/form-data",
+                    "text/calendar"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "busy"
+                ],
+                "summary": "Import busy time",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "iCalendar file",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/BusyImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/calendar.ics": {
+            "get": {
+                "description": "handles the retrieval of the user's iCalendar (RFC 5545) feed, to subscribe to from any calendar client\nthe free time of the default schedule (as transparent \"Available\" events) and the bookings are listed from today on\nfor ` + "`" + `CALENDAR_FEED_DAYS` + "`" + ` days, in the user's timezone. The ` + "`" + `token` + "`" + ` is the one issued by /users/{username}/calendar/token",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "calendar"
+                ],
+                "summary": "Get calendar feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Feed Token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "iCalendar object",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/calendar/token": {
+            "post": {
+                "description": "handles the creation of a new secret token for the user's iCalendar feed, the previous token stops working\nthe token is only returned once, along with the feed url to subscribe to from a calendar client\nthe request is authorized with ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + `, the current feed token or the ` + "`" + `ADMIN_TOKEN` + "`" + ` (needed for the first token of a user)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "calendar"
+                ],
+                "summary": "Rotate calendar feed token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer current feed token or admin token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/CalendarFeedTokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/calendars": {
+            "get": {
+                "description": "handles the retrieval of the user's connected calendars along with the state of their last sync",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "calendar-connection"
+                ],
+                "summary": "Get calendar connections",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/CalendarConnection"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "handles the connection of an external calendar of the user, its events are synced periodically (every ` + "`" + `CALENDAR_SYNC_INTERVAL` + "`" + `) as busy blocks\nthat are subtracted from availability, the first sync runs right away and its error (if any) is kept in ` + "`" + `last_error` + "`" + `\nthe ` + "`" + `fake` + "`" + ` provider (enabled with ` + "`" + `FAKE_CALENDAR_PROVIDER` + "`" + `) reads a JSON calendar from a file of ` + "`" + `FAKE_CALENDAR_DIR` + "`" + ` or an http(s) url",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "calendar-connection"
+                ],
+                "summary": "Connect a calendar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "CreateCalendarConnectionRequest",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreateCalendarConnectionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/CalendarConnection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/calendars/{id}": {
+            "delete": {
+                "description": "handles the deletion of a calendar connection, the busy blocks synced from it are deleted as well",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "calendar-connection"
+                ],
+                "summary": "Disconnect a calendar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Calendar connection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/calendars/{id}/sync": {
+            "post": {
+                "description": "handles an immediate sync of a connected calendar: the events changed or deleted since the last sync replace their busy blocks\nevery event is pulled again once a day or when the provider expired the sync token (` + "`" + `full` + "`" + `)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "calendar-connection"
+                ],
+                "summary": "Sync a calendar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Calendar connection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CalendarSyncResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/event-types": {
+            "get": {
+                "description": "handles the retrieval of all event types of the user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "event-type"
+                ],
+                "summary": "Get event types",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/EventType"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "handles the creation of an event type (e.g. \"30-min intro\") for the user\nslug has to be unique per user, slot_increment defaults to 15 minutes\nthe slot_increment grid, min_notice, booking_horizon and buffer_before/after are applied to the bookable slots and enforced on booking\ndaily_cap/weekly_cap limit the confirmed bookings of the event type per day/week, 0 means no cap\nslots come from the schedule ` + "`" + `schedule_id` + "`" + `, the user's default schedule when not given",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "event-type"
+                ],
+                "summary": "Create event type",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "CreateEventTypeRequest",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreateEventTypeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/EventType"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/event-types/{slug}": {
+            "get": {
+                "description": "handles the retrieval of an event type by slug",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "event-type"
+                ],
+                "summary": "Get event type",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event type slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/EventType"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "handles the update of an event type, only the provided fields are updated\na nil UUID ` + "`" + `schedule_id` + "`" + ` switches the event type back to the user's default schedule",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "event-type"
+                ],
+                "summary": "Update event type",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event type slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "UpdateEventTypeRequest",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpdateEventTypeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/EventType"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "handles the deletion of an event type",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "event-type"
+                ],
+                "summary": "Delete event type",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event type slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/event-types/{slug}/slots": {
+            "get": {
+                "description": "handles the retrieval of bookable start times of an event type between the given dates\nstart times are generated from the host's availability every ` + "`" + `slot_increment` + "`" + ` minutes, each fits a full meeting\nstart times breaking the event's min notice, booking horizon or buffers around other bookings are left out\ndays and weeks (in the host's timezone) where the host's or the event's booking caps are reached have no start times",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "event-type"
+                ],
+                "summary": "Get bookable slots of an event type",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event type slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "2024-12-15",
+                        "description": "Start Date",
+                        "name": "startDate",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "2024-12-15",
+                        "description": "End Date",
+                        "name": "endDate",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "UTC",
+                        "description": "IANA timezone of the response",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/EventTypeSlots"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/freebusy": {
+            "get": {
+                "description": "handles the retrieval of the user's free and busy time between given dates (of the user's timezone) as an iCalendar VFREEBUSY (RFC 5545)\nfree periods are the availability of the default schedule, the rest of the range is busy, periods are in UTC",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "calendar"
+                ],
+                "summary": "Get free/busy time",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "2024-12-15",
+                        "description": "Start Date",
+                        "name": "startDate",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "2024-12-21",
+                        "description": "End Date",
+                        "name": "endDate",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "iCalendar object",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/schedules": {
+            "get": {
+                "description": "handles the retrieval of all schedules of the user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Get schedules",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Schedule"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "handles the creation of a named availability schedule (e.g. \"Evening support\") for the user\nname has to be unique per user, the user's first schedule is always the default one\nsetting ` + "`" + `is_default` + "`" + ` makes it the default schedule in place of the previous one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Create schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "CreateScheduleRequest",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreateScheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/Schedule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/schedules/{id}": {
+            "get": {
+                "description": "handles the retrieval of a schedule along with its day availabilities, date overrides and recurring rules",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Get schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Schedule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "handles the renaming of a schedule or making it the default one, only the provided fields are updated",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Update schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "UpdateScheduleRequest",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpdateScheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Schedule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "handles the deletion of a schedule along with its availability, event types using it fall back to the default schedule\nthe default schedule can't be deleted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Delete schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/time-off": {
+            "get": {
+                "description": "handles the retrieval of all time offs of the user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "time-off"
+                ],
+                "summary": "Get time offs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/TimeOff"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "handles the creation of an out-of-office range (e.g. a vacation) for the user, the user is unavailable during it whatever their schedules say\ndates are interpreted in the user's timezone, ` + "`" + `start_minute` + "`" + `/` + "`" + `end_minute` + "`" + ` make the first/last day partial",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "time-off"
+                ],
+                "summary": "Create time off",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "CreateTimeOffRequest",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreateTimeOffRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/TimeOff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/time-off/{id}": {
+            "get": {
+                "description": "handles the retrieval of a time off by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "time-off"
+                ],
+                "summary": "Get time off",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Time off ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/TimeOff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "handles the update of a time off, only the provided fields are updated",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "time-off"
+                ],
+                "summary": "Update time off",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Time off ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "UpdateTimeOffRequest",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpdateTimeOffRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/TimeOff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "handles the deletion of a time off",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "time-off"
+                ],
+                "summary": "Delete time off",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Time off ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "Booking": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/BookingChange"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "event_type_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "invitee_email": {
+                    "type": "string"
+                },
+                "invitee_name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/github_com_niharika88_calendly-api_internal_db_models.BookingStatus"
+                },
+                "token": {
+                    "description": "Token lets the invitee cancel or reschedule without an account, it's only returned when the booking is created",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "host of the booking",
+                    "type": "string"
+                }
+            }
+        },
+        "BookingChange": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/github_com_niharika88_calendly-api_internal_db_models.BookingChangeAction"
+                },
+                "booking_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "previous_end_time": {
+                    "type": "string"
+                },
+                "previous_start_time": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "start_time": {
+                    "description": "new time when rescheduled",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "BusyBlock": {
+            "type": "object",
+            "properties": {
+                "connection_id": {
+                    "description": "Calendar connection the block was synced from, nil for imported ones",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string",
+                    "example": "import"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "summary": {
+                    "type": "string",
+                    "example": "Team sync"
+                },
+                "uid": {
+                    "type": "string",
+                    "example": "040000008200E00074C5B7101A82E008@example.com"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "BusyImportResponse": {
+            "type": "object",
+            "properties": {
+                "blocks": {
+                    "type": "integer",
+                    "example": 87
+                },
+                "events": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "CalendarConnection": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "full_synced_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "description": "Error of the last sync, empty when it succeeded",
+                    "type": "string"
+                },
+                "last_synced_at": {
+                    "type": "string"
+                },
+                "location": {
+                    "description": "Provider specific address of the calendar, e.g. a file or url",
+                    "type": "string",
+                    "example": "work.json"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Work calendar"
+                },
+                "provider": {
+                    "type": "string",
+                    "example": "fake"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "CalendarFeedTokenResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "example": "q3v0bC1hcGktZmVlZC10b2tlbi1leGFtcGxl"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://calendly.example/api/users/jdoe/calendar.ics?token=q3v0bC1hcGktZmVlZC10b2tlbi1leGFtcGxl"
+                }
+            }
+        },
+        "CalendarSyncResponse": {
+            "type": "object",
+            "properties": {
+                "blocks": {
+                    "type": "integer",
+                    "example": 14
+                },
+                "changed": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "deleted": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "full": {
+                    "type": "boolean"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "CancelBookingRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "something came up"
+                }
+            }
+        },
+        "CopyAvailabilityRequest": {
+            "type": "object",
+            "required": [
+                "source_week",
+                "weeks"
+            ],
+            "properties": {
+                "conflict": {
+                    "description": "skip (default) or overwrite",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_niharika88_calendly-api_pkg_api.CopyConflictPolicy"
+                        }
+                    ],
+                    "example": "skip"
+                },
+                "schedule_id": {
+                    "description": "defaults to the user's default schedule",
+                    "type": "string"
+                },
+                "source_week": {
+                    "description": "any date of the week",
+                    "type": "string",
+                    "example": "2024-12-16T00:00:00Z"
+                },
+                "weeks": {
+                    "description": "number of weeks after the source week",
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "CopyAvailabilityResponse": {
+            "type": "object",
+            "properties": {
+                "copied": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/DateAvailability"
+                    }
+                },
+                "skipped": {
+                    "description": "dates with date availabilities already (skip policy), in the past or public holidays (target or source date)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "2024-12-24T00:00:00Z"
+                    ]
+                }
+            }
+        },
+        "CreateBookingRequest": {
+            "type": "object",
+            "required": [
+                "invitee_email",
+                "invitee_name",
+                "start_time",
+                "username"
+            ],
+            "properties": {
+                "end_time": {
+                    "description": "required without an event type",
+                    "type": "string",
+                    "example": "2024-12-15T10:30:00Z"
+                },
+                "event_type": {
+                    "description": "slug of the host's event type, its rules apply and end time is derived from its duration",
+                    "type": "string",
+                    "example": "intro-30"
+                },
+                "invitee_email": {
+                    "type": "string"
+                },
+                "invitee_name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "start_time": {
+                    "type": "string",
+                    "example": "2024-12-15T10:00:00Z"
+                },
+                "username": {
+                    "description": "host of the booking",
+                    "type": "string"
+                }
+            }
+        },
+        "CreateCalendarConnectionRequest": {
+            "type": "object",
+            "required": [
+                "location",
+                "provider"
+            ],
+            "properties": {
+                "location": {
+                    "description": "provider specific, a file name or an http(s) url under FAKE_CALENDAR_BASE_URL for the fake provider",
+                    "type": "string",
+                    "example": "work.json"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Work calendar"
+                },
+                "provider": {
+                    "type": "string",
+                    "example": "fake"
+                }
+            }
+        },
+        "CreateDateAvailabilityRequest": {
+            "type": "object",
+            "required": [
+                "date",
+                "slots",
+                "username"
+            ],
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2024-12-15T00:00:00Z"
+                },
+                "mode": {
+                    "description": "replace (default), add or block",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_niharika88_calendly-api_internal_db_models.OverrideMode"
+                        }
+                    ],
+                    "example": "block"
+                },
+                "schedule_id": {
+                    "description": "defaults to the user's default schedule",
+                    "type": "string"
+                },
+                "slots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Slot"
+                    }
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "CreateDateRangeAvailabilityRequest": {
+            "type": "object",
+            "required": [
+                "end_date",
+                "slots",
+                "start_date",
+                "username"
+            ],
+            "properties": {
+                "days": {
+                    "description": "all days by default",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_niharika88_calendly-api_internal_db_models.Day"
+                    },
+                    "example": [
+                        "monday",
+                        "tuesday"
+                    ]
+                },
+                "end_date": {
+                    "type": "string",
+                    "example": "2024-12-31T00:00:00Z"
+                },
+                "mode": {
+                    "description": "replace (default), add or block",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_niharika88_calendly-api_internal_db_models.OverrideMode"
+                        }
+                    ],
+                    "example": "replace"
+                },
+                "schedule_id": {
+                    "description": "defaults to the user's default schedule",
+                    "type": "string"
+                },
+                "slots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Slot"
+                    }
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2024-12-23T00:00:00Z"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "CreateDayAvailabilityRequest": {
+            "type": "object",
+            "required": [
+                "availability",
+                "username"
+            ],
+            "properties": {
+                "availability": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/UserDayAvailability"
+                    }
+                },
+                "effective_from": {
+                    "description": "the weekly hours apply from this date on, today by default",
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "schedule_id": {
+                    "description": "defaults to the user's default schedule",
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "CreateEventTypeRequest": {
+            "type": "object",
+            "required": [
+                "duration",
+                "name",
+                "slug"
+            ],
+            "properties": {
+                "booking_horizon": {
+                    "description": "days, nothing can be booked further than this from now, 0 means no limit",
+                    "type": "integer",
+                    "example": 60
+                },
+                "buffer_after": {
+                    "description": "minutes that have to be free after the meeting",
+                    "type": "integer",
+                    "example": 15
+                },
+                "buffer_before": {
+                    "description": "minutes that have to be free before the meeting",
+                    "type": "integer",
+                    "example": 15
+                },
+                "daily_cap": {
+                    "description": "max bookings of this event type per day, 0 means no cap",
+                    "type": "integer",
+                    "example": 4
+                },
+                "description": {
+                    "type": "string"
+                },
+                "duration": {
+                    "description": "minutes",
+                    "type": "integer",
+                    "example": 30
+                },
+                "min_notice": {
+                    "description": "minutes, nothing can be booked sooner than this from now",
+                    "type": "integer",
+                    "example": 240
+                },
+                "name": {
+                    "type": "string",
+                    "example": "30-min intro"
+                },
+                "schedule_id": {
+                    "description": "schedule the slots come from, defaults to the user's default schedule",
+                    "type": "string"
+                },
+                "slot_increment": {
+                    "description": "minutes, defaults to 15",
+                    "type": "integer",
+                    "example": 15
+                },
+                "slug": {
+                    "type": "string",
+                    "example": "intro-30"
+                },
+                "weekly_cap": {
+                    "description": "max bookings of this event type per week, 0 means no cap",
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "CreateRuleAvailabilityRequest": {
+            "type": "object",
+            "required": [
+                "rrule",
+                "slots",
+                "start_date",
+                "username"
+            ],
+            "properties": {
+                "exdates": {
+                    "description": "EXDATEs",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "2025-01-17T00:00:00Z"
+                    ]
+                },
+                "rrule": {
+                    "type": "string",
+                    "example": "FREQ=WEEKLY;INTERVAL=2;BYDAY=FR"
+                },
+                "schedule_id": {
+                    "description": "defaults to the user's default schedule",
+                    "type": "string"
+                },
+                "slots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Slot"
+                    }
+                },
+                "start_date": {
+                    "description": "DTSTART",
+                    "type": "string",
+                    "example": "2024-12-20T00:00:00Z"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "CreateScheduleRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "is_default": {
+                    "description": "the user's first schedule is always the default one",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Evening support"
+                }
+            }
+        },
+        "CreateTimeOffRequest": {
+            "type": "object",
+            "required": [
+                "end_date",
+                "start_date"
+            ],
+            "properties": {
+                "end_date": {
+                    "type": "string",
+                    "example": "2025-01-03T00:00:00Z"
+                },
+                "end_minute": {
+                    "description": "time off ends at this minute of the end date, whole day (1440) by default",
+                    "type": "integer",
+                    "example": 1440
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Vacation"
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2024-12-23T00:00:00Z"
+                },
+                "start_minute": {
+                    "description": "time off starts at this minute of the start date, whole day (0) by default",
+                    "type": "integer",
+                    "example": 720
+                }
+            }
+        },
+        "DateAvailability": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "mode": {
+                    "$ref": "#/definitions/github_com_niharika88_calendly-api_internal_db_models.OverrideMode"
+                },
+                "schedule_id": {
+                    "type": "string"
+                },
+                "slots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Slot"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "DateAvailabilityResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "mode": {
+                    "$ref": "#/definitions/github_com_niharika88_calendly-api_internal_db_models.OverrideMode"
+                },
+                "schedule_id": {
+                    "type": "string"
+                },
+                "slots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Slot"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "DateRangeAvailabilityResponse": {
+            "type": "object",
+            "properties": {
+                "availability": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/DateAvailability"
+                    }
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "DayAvailability": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "day": {
+                    "$ref": "#/definitions/github_com_niharika88_calendly-api_internal_db_models.Day"
+                },
+                "effective_from": {
+                    "description": "Date (in the user's timezone) the weekly hours start to apply",
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "effective_to": {
+                    "description": "Date they stop to apply (excluded), nil until a newer version starts",
+                    "type": "string",
+                    "example": "2025-02-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "schedule_id": {
+                    "type": "string"
+                },
+                "slots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Slot"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "DayAvailabilityResponse": {
+            "type": "object",
+            "properties": {
+                "availability": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/DayAvailability"
+                    }
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "DeleteDateRangeAvailabilityRequest": {
+            "type": "object",
+            "required": [
+                "end_date",
+                "start_date",
+                "username"
+            ],
+            "properties": {
+                "end_date": {
+                    "type": "string",
+                    "example": "2024-12-31T00:00:00Z"
+                },
+                "mode": {
+                    "description": "only date availabilities of this mode are deleted, all modes by default",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_niharika88_calendly-api_internal_db_models.OverrideMode"
+                        }
+                    ]
+                },
+                "schedule_id": {
+                    "description": "defaults to the user's default schedule",
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2024-12-23T00:00:00Z"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "DeleteRuleAvailabilityRequest": {
+            "type": "object",
+            "required": [
+                "id",
+                "username"
+            ],
+            "properties": {
+                "id": {
+                    "description": "ID of the rule availability",
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "DeleteUserAvailabilityRequest": {
+            "type": "object",
+            "required": [
+                "username"
+            ],
+            "properties": {
+                "date": {
+                    "description": "for day availability the date the weekly hours stop to apply, today by default",
+                    "type": "string",
+                    "example": "2024-12-15T00:00:00Z"
+                },
+                "mode": {
+                    "description": "only date availabilities of this mode are deleted, all modes by default",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_niharika88_calendly-api_internal_db_models.OverrideMode"
+                        }
+                    ]
+                },
+                "schedule_id": {
+                    "description": "defaults to the user's default schedule",
+                    "type": "string"
+                },
                 "username": {
                     "type": "string"
                 }
             }
         },
-        "CreateDayAvailabilityRequest": {
+        "EventType": {
+            "type": "object",
+            "properties": {
+                "booking_horizon": {
+                    "description": "Days from now after which nothing can be booked, 0 means no limit",
+                    "type": "integer"
+                },
+                "buffer_after": {
+                    "description": "Minutes that have to be free after the meeting",
+                    "type": "integer"
+                },
+                "buffer_before": {
+                    "description": "Minutes that have to be free before the meeting",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "daily_cap": {
+                    "description": "Max confirmed bookings of this event type per day in the host's timezone, 0 means no cap",
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "duration": {
+                    "description": "Length of the meeting in minutes",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "min_notice": {
+                    "description": "booking rules",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "schedule_id": {
+                    "description": "Schedule the slots come from, nil means the user's default schedule",
+                    "type": "string"
+                },
+                "slot_increment": {
+                    "description": "Minutes between two consecutive start times",
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "weekly_cap": {
+                    "description": "Max confirmed bookings of this event type per week (monday to sunday), 0 means no cap",
+                    "type": "integer"
+                }
+            }
+        },
+        "EventTypeSlots": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "event_type": {
+                    "description": "slug of the event type",
+                    "type": "string",
+                    "example": "intro-30"
+                },
+                "slots": {
+                    "description": "bookable start times grouped by date",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "Holiday": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2025-10-03T00:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Tag der Deutschen Einheit"
+                }
+            }
+        },
+        "HolidayRegion": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "DE-BY"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Germany, Bavaria"
+                }
+            }
+        },
+        "NextCommonSlots": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "description": "minutes",
+                    "type": "integer",
+                    "example": 30
+                },
+                "windows": {
+                    "description": "earliest windows where everyone is free for at least duration",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/TimeWindow"
+                    }
+                }
+            }
+        },
+        "OverlapSlot": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "description": "End time in minutes since midnight, past 1440 for a slot continuing on the next day",
+                    "type": "integer"
+                },
+                "start": {
+                    "description": "Start time in minutes since midnight",
+                    "type": "integer"
+                },
+                "users": {
+                    "description": "usernames",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "RescheduleBookingRequest": {
             "type": "object",
             "required": [
-                "availability",
-                "username"
+                "start_time"
             ],
             "properties": {
-                "availability": {
+                "reason": {
+                    "type": "string",
+                    "example": "conflicting meeting"
+                },
+                "start_time": {
+                    "description": "booking keeps its duration",
+                    "type": "string",
+                    "example": "2024-12-16T10:00:00Z"
+                }
+            }
+        },
+        "Response": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "error": {
+                    "$ref": "#/definitions/echo.HTTPError"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "RuleAvailability": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "exdates": {
+                    "description": "EXDATEs, dates the rule doesn't occur on",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/UserDayAvailability"
+                        "type": "string"
                     }
                 },
-                "username": {
+                "id": {
+                    "type": "string"
+                },
+                "rrule": {
+                    "type": "string",
+                    "example": "FREQ=WEEKLY;INTERVAL=2;BYDAY=FR"
+                },
+                "schedule_id": {
+                    "type": "string"
+                },
+                "slots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Slot"
+                    }
+                },
+                "start_date": {
+                    "description": "DTSTART, the first date the rule can occur on",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "DateAvailability": {
+        "RuleAvailabilityResponse": {
             "type": "object",
             "properties": {
                 "created_at": {
                     "type": "string"
                 },
-                "date": {
-                    "type": "string"
+                "exdates": {
+                    "description": "EXDATEs, dates the rule doesn't occur on",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
                 "id": {
                     "type": "string"
                 },
+                "rrule": {
+                    "type": "string",
+                    "example": "FREQ=WEEKLY;INTERVAL=2;BYDAY=FR"
+                },
+                "schedule_id": {
+                    "type": "string"
+                },
                 "slots": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/Slot"
                     }
                 },
+                "start_date": {
+                    "description": "DTSTART, the first date the rule can occur on",
+                    "type": "string"
+                },
                 "updated_at": {
                     "type": "string"
                 },
                 "user_id": {
                     "type": "string"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "DayAvailability": {
+        "Schedule": {
             "type": "object",
             "properties": {
                 "created_at": {
                     "type": "string"
                 },
-                "day": {
-                    "$ref": "#/definitions/github_com_niharika88_calendly-api_internal_db_models.Day"
+                "date_availabilities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/DateAvailability"
+                    }
+                },
+                "day_availabilities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/DayAvailability"
+                    }
                 },
                 "id": {
                     "type": "string"
                 },
-                "slots": {
+                "is_default": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Working hours"
+                },
+                "rule_availabilities": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/Slot"
+                        "$ref": "#/definitions/RuleAvailability"
                     }
                 },
                 "updated_at": {
@@ -790,44 +4487,158 @@ const docTemplate = `{
                 }
             }
         },
-        "DeleteUserAvailabilityRequest": {
+        "ScheduleOverlap": {
             "type": "object",
             "required": [
-                "username"
+                "availability"
             ],
             "properties": {
-                "date": {
+                "availability": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/OverlapSlot"
+                        }
+                    }
+                }
+            }
+        },
+        "Slot": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "description": "End time in minutes since midnight, past 1440 for a slot continuing on the next day",
+                    "type": "integer"
+                },
+                "start": {
+                    "description": "Start time in minutes since midnight",
+                    "type": "integer"
+                }
+            }
+        },
+        "TimeOff": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "end_minute": {
+                    "description": "Minutes since midnight of the end date, 1440 is the whole day",
+                    "type": "integer",
+                    "example": 1440
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reason": {
                     "type": "string",
-                    "example": "2024-12-15T00:00:00Z"
+                    "example": "Vacation"
                 },
-                "username": {
+                "start_date": {
+                    "type": "string"
+                },
+                "start_minute": {
+                    "description": "Minutes since midnight of the start date, 0 is the whole day",
+                    "type": "integer",
+                    "example": 0
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "Response": {
+        "TimeWindow": {
             "type": "object",
             "properties": {
-                "code": {
+                "end": {
+                    "type": "string",
+                    "example": "2024-12-15T11:00:00Z"
+                },
+                "start": {
+                    "type": "string",
+                    "example": "2024-12-15T10:00:00Z"
+                }
+            }
+        },
+        "UpdateEventTypeRequest": {
+            "type": "object",
+            "properties": {
+                "booking_horizon": {
                     "type": "integer"
                 },
-                "error": {
-                    "$ref": "#/definitions/echo.HTTPError"
+                "buffer_after": {
+                    "type": "integer"
                 },
-                "success": {
+                "buffer_before": {
+                    "type": "integer"
+                },
+                "daily_cap": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "duration": {
+                    "type": "integer"
+                },
+                "min_notice": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "schedule_id": {
+                    "description": "nil UUID switches back to the user's default schedule",
+                    "type": "string"
+                },
+                "slot_increment": {
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "weekly_cap": {
+                    "type": "integer"
+                }
+            }
+        },
+        "UpdateScheduleRequest": {
+            "type": "object",
+            "properties": {
+                "is_default": {
+                    "description": "only true is allowed, make another schedule the default to unset it",
                     "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
                 }
             }
         },
-        "Slot": {
+        "UpdateTimeOffRequest": {
             "type": "object",
             "properties": {
-                "end": {
-                    "description": "End time in minutes since midnight",
+                "end_date": {
+                    "type": "string",
+                    "example": "2025-01-03T00:00:00Z"
+                },
+                "end_minute": {
                     "type": "integer"
                 },
-                "start": {
-                    "description": "Start time in minutes since midnight",
+                "reason": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2024-12-23T00:00:00Z"
+                },
+                "start_minute": {
                     "type": "integer"
                 }
             }
@@ -835,17 +4646,49 @@ const docTemplate = `{
         "UpdateUserRequest": {
             "type": "object",
             "properties": {
+                "daily_booking_cap": {
+                    "type": "integer"
+                },
                 "email": {
                     "type": "string"
                 },
                 "first_name": {
                     "type": "string"
                 },
+                "holiday_region": {
+                    "type": "string"
+                },
                 "last_name": {
                     "type": "string"
                 },
                 "timezone": {
                     "type": "string"
+                },
+                "weekly_booking_cap": {
+                    "type": "integer"
+                }
+            }
+        },
+        "UpsertDayAvailabilityRequest": {
+            "type": "object",
+            "required": [
+                "slots"
+            ],
+            "properties": {
+                "effective_from": {
+                    "description": "the slots apply from this date on, today by default",
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "schedule_id": {
+                    "description": "defaults to the user's default schedule",
+                    "type": "string"
+                },
+                "slots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Slot"
+                    }
                 }
             }
         },
@@ -858,12 +4701,22 @@ const docTemplate = `{
                 "created_at": {
                     "type": "string"
                 },
+                "daily_booking_cap": {
+                    "description": "Max confirmed bookings per day in the user's timezone, 0 means no cap",
+                    "type": "integer",
+                    "example": 4
+                },
                 "email": {
                     "type": "string"
                 },
                 "first_name": {
                     "type": "string"
                 },
+                "holiday_region": {
+                    "description": "Public holidays of the region (e.g. DE-BY) block availability, empty means none",
+                    "type": "string",
+                    "example": "DE-BY"
+                },
                 "id": {
                     "type": "string"
                 },
@@ -871,14 +4724,20 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "timezone": {
-                    "description": "timezone for future use",
-                    "type": "string"
+                    "description": "IANA timezone, availability slots are interpreted in it",
+                    "type": "string",
+                    "example": "Europe/Berlin"
                 },
                 "updated_at": {
                     "type": "string"
                 },
                 "username": {
                     "type": "string"
+                },
+                "weekly_booking_cap": {
+                    "description": "Max confirmed bookings per week (monday to sunday), 0 means no cap",
+                    "type": "integer",
+                    "example": 12
                 }
             }
         },
@@ -917,12 +4776,53 @@ const docTemplate = `{
                 }
             }
         },
+        "WeeklyHoursVersion": {
+            "type": "object",
+            "properties": {
+                "availability": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/UserDayAvailability"
+                    }
+                },
+                "effective_from": {
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "effective_to": {
+                    "type": "string",
+                    "example": "2025-02-01T00:00:00Z"
+                }
+            }
+        },
         "echo.HTTPError": {
             "type": "object",
             "properties": {
                 "message": {}
             }
         },
+        "github_com_niharika88_calendly-api_internal_db_models.BookingChangeAction": {
+            "type": "string",
+            "enum": [
+                "cancelled",
+                "rescheduled"
+            ],
+            "x-enum-varnames": [
+                "BookingChangeCancelled",
+                "BookingChangeRescheduled"
+            ]
+        },
+        "github_com_niharika88_calendly-api_internal_db_models.BookingStatus": {
+            "type": "string",
+            "enum": [
+                "confirmed",
+                "cancelled"
+            ],
+            "x-enum-varnames": [
+                "BookingStatusConfirmed",
+                "BookingStatusCancelled"
+            ]
+        },
         "github_com_niharika88_calendly-api_internal_db_models.Day": {
             "type": "string",
             "enum": [
@@ -943,6 +4843,39 @@ const docTemplate = `{
                 "DaySaturday",
                 "DaySunday"
             ]
+        },
+        "github_com_niharika88_calendly-api_internal_db_models.OverrideMode": {
+            "type": "string",
+            "enum": [
+                "replace",
+                "add",
+                "block"
+            ],
+            "x-enum-comments": {
+                "OverrideModeAdd": "slots are added to the availability of the date",
+                "OverrideModeBlock": "slots are removed from the availability of the date",
+                "OverrideModeReplace": "slots replace the availability of the date"
+            },
+            "x-enum-varnames": [
+                "OverrideModeReplace",
+                "OverrideModeAdd",
+                "OverrideModeBlock"
+            ]
+        },
+        "github_com_niharika88_calendly-api_pkg_api.CopyConflictPolicy": {
+            "type": "string",
+            "enum": [
+                "skip",
+                "overwrite"
+            ],
+            "x-enum-comments": {
+                "CopyConflictOverwrite": "its date availabilities (of every mode) are replaced",
+                "CopyConflictSkip": "the date is left as it is"
+            },
+            "x-enum-varnames": [
+                "CopyConflictSkip",
+                "CopyConflictOverwrite"
+            ]
         }
     }
 }`
@@ -5,12 +5,14 @@ go 1.23.0
 require (
 	github.com/amacneil/dbmate/v2 v2.24.0
 	github.com/caarlos0/env/v11 v11.2.2
+	github.com/emersion/go-ical v0.0.0-20250329121855-f41e73efc392
 	github.com/go-playground/validator v9.31.0+incompatible
 	github.com/google/uuid v1.6.0
 	github.com/labstack/echo/v4 v4.13.2
 	github.com/pandoratoolbox/bun/extra/bunslog v0.0.0-20240419144920-8d9f15e33ce6
 	github.com/swaggo/echo-swagger v1.4.1
 	github.com/swaggo/swag v1.16.4
+	github.com/teambition/rrule-go v1.8.2
 	github.com/uptrace/bun v1.2.6
 	github.com/uptrace/bun/dialect/pgdialect v1.2.6
 	github.com/uptrace/bun/driver/pgdriver v1.2.6
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type BookingStatus string
+
+const (
+	BookingStatusConfirmed BookingStatus = "confirmed"
+	BookingStatusCancelled BookingStatus = "cancelled"
+)
+
+func (s BookingStatus) String() string {
+	return string(s)
+}
+
+func (s BookingStatus) IsValid() bool {
+	switch s {
+	case BookingStatusConfirmed, BookingStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// Booking represents a meeting booked by an invitee on the host's (user) calendar.
+type Booking struct {
+	bun.BaseModel `bun:"table:bookings" swaggerignore:"true"`
+
+	ID           uuid.UUID     `json:"id" bun:"id,pk,type:uuid"`
+	UserID       uuid.UUID     `json:"user_id" bun:"user_id,type:uuid,notnull"` // host of the booking
+	EventTypeID  *uuid.UUID    `json:"event_type_id,omitempty" bun:"event_type_id,type:uuid"`
+	InviteeName  string        `json:"invitee_name" bun:"invitee_name,type:varchar(255),notnull"`
+	InviteeEmail string        `json:"invitee_email" bun:"invitee_email,type:varchar(255),notnull"`
+	StartTime    time.Time     `json:"start_time" bun:"start_time,type:timestamptz,notnull"`
+	EndTime      time.Time     `json:"end_time" bun:"end_time,type:timestamptz,notnull"`
+	Status       BookingStatus `json:"status" bun:"status,type:booking_status_enum,notnull"`
+	Notes        string        `json:"notes" bun:"notes,type:text"`
+	TokenHash    string        `json:"-" bun:"token_hash,type:varchar(64),nullzero"`
+	CreatedAt    time.Time     `json:"created_at" bun:"created_at,type:timestamptz,notnull,default:current_timestamp"`
+	UpdatedAt    time.Time     `json:"updated_at" bun:"updated_at,type:timestamptz,notnull,default:current_timestamp"`
+
+	// Token lets the invitee cancel or reschedule without an account, it's only returned when the booking is created
+	Token   string           `json:"token,omitempty" bun:"-"`
+	Changes []*BookingChange `json:"changes,omitempty" bun:"rel:has-many,join:id=booking_id"`
+} // @name Booking
+
+var _ bun.BeforeAppendModelHook = (*Booking)(nil)
+
+func (b *Booking) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		b.CreatedAt = time.Now().UTC()
+		if b.ID == uuid.Nil {
+			b.ID = uuid.New()
+		}
+	case *bun.UpdateQuery:
+		b.UpdatedAt = time.Now().UTC()
+	}
+	return nil
+}
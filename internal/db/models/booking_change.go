@@ -0,0 +1,47 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type BookingChangeAction string
+
+const (
+	BookingChangeCancelled   BookingChangeAction = "cancelled"
+	BookingChangeRescheduled BookingChangeAction = "rescheduled"
+)
+
+// BookingChange keeps the history of a booking, every cancellation or reschedule by the invitee adds one.
+type BookingChange struct {
+	bun.BaseModel `bun:"table:booking_changes" swaggerignore:"true"`
+
+	ID                uuid.UUID           `json:"id" bun:"id,pk,type:uuid"`
+	BookingID         uuid.UUID           `json:"booking_id" bun:"booking_id,type:uuid,notnull"`
+	Action            BookingChangeAction `json:"action" bun:"action,type:booking_change_action_enum,notnull"`
+	PreviousStartTime time.Time           `json:"previous_start_time" bun:"previous_start_time,type:timestamptz,notnull"`
+	PreviousEndTime   time.Time           `json:"previous_end_time" bun:"previous_end_time,type:timestamptz,notnull"`
+	StartTime         *time.Time          `json:"start_time,omitempty" bun:"start_time,type:timestamptz"` // new time when rescheduled
+	EndTime           *time.Time          `json:"end_time,omitempty" bun:"end_time,type:timestamptz"`
+	Reason            string              `json:"reason" bun:"reason,type:text"`
+	CreatedAt         time.Time           `json:"created_at" bun:"created_at,type:timestamptz,notnull,default:current_timestamp"`
+	UpdatedAt         time.Time           `json:"updated_at" bun:"updated_at,type:timestamptz,notnull,default:current_timestamp"`
+} // @name BookingChange
+
+var _ bun.BeforeAppendModelHook = (*BookingChange)(nil)
+
+func (b *BookingChange) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		b.CreatedAt = time.Now().UTC()
+		if b.ID == uuid.Nil {
+			b.ID = uuid.New()
+		}
+	case *bun.UpdateQuery:
+		b.UpdatedAt = time.Now().UTC()
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// BusySourceImport is the source of the busy blocks imported from uploaded .ics files
+const BusySourceImport = "import"
+
+// BusyBlock is time a user is busy with an event of another calendar (an imported .ics file or a connected calendar), it's not available for bookings.
+// Every occurrence of a recurring event is a block of its own, all of them share the event's UID
+type BusyBlock struct {
+	bun.BaseModel `bun:"table:busy_blocks" swaggerignore:"true"`
+
+	ID        uuid.UUID `json:"id" bun:"id,pk,type:uuid"`
+	UserID    uuid.UUID `json:"user_id" bun:"user_id,type:uuid,notnull"`
+	Source    string    `json:"source" example:"import" bun:"source,type:varchar(255),notnull"`
+	UID       string    `json:"uid" example:"040000008200E00074C5B7101A82E008@example.com" bun:"uid,type:varchar(1024),notnull"`
+	Summary   string    `json:"summary" example:"Team sync" bun:"summary,type:text"`
+	StartTime time.Time `json:"start_time" bun:"start_time,type:timestamptz,notnull"`
+	EndTime   time.Time `json:"end_time" bun:"end_time,type:timestamptz,notnull"`
+	CreatedAt time.Time `json:"created_at" bun:"created_at,type:timestamptz,notnull,default:current_timestamp"`
+	UpdatedAt time.Time `json:"updated_at" bun:"updated_at,type:timestamptz,notnull,default:current_timestamp"`
+
+	ConnectionID *uuid.UUID `json:"connection_id,omitempty" bun:"connection_id,type:uuid"` // Calendar connection the block was synced from, nil for imported ones
+} // @name BusyBlock
+
+var _ bun.BeforeAppendModelHook = (*BusyBlock)(nil)
+
+func (b *BusyBlock) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		b.CreatedAt = time.Now().UTC()
+		if b.ID == uuid.Nil {
+			b.ID = uuid.New()
+		}
+	case *bun.UpdateQuery:
+		b.UpdatedAt = time.Now().UTC()
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// CalendarConnection is an external calendar of a user, its busy time is synced periodically into busy blocks
+type CalendarConnection struct {
+	bun.BaseModel `bun:"table:calendar_connections" swaggerignore:"true"`
+
+	ID           uuid.UUID  `json:"id" bun:"id,pk,type:uuid"`
+	UserID       uuid.UUID  `json:"user_id" bun:"user_id,type:uuid,notnull"`
+	Provider     string     `json:"provider" example:"fake" bun:"provider,type:varchar(64),notnull"`
+	Name         string     `json:"name" example:"Work calendar" bun:"name,type:varchar(255),notnull"`
+	Location     string     `json:"location" example:"work.json" bun:"location,type:text,notnull"` // Provider specific address of the calendar, e.g. a file or url
+	SyncToken    string     `json:"-" bun:"sync_token,type:text,nullzero"`
+	LastSyncedAt *time.Time `json:"last_synced_at" bun:"last_synced_at,type:timestamptz"`
+	FullSyncedAt *time.Time `json:"full_synced_at" bun:"full_synced_at,type:timestamptz"`
+	LastError    string     `json:"last_error,omitempty" bun:"last_error,type:text,nullzero"` // Error of the last sync, empty when it succeeded
+	CreatedAt    time.Time  `json:"created_at" bun:"created_at,type:timestamptz,notnull,default:current_timestamp"`
+	UpdatedAt    time.Time  `json:"updated_at" bun:"updated_at,type:timestamptz,notnull,default:current_timestamp"`
+} // @name CalendarConnection
+
+var _ bun.BeforeAppendModelHook = (*CalendarConnection)(nil)
+
+func (c *CalendarConnection) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		c.CreatedAt = time.Now().UTC()
+		if c.ID == uuid.Nil {
+			c.ID = uuid.New()
+		}
+	case *bun.UpdateQuery:
+		c.UpdatedAt = time.Now().UTC()
+	}
+	return nil
+}
@@ -8,16 +8,40 @@ import (
 	"github.com/uptrace/bun"
 )
 
-// DateAvailability represents the availability of a user for a specific date.
+type OverrideMode string
+
+const (
+	OverrideModeReplace OverrideMode = "replace" // slots replace the availability of the date
+	OverrideModeAdd     OverrideMode = "add"     // slots are added to the availability of the date
+	OverrideModeBlock   OverrideMode = "block"   // slots are removed from the availability of the date
+)
+
+func (m OverrideMode) String() string {
+	return string(m)
+}
+
+func (m OverrideMode) IsValid() bool {
+	switch m {
+	case OverrideModeReplace, OverrideModeAdd, OverrideModeBlock:
+		return true
+	default:
+		return false
+	}
+}
+
+// DateAvailability represents the availability of a user for a specific date, depending on its mode it replaces, adds to
+// or blocks part of the availability of the same schedule on that date.
 type DateAvailability struct {
 	bun.BaseModel `bun:"table:date_availabilities" swaggerignore:"true"`
 
-	ID        uuid.UUID `json:"id" bun:"id,pk,type:uuid"`
-	Date      time.Time `json:"date" bun:"date,type:date,notnull"`
-	UserID    uuid.UUID `json:"user_id" bun:"user_id,type:uuid,notnull"`
-	Slots     []Slot    `json:"slots" bun:"slots,type:jsonb,notnull"`
-	CreatedAt time.Time `json:"created_at" bun:"created_at,type:timestamptz,notnull,default:current_timestamp"`
-	UpdatedAt time.Time `json:"updated_at" bun:"updated_at,type:timestamptz,notnull,default:current_timestamp"`
+	ID         uuid.UUID    `json:"id" bun:"id,pk,type:uuid"`
+	Date       time.Time    `json:"date" bun:"date,type:date,notnull"`
+	Mode       OverrideMode `json:"mode" bun:"mode,type:override_mode_enum,notnull"`
+	UserID     uuid.UUID    `json:"user_id" bun:"user_id,type:uuid,notnull"`
+	ScheduleID uuid.UUID    `json:"schedule_id" bun:"schedule_id,type:uuid,notnull"`
+	Slots      []Slot       `json:"slots" bun:"slots,type:jsonb,notnull"`
+	CreatedAt  time.Time    `json:"created_at" bun:"created_at,type:timestamptz,notnull,default:current_timestamp"`
+	UpdatedAt  time.Time    `json:"updated_at" bun:"updated_at,type:timestamptz,notnull,default:current_timestamp"`
 } // @name DateAvailability
 
 var _ bun.BeforeAppendModelHook = (*DateAvailability)(nil)
@@ -38,5 +62,5 @@ func (d *DateAvailability) BeforeAppendModel(ctx context.Context, query bun.Quer
 // Slot represents a time slot within a day or a date.
 type Slot struct {
 	Start int `json:"start"` // Start time in minutes since midnight
-	End   int `json:"end"`   // End time in minutes since midnight
+	End   int `json:"end"`   // End time in minutes since midnight, past 1440 for a slot continuing on the next day
 } // @name Slot
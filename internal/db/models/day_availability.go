@@ -33,16 +33,21 @@ func (d Day) IsValid() bool {
 	}
 }
 
-// DayAvailability represents the availability of a user for a specific day.
+// DayAvailability represents the availability of a user for a specific day, as part of one of their schedules.
+// The weekly hours of a schedule are versioned, every version (the rows with the same EffectiveFrom) applies from EffectiveFrom until EffectiveTo
 type DayAvailability struct {
 	bun.BaseModel `bun:"table:day_availabilities" swaggerignore:"true"`
 
-	ID        uuid.UUID `json:"id" bun:"id,pk,type:uuid"`
-	Day       Day       `json:"day" bun:"day,type:day_enum,notnull"`
-	UserID    uuid.UUID `json:"user_id" bun:"user_id,type:uuid,notnull"`
-	Slots     []Slot    `json:"slots" bun:"slots,type:jsonb,notnull"`
-	CreatedAt time.Time `json:"created_at" bun:"created_at,type:timestamptz,notnull,default:current_timestamp"`
-	UpdatedAt time.Time `json:"updated_at" bun:"updated_at,type:timestamptz,notnull,default:current_timestamp"`
+	ID         uuid.UUID `json:"id" bun:"id,pk,type:uuid"`
+	Day        Day       `json:"day" bun:"day,type:day_enum,notnull"`
+	UserID     uuid.UUID `json:"user_id" bun:"user_id,type:uuid,notnull"`
+	ScheduleID uuid.UUID `json:"schedule_id" bun:"schedule_id,type:uuid,notnull"`
+	Slots      []Slot    `json:"slots" bun:"slots,type:jsonb,notnull"`
+	CreatedAt  time.Time `json:"created_at" bun:"created_at,type:timestamptz,notnull,default:current_timestamp"`
+	UpdatedAt  time.Time `json:"updated_at" bun:"updated_at,type:timestamptz,notnull,default:current_timestamp"`
+
+	EffectiveFrom time.Time  `json:"effective_from" example:"2025-01-01T00:00:00Z" bun:"effective_from,type:date,notnull"` // Date (in the user's timezone) the weekly hours start to apply
+	EffectiveTo   *time.Time `json:"effective_to" example:"2025-02-01T00:00:00Z" bun:"effective_to,type:date"`             // Date they stop to apply (excluded), nil until a newer version starts
 } // @name DayAvailability
 
 var _ bun.BeforeAppendModelHook = (*DayAvailability)(nil)
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// EventType represents a kind of meeting a host offers (e.g. "30-min intro"), invitees book start times of an event type.
+type EventType struct {
+	bun.BaseModel `bun:"table:event_types" swaggerignore:"true"`
+
+	ID            uuid.UUID  `json:"id" bun:"id,pk,type:uuid"`
+	UserID        uuid.UUID  `json:"user_id" bun:"user_id,type:uuid,notnull"`
+	Name          string     `json:"name" bun:"name,type:varchar(255),notnull"`
+	Slug          string     `json:"slug" bun:"slug,type:varchar(255),notnull"`
+	Description   string     `json:"description" bun:"description,type:text"`
+	Duration      int        `json:"duration" bun:"duration,notnull"`             // Length of the meeting in minutes
+	SlotIncrement int        `json:"slot_increment" bun:"slot_increment,notnull"` // Minutes between two consecutive start times
+	ScheduleID    *uuid.UUID `json:"schedule_id" bun:"schedule_id,type:uuid"`     // Schedule the slots come from, nil means the user's default schedule
+
+	// booking rules
+	MinNotice      int `json:"min_notice" bun:"min_notice,notnull"`           // Minutes from now before which nothing can be booked
+	BookingHorizon int `json:"booking_horizon" bun:"booking_horizon,notnull"` // Days from now after which nothing can be booked, 0 means no limit
+	BufferBefore   int `json:"buffer_before" bun:"buffer_before,notnull"`     // Minutes that have to be free before the meeting
+	BufferAfter    int `json:"buffer_after" bun:"buffer_after,notnull"`       // Minutes that have to be free after the meeting
+	DailyCap       int `json:"daily_cap" bun:"daily_cap,notnull"`             // Max confirmed bookings of this event type per day in the host's timezone, 0 means no cap
+	WeeklyCap      int `json:"weekly_cap" bun:"weekly_cap,notnull"`           // Max confirmed bookings of this event type per week (monday to sunday), 0 means no cap
+
+	CreatedAt time.Time `json:"created_at" bun:"created_at,type:timestamptz,notnull,default:current_timestamp"`
+	UpdatedAt time.Time `json:"updated_at" bun:"updated_at,type:timestamptz,notnull,default:current_timestamp"`
+} // @name EventType
+
+var _ bun.BeforeAppendModelHook = (*EventType)(nil)
+
+func (e *EventType) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		e.CreatedAt = time.Now().UTC()
+		if e.ID == uuid.Nil {
+			e.ID = uuid.New()
+		}
+	case *bun.UpdateQuery:
+		e.UpdatedAt = time.Now().UTC()
+	}
+	return nil
+}
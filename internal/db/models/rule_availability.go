@@ -0,0 +1,40 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// RuleAvailability represents recurring availability of a user expressed as an iCalendar RRULE (e.g. every other friday),
+// as part of one of their schedules. The rule produces dates of the user's timezone starting from StartDate.
+type RuleAvailability struct {
+	bun.BaseModel `bun:"table:rule_availabilities" swaggerignore:"true"`
+
+	ID         uuid.UUID   `json:"id" bun:"id,pk,type:uuid"`
+	UserID     uuid.UUID   `json:"user_id" bun:"user_id,type:uuid,notnull"`
+	ScheduleID uuid.UUID   `json:"schedule_id" bun:"schedule_id,type:uuid,notnull"`
+	RRule      string      `json:"rrule" example:"FREQ=WEEKLY;INTERVAL=2;BYDAY=FR" bun:"rrule,type:text,notnull"`
+	StartDate  time.Time   `json:"start_date" bun:"start_date,type:date,notnull"`   // DTSTART, the first date the rule can occur on
+	ExDates    []time.Time `json:"exdates" bun:"exdates,array,type:date[],notnull"` // EXDATEs, dates the rule doesn't occur on
+	Slots      []Slot      `json:"slots" bun:"slots,type:jsonb,notnull"`
+	CreatedAt  time.Time   `json:"created_at" bun:"created_at,type:timestamptz,notnull,default:current_timestamp"`
+	UpdatedAt  time.Time   `json:"updated_at" bun:"updated_at,type:timestamptz,notnull,default:current_timestamp"`
+} // @name RuleAvailability
+
+var _ bun.BeforeAppendModelHook = (*RuleAvailability)(nil)
+
+func (r *RuleAvailability) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		r.CreatedAt = time.Now().UTC()
+		if r.ID == uuid.Nil {
+			r.ID = uuid.New()
+		}
+	case *bun.UpdateQuery:
+		r.UpdatedAt = time.Now().UTC()
+	}
+	return nil
+}
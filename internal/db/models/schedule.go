@@ -0,0 +1,41 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Schedule is a named set of day availabilities and date overrides (e.g. "Working hours", "Evening support"),
+// every user has one default schedule that is used unless another one is picked.
+type Schedule struct {
+	bun.BaseModel `bun:"table:schedules" swaggerignore:"true"`
+
+	ID        uuid.UUID `json:"id" bun:"id,pk,type:uuid"`
+	UserID    uuid.UUID `json:"user_id" bun:"user_id,type:uuid,notnull"`
+	Name      string    `json:"name" example:"Working hours" bun:"name,type:varchar(255),notnull"`
+	IsDefault bool      `json:"is_default" bun:"is_default,notnull"`
+	CreatedAt time.Time `json:"created_at" bun:"created_at,type:timestamptz,notnull,default:current_timestamp"`
+	UpdatedAt time.Time `json:"updated_at" bun:"updated_at,type:timestamptz,notnull,default:current_timestamp"`
+
+	DayAvailabilities  []*DayAvailability  `json:"day_availabilities,omitempty" bun:"rel:has-many,join:id=schedule_id"`
+	DateAvailabilities []*DateAvailability `json:"date_availabilities,omitempty" bun:"rel:has-many,join:id=schedule_id"`
+	RuleAvailabilities []*RuleAvailability `json:"rule_availabilities,omitempty" bun:"rel:has-many,join:id=schedule_id"`
+} // @name Schedule
+
+var _ bun.BeforeAppendModelHook = (*Schedule)(nil)
+
+func (s *Schedule) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		s.CreatedAt = time.Now().UTC()
+		if s.ID == uuid.Nil {
+			s.ID = uuid.New()
+		}
+	case *bun.UpdateQuery:
+		s.UpdatedAt = time.Now().UTC()
+	}
+	return nil
+}
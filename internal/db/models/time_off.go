@@ -0,0 +1,40 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// TimeOff represents an out-of-office range of a user (e.g. a vacation), they are unavailable from StartMinute of StartDate
+// until EndMinute of EndDate in their timezone, whatever their schedules say.
+type TimeOff struct {
+	bun.BaseModel `bun:"table:time_offs" swaggerignore:"true"`
+
+	ID          uuid.UUID `json:"id" bun:"id,pk,type:uuid"`
+	UserID      uuid.UUID `json:"user_id" bun:"user_id,type:uuid,notnull"`
+	StartDate   time.Time `json:"start_date" bun:"start_date,type:date,notnull"`
+	EndDate     time.Time `json:"end_date" bun:"end_date,type:date,notnull"`
+	StartMinute int       `json:"start_minute" example:"0" bun:"start_minute,notnull"` // Minutes since midnight of the start date, 0 is the whole day
+	EndMinute   int       `json:"end_minute" example:"1440" bun:"end_minute,notnull"`  // Minutes since midnight of the end date, 1440 is the whole day
+	Reason      string    `json:"reason" example:"Vacation" bun:"reason,type:text"`
+	CreatedAt   time.Time `json:"created_at" bun:"created_at,type:timestamptz,notnull,default:current_timestamp"`
+	UpdatedAt   time.Time `json:"updated_at" bun:"updated_at,type:timestamptz,notnull,default:current_timestamp"`
+} // @name TimeOff
+
+var _ bun.BeforeAppendModelHook = (*TimeOff)(nil)
+
+func (t *TimeOff) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		t.CreatedAt = time.Now().UTC()
+		if t.ID == uuid.Nil {
+			t.ID = uuid.New()
+		}
+	case *bun.UpdateQuery:
+		t.UpdatedAt = time.Now().UTC()
+	}
+	return nil
+}
@@ -16,7 +16,15 @@ type User struct {
 	LastName  string    `json:"last_name" bun:"last_name,type:varchar(255)"`
 	Username  string    `json:"username" validate:"required" bun:"username,unique,notnull,type:varchar(255)"`
 	Email     string    `json:"email" bun:"email,unique,type:varchar(255)"`
-	Timezone  string    `json:"timezone" bun:"timezone,type:varchar(255)"` // timezone for future use
+	Timezone  string    `json:"timezone" example:"Europe/Berlin" bun:"timezone,type:varchar(255)"` // IANA timezone, availability slots are interpreted in it
+
+	HolidayRegion string `json:"holiday_region" example:"DE-BY" bun:"holiday_region,notnull,type:varchar(16)"` // Public holidays of the region (e.g. DE-BY) block availability, empty means none
+
+	DailyBookingCap  int `json:"daily_booking_cap" example:"4" bun:"daily_booking_cap,notnull"`    // Max confirmed bookings per day in the user's timezone, 0 means no cap
+	WeeklyBookingCap int `json:"weekly_booking_cap" example:"12" bun:"weekly_booking_cap,notnull"` // Max confirmed bookings per week (monday to sunday), 0 means no cap
+
+	CalendarFeedTokenHash string `json:"-" bun:"calendar_feed_token_hash,type:varchar(64),nullzero"` // only the hash of the iCalendar feed token is kept
+
 	CreatedAt time.Time `json:"created_at" bun:"created_at,type:timestamptz,notnull,default:current_timestamp"`
 	UpdatedAt time.Time `json:"updated_at" bun:"updated_at,type:timestamptz,notnull,default:current_timestamp"`
 } // @name User
@@ -35,3 +43,12 @@ func (u *User) BeforeAppendModel(ctx context.Context, query bun.Query) error {
 	}
 	return nil
 }
+
+// Location returns the user's timezone, UTC if it's not set or not an IANA timezone (e.g. "Local", the server's timezone)
+func (u *User) Location() *time.Location {
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil || loc == time.Local {
+		return time.UTC
+	}
+	return loc
+}
@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,47 +11,77 @@ import (
 )
 
 type AvailabilityRepo interface {
-	InsertDayAvailability(ctx context.Context, dayAvailabilities []*models.DayAvailability) error
+	InsertDayAvailability(ctx context.Context, scheduleID uuid.UUID, effectiveFrom time.Time, dayAvailabilities []*models.DayAvailability) error
 	InsertDateAvailability(ctx context.Context, dateAvailabilities *models.DateAvailability) error
-	DeleteDayAvailabilities(ctx context.Context, userID uuid.UUID) error
-	DeleteDateAvailabilities(ctx context.Context, userID uuid.UUID, date *time.Time) error
-	GetAllDayAvailabilities(ctx context.Context, userID *uuid.UUID) ([]*models.DayAvailability, error)
-	GetAllDateAvailabilities(ctx context.Context, userID *uuid.UUID, fromDate, toDate string) ([]*models.DateAvailability, error)
+	InsertDateAvailabilities(ctx context.Context, dateAvailabilities []*models.DateAvailability) error
+	ReplaceDateAvailabilities(ctx context.Context, scheduleID uuid.UUID, dateAvailabilities []*models.DateAvailability) error
+	DeleteDayAvailabilities(ctx context.Context, scheduleID uuid.UUID, effectiveFrom time.Time) error
+	UpsertDayAvailability(ctx context.Context, scheduleID uuid.UUID, effectiveFrom time.Time, dayAvailability *models.DayAvailability) error
+	DeleteDayAvailability(ctx context.Context, scheduleID uuid.UUID, effectiveFrom time.Time, day models.Day) error
+	DeleteDateAvailabilities(ctx context.Context, scheduleID uuid.UUID, fromDate, toDate *time.Time, mode models.OverrideMode) error
+	GetAllDayAvailabilities(ctx context.Context, scheduleID *uuid.UUID, fromDate, toDate string) ([]*models.DayAvailability, error)
+	GetAllDateAvailabilities(ctx context.Context, scheduleID *uuid.UUID, fromDate, toDate string) ([]*models.DateAvailability, error)
+	InsertRuleAvailability(ctx context.Context, ruleAvailability *models.RuleAvailability) error
+	DeleteRuleAvailability(ctx context.Context, userID, id uuid.UUID) error
+	GetAllRuleAvailabilities(ctx context.Context, scheduleID *uuid.UUID, toDate string) ([]*models.RuleAvailability, error)
 }
 
 type availability struct {
 	dayRepo  *baseRepo[models.DayAvailability]
 	dateRepo *baseRepo[models.DateAvailability]
+	ruleRepo *baseRepo[models.RuleAvailability]
 }
 
 func NewAvailabilityRepo(db *bun.DB) AvailabilityRepo {
 	return &availability{
 		dayRepo:  newBaseRepo[models.DayAvailability](db),
 		dateRepo: newBaseRepo[models.DateAvailability](db),
+		ruleRepo: newBaseRepo[models.RuleAvailability](db),
 	}
 }
 
-func (a *availability) InsertDayAvailability(ctx context.Context, dayAvailabilities []*models.DayAvailability) error {
+// InsertDayAvailability stores the day availabilities as the version of the schedule's weekly hours starting at effectiveFrom,
+// in one transaction: a version starting on the same date is replaced, the version in effect then ends at effectiveFrom
+// and the new one ends where the next version starts. Without day availabilities the version in effect just ends
+func (a *availability) InsertDayAvailability(ctx context.Context, scheduleID uuid.UUID, effectiveFrom time.Time, dayAvailabilities []*models.DayAvailability) error {
+	from := effectiveFrom.Format("2006-01-02")
+	return a.dayRepo.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewDelete().
+			Model((*models.DayAvailability)(nil)).
+			Where("schedule_id = ?", scheduleID).
+			Where("effective_from = ?", from).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if err := endDayAvailabilities(ctx, tx, scheduleID, from); err != nil {
+			return err
+		}
+		if len(dayAvailabilities) == 0 {
+			return nil
+		}
 
-	if err := a.DeleteDayAvailabilities(ctx, dayAvailabilities[0].UserID); err != nil {
-		return err
-	}
+		next, err := nextDayAvailabilities(ctx, tx, scheduleID, from)
+		if err != nil {
+			return err
+		}
+		for _, dayAvl := range dayAvailabilities {
+			dayAvl.EffectiveFrom = effectiveFrom
+			dayAvl.EffectiveTo = next
+		}
 
-	_, err := a.dayRepo.db.NewInsert().
-		Model(&dayAvailabilities).
-		Exec(ctx)
-	if err != nil {
+		_, err = tx.NewInsert().
+			Model(&dayAvailabilities).
+			Exec(ctx)
 		return err
-	}
-
-	return err
+	})
 }
 
 func (a *availability) InsertDateAvailability(ctx context.Context, dateAvailability *models.DateAvailability) error {
 
 	_, err := a.dateRepo.db.NewInsert().
 		Model(dateAvailability).
-		On("CONFLICT (user_id, date) DO UPDATE").
+		On("CONFLICT (schedule_id, date, mode) DO UPDATE").
 		Set("slots = EXCLUDED.slots").
 		Exec(ctx)
 	if err != nil {
@@ -60,34 +91,209 @@ func (a *availability) InsertDateAvailability(ctx context.Context, dateAvailabil
 	return err
 }
 
-func (a *availability) DeleteDayAvailabilities(ctx context.Context, userID uuid.UUID) error {
-	_, err := a.dayRepo.db.NewDelete().
+// InsertDateAvailabilities stores the date availabilities in one transaction, like InsertDateAvailability the slots of an existing
+// date availability of the same date and mode are overridden
+func (a *availability) InsertDateAvailabilities(ctx context.Context, dateAvailabilities []*models.DateAvailability) error {
+	return a.dateRepo.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewInsert().
+			Model(&dateAvailabilities).
+			On("CONFLICT (schedule_id, date, mode) DO UPDATE").
+			Set("slots = EXCLUDED.slots").
+			Exec(ctx)
+		return err
+	})
+}
+
+// ReplaceDateAvailabilities deletes the date availabilities (of every mode) the schedule has on the dates of the given ones
+// and stores the given ones instead, in one transaction
+func (a *availability) ReplaceDateAvailabilities(ctx context.Context, scheduleID uuid.UUID, dateAvailabilities []*models.DateAvailability) error {
+	dates := make([]string, 0, len(dateAvailabilities))
+	for _, dateAvl := range dateAvailabilities {
+		dates = append(dates, dateAvl.Date.Format("2006-01-02"))
+	}
+	return a.dateRepo.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewDelete().
+			Model((*models.DateAvailability)(nil)).
+			Where("schedule_id = ?", scheduleID).
+			Where("date IN (?)", bun.In(dates)).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = tx.NewInsert().
+			Model(&dateAvailabilities).
+			Exec(ctx)
+		return err
+	})
+}
+
+// DeleteDayAvailabilities ends the schedule's weekly hours at effectiveFrom, the versions starting from then on are deleted
+// and the one in effect ends, so the past versions are kept
+func (a *availability) DeleteDayAvailabilities(ctx context.Context, scheduleID uuid.UUID, effectiveFrom time.Time) error {
+	from := effectiveFrom.Format("2006-01-02")
+	return a.dayRepo.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewDelete().
+			Model((*models.DayAvailability)(nil)).
+			Where("schedule_id = ?", scheduleID).
+			Where("effective_from >= ?", from).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		return endDayAvailabilities(ctx, tx, scheduleID, from)
+	})
+}
+
+// UpsertDayAvailability sets the slots of one day from effectiveFrom on, the other days keep the slots of the weekly hours in effect then.
+// Both steps run in one transaction, see splitDayAvailabilities
+func (a *availability) UpsertDayAvailability(ctx context.Context, scheduleID uuid.UUID, effectiveFrom time.Time, dayAvailability *models.DayAvailability) error {
+	return a.dayRepo.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		effectiveTo, err := splitDayAvailabilities(ctx, tx, scheduleID, effectiveFrom)
+		if err != nil {
+			return err
+		}
+		dayAvailability.EffectiveFrom = effectiveFrom
+		dayAvailability.EffectiveTo = effectiveTo
+
+		_, err = tx.NewInsert().
+			Model(dayAvailability).
+			On("CONFLICT (schedule_id, day, effective_from) DO UPDATE").
+			Set("slots = EXCLUDED.slots").
+			Set("updated_at = CURRENT_TIMESTAMP").
+			Returning("*").
+			Exec(ctx)
+		return err
+	})
+}
+
+// DeleteDayAvailability removes one day from effectiveFrom on, the other days keep the slots of the weekly hours in effect then.
+// Both steps run in one transaction, see splitDayAvailabilities
+func (a *availability) DeleteDayAvailability(ctx context.Context, scheduleID uuid.UUID, effectiveFrom time.Time, day models.Day) error {
+	return a.dayRepo.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := splitDayAvailabilities(ctx, tx, scheduleID, effectiveFrom); err != nil {
+			return err
+		}
+		_, err := tx.NewDelete().
+			Model((*models.DayAvailability)(nil)).
+			Where("schedule_id = ?", scheduleID).
+			Where("day = ?", day).
+			Where("effective_from = ?", effectiveFrom.Format("2006-01-02")).
+			Exec(ctx)
+		return err
+	})
+}
+
+// splitDayAvailabilities makes a version of the schedule's weekly hours start on the date: the version in effect then is ended
+// at the date and its days are copied into a new version starting on it. The end of the version starting on the date is returned
+func splitDayAvailabilities(ctx context.Context, tx bun.Tx, scheduleID uuid.UUID, date time.Time) (*time.Time, error) {
+	from := date.Format("2006-01-02")
+	var current []*models.DayAvailability
+	err := tx.NewSelect().
+		Model(&current).
+		Where("schedule_id = ?", scheduleID).
+		Where("effective_from <= ?", from).
+		Where("effective_to IS NULL OR effective_to > ?", from).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(current) == 0 {
+		return nextDayAvailabilities(ctx, tx, scheduleID, from)
+	}
+	effectiveTo := current[0].EffectiveTo
+	if current[0].EffectiveFrom.Format("2006-01-02") == from {
+		return effectiveTo, nil
+	}
+
+	if err := endDayAvailabilities(ctx, tx, scheduleID, from); err != nil {
+		return nil, err
+	}
+	copies := make([]*models.DayAvailability, 0, len(current))
+	for _, dayAvl := range current {
+		copies = append(copies, &models.DayAvailability{
+			Day:           dayAvl.Day,
+			UserID:        dayAvl.UserID,
+			ScheduleID:    dayAvl.ScheduleID,
+			Slots:         dayAvl.Slots,
+			EffectiveFrom: date,
+			EffectiveTo:   effectiveTo,
+		})
+	}
+	if _, err := tx.NewInsert().Model(&copies).Exec(ctx); err != nil {
+		return nil, err
+	}
+	return effectiveTo, nil
+}
+
+// nextDayAvailabilities returns the date the first version of the schedule's weekly hours after the date starts on, nil if there is none
+func nextDayAvailabilities(ctx context.Context, tx bun.Tx, scheduleID uuid.UUID, date string) (*time.Time, error) {
+	var next sql.NullTime
+	err := tx.NewSelect().
 		Model((*models.DayAvailability)(nil)).
-		Where("user_id = ?", userID).
+		ColumnExpr("MIN(effective_from)").
+		Where("schedule_id = ?", scheduleID).
+		Where("effective_from > ?", date).
+		Scan(ctx, &next)
+	if err != nil || !next.Valid {
+		return nil, err
+	}
+	return &next.Time, nil
+}
+
+// endDayAvailabilities ends the version of the schedule's weekly hours in effect on the date at that date
+func endDayAvailabilities(ctx context.Context, tx bun.Tx, scheduleID uuid.UUID, date string) error {
+	_, err := tx.NewUpdate().
+		Model((*models.DayAvailability)(nil)).
+		Set("effective_to = ?", date).
+		Set("updated_at = CURRENT_TIMESTAMP").
+		Where("schedule_id = ?", scheduleID).
+		Where("effective_from < ?", date).
+		Where("effective_to IS NULL OR effective_to > ?", date).
 		Exec(ctx)
 	return err
 }
 
-func (a *availability) DeleteDateAvailabilities(ctx context.Context, userID uuid.UUID, date *time.Time) error {
+// DeleteDateAvailabilities deletes the date availabilities of the schedule, only of the dates from fromDate to toDate (both inclusive, nil
+// is not bounding) and of the mode when given. It's a single statement, so a range is deleted as a whole or not at all
+func (a *availability) DeleteDateAvailabilities(ctx context.Context, scheduleID uuid.UUID, fromDate, toDate *time.Time, mode models.OverrideMode) error {
 	query := a.dateRepo.db.NewDelete().
 		Model((*models.DateAvailability)(nil)).
-		Where("user_id = ?", userID)
+		Where("schedule_id = ?", scheduleID)
 
-	if date != nil {
-		query = query.Where("date = ?", date.Format("2006-01-02"))
+	if fromDate != nil {
+		query = query.Where("date >= ?", fromDate.Format("2006-01-02"))
+	}
+	if toDate != nil {
+		query = query.Where("date <= ?", toDate.Format("2006-01-02"))
+	}
+	if mode != "" {
+		query = query.Where("mode = ?", mode)
 	}
 	_, err := query.Exec(ctx)
 	return err
 }
 
-func (a *availability) GetAllDayAvailabilities(ctx context.Context, userID *uuid.UUID) ([]*models.DayAvailability, error) {
-	if userID == nil {
-		return a.dayRepo.GetAll(ctx, "")
+// GetAllDayAvailabilities returns the day availabilities of every version of the weekly hours in effect on any date from fromDate to toDate,
+// empty dates are not bounding. They are sorted by version
+func (a *availability) GetAllDayAvailabilities(ctx context.Context, scheduleID *uuid.UUID, fromDate, toDate string) ([]*models.DayAvailability, error) {
+	var dayAvls []*models.DayAvailability
+	query := a.dayRepo.db.NewSelect().Model(&dayAvls)
+	if fromDate != "" {
+		query = query.Where("effective_to IS NULL OR effective_to > ?", fromDate)
+	}
+	if toDate != "" {
+		query = query.Where("effective_from <= ?", toDate)
 	}
-	return a.dayRepo.FindByColumn(ctx, "user_id", userID.String(), "")
+	if scheduleID != nil {
+		query = query.Where("schedule_id = ?", scheduleID.String())
+	}
+	if err := query.OrderExpr("effective_from ASC").Scan(ctx); err != nil {
+		return nil, err
+	}
+	return dayAvls, nil
 }
 
-func (a *availability) GetAllDateAvailabilities(ctx context.Context, userID *uuid.UUID, fromDate, toDate string) ([]*models.DateAvailability, error) {
+func (a *availability) GetAllDateAvailabilities(ctx context.Context, scheduleID *uuid.UUID, fromDate, toDate string) ([]*models.DateAvailability, error) {
 	var dateAvls []*models.DateAvailability
 	query := a.dateRepo.db.NewSelect().Model(&dateAvls)
 	if fromDate != "" {
@@ -96,11 +302,47 @@ func (a *availability) GetAllDateAvailabilities(ctx context.Context, userID *uui
 	if toDate != "" {
 		query = query.Where("date <= ?", toDate)
 	}
-	if userID != nil {
-		query = query.Where("user_id = ?", userID.String())
+	if scheduleID != nil {
+		query = query.Where("schedule_id = ?", scheduleID.String())
 	}
 	if err := query.Scan(ctx); err != nil {
 		return nil, err
 	}
 	return dateAvls, nil
 }
+
+func (a *availability) InsertRuleAvailability(ctx context.Context, ruleAvailability *models.RuleAvailability) error {
+	return a.ruleRepo.Insert(ctx, ruleAvailability)
+}
+
+// DeleteRuleAvailability deletes the rule only if it belongs to the user, sql.ErrNoRows is returned otherwise
+func (a *availability) DeleteRuleAvailability(ctx context.Context, userID, id uuid.UUID) error {
+	res, err := a.ruleRepo.db.NewDelete().
+		Model((*models.RuleAvailability)(nil)).
+		Where("id = ?", id).
+		Where("user_id = ?", userID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetAllRuleAvailabilities returns the rules of the schedule that start on or before toDate, the ones that could occur till then
+func (a *availability) GetAllRuleAvailabilities(ctx context.Context, scheduleID *uuid.UUID, toDate string) ([]*models.RuleAvailability, error) {
+	var ruleAvls []*models.RuleAvailability
+	query := a.ruleRepo.db.NewSelect().Model(&ruleAvls)
+	if toDate != "" {
+		query = query.Where("start_date <= ?", toDate)
+	}
+	if scheduleID != nil {
+		query = query.Where("schedule_id = ?", scheduleID.String())
+	}
+	if err := query.OrderExpr("created_at ASC").Scan(ctx); err != nil {
+		return nil, err
+	}
+	return ruleAvls, nil
+}
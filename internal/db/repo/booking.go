@@ -0,0 +1,149 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/niharika88/calendly-api/internal/db/models"
+	"github.com/uptrace/bun"
+)
+
+// ErrBookingNotConfirmed is returned by UpdateWithChange when the booking isn't confirmed anymore
+var ErrBookingNotConfirmed = errors.New("booking is not confirmed")
+
+// BookingCheck validates a booking while its host's bookings are locked, see InsertChecked
+type BookingCheck func(ctx context.Context) error
+
+type BookingRepo interface {
+	Insert(ctx context.Context, booking *models.Booking) error
+	InsertChecked(ctx context.Context, booking *models.Booking, check BookingCheck) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Booking, error)
+	FindByTokenHash(ctx context.Context, tokenHash string) (*models.Booking, error)
+	GetBookings(ctx context.Context, userID uuid.UUID, from, to time.Time, status models.BookingStatus, association bool) ([]*models.Booking, error)
+	UpdateWithChange(ctx context.Context, booking *models.Booking, change *models.BookingChange, check BookingCheck) error
+}
+
+type booking struct {
+	*baseRepo[models.Booking]
+}
+
+func NewBookingRepo(db *bun.DB) BookingRepo {
+	return &booking{
+		baseRepo: newBaseRepo[models.Booking](db),
+	}
+}
+
+func (b *booking) Insert(ctx context.Context, booking *models.Booking) error {
+	return b.baseRepo.Insert(ctx, booking)
+}
+
+// InsertChecked inserts the booking once check passes, the host's bookings are locked from the check until the insert commits
+// so that the checks of concurrent bookings (e.g. buffers around the other bookings) see each other. The check runs on its own
+// connections, it reads what's committed. Without a check the booking is inserted right away
+func (b *booking) InsertChecked(ctx context.Context, booking *models.Booking, check BookingCheck) error {
+	if check == nil {
+		return b.Insert(ctx, booking)
+	}
+	return b.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := lockHostBookings(ctx, tx, booking.UserID); err != nil {
+			return err
+		}
+		if err := check(ctx); err != nil {
+			return err
+		}
+		_, err := tx.NewInsert().Model(booking).Exec(ctx)
+		return err
+	})
+}
+
+// FindByID returns the booking along with its history of changes
+func (b *booking) FindByID(ctx context.Context, id uuid.UUID) (*models.Booking, error) {
+	booking := new(models.Booking)
+	if err := b.db.NewSelect().
+		Model(booking).
+		Relation("Changes", orderChanges).
+		Where("id = ?", id).
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+	return booking, nil
+}
+
+func (b *booking) FindByTokenHash(ctx context.Context, tokenHash string) (*models.Booking, error) {
+	booking := new(models.Booking)
+	if err := b.db.NewSelect().
+		Model(booking).
+		Relation("Changes", orderChanges).
+		Where("token_hash = ?", tokenHash).
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+	return booking, nil
+}
+
+// GetBookings returns the bookings of a user that overlap with [from, to), empty status returns all bookings,
+// association loads the history of changes as well
+func (b *booking) GetBookings(ctx context.Context, userID uuid.UUID, from, to time.Time, status models.BookingStatus, association bool) ([]*models.Booking, error) {
+	var bookings []*models.Booking
+	query := b.db.NewSelect().
+		Model(&bookings).
+		Where("user_id = ?", userID).
+		Where("start_time < ?", to).
+		Where("end_time > ?", from)
+	if association {
+		query = query.Relation("Changes", orderChanges)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.OrderExpr("start_time ASC").Scan(ctx); err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+// UpdateWithChange updates the booking and records the change in its history in a single transaction, only while it's
+// confirmed in the database: ErrBookingNotConfirmed when it was cancelled in the meantime. The update is checked under
+// the host's lock like InsertChecked, when a check is given
+func (b *booking) UpdateWithChange(ctx context.Context, booking *models.Booking, change *models.BookingChange, check BookingCheck) error {
+	return b.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if check != nil {
+			if err := lockHostBookings(ctx, tx, booking.UserID); err != nil {
+				return err
+			}
+			if err := check(ctx); err != nil {
+				return err
+			}
+		}
+		res, err := tx.NewUpdate().
+			Model(booking).
+			WherePK().
+			Where("status = ?", models.BookingStatusConfirmed).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return ErrBookingNotConfirmed
+		}
+		if _, err := tx.NewInsert().Model(change).Exec(ctx); err != nil {
+			return err
+		}
+		booking.Changes = append(booking.Changes, change)
+		return nil
+	})
+}
+
+// lockHostBookings takes the advisory lock of the user's bookings, it's held until the transaction ends
+func lockHostBookings(ctx context.Context, tx bun.Tx, userID uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtextextended(?, 0))", "bookings:"+userID.String())
+	return err
+}
+
+func orderChanges(q *bun.SelectQuery) *bun.SelectQuery {
+	return q.OrderExpr("created_at ASC")
+}
@@ -0,0 +1,63 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/niharika88/calendly-api/internal/db/models"
+	"github.com/uptrace/bun"
+)
+
+type BusyBlockRepo interface {
+	ReplaceByUIDs(ctx context.Context, userID uuid.UUID, source string, uids []string, busyBlocks []*models.BusyBlock) error
+	GetAllByUser(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*models.BusyBlock, error)
+}
+
+type busyBlock struct {
+	*baseRepo[models.BusyBlock]
+}
+
+func NewBusyBlockRepo(db *bun.DB) BusyBlockRepo {
+	return &busyBlock{
+		baseRepo: newBaseRepo[models.BusyBlock](db),
+	}
+}
+
+// ReplaceByUIDs deletes the user's busy blocks of the source with one of the UIDs and stores the given ones instead,
+// in one transaction, so importing the same events again doesn't duplicate them
+func (b *busyBlock) ReplaceByUIDs(ctx context.Context, userID uuid.UUID, source string, uids []string, busyBlocks []*models.BusyBlock) error {
+	if len(uids) == 0 {
+		return nil
+	}
+	return b.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewDelete().
+			Model((*models.BusyBlock)(nil)).
+			Where("user_id = ?", userID).
+			Where("source = ?", source).
+			Where("uid IN (?)", bun.In(uids)).
+			Exec(ctx)
+		if err != nil || len(busyBlocks) == 0 {
+			return err
+		}
+		_, err = tx.NewInsert().
+			Model(&busyBlocks).
+			Exec(ctx)
+		return err
+	})
+}
+
+// GetAllByUser returns the busy blocks of the user that overlap with [from, to)
+func (b *busyBlock) GetAllByUser(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*models.BusyBlock, error) {
+	var busyBlocks []*models.BusyBlock
+	if err := b.db.NewSelect().
+		Model(&busyBlocks).
+		Where("user_id = ?", userID).
+		Where("start_time < ?", to).
+		Where("end_time > ?", from).
+		OrderExpr("start_time ASC").
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+	return busyBlocks, nil
+}
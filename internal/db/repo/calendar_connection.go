@@ -0,0 +1,133 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/niharika88/calendly-api/internal/db/models"
+	"github.com/uptrace/bun"
+)
+
+// ErrConcurrentSync is returned by ApplySync when another sync of the connection was applied since its sync token was read
+var ErrConcurrentSync = errors.New("the calendar connection was synced concurrently")
+
+type CalendarConnectionRepo interface {
+	Insert(ctx context.Context, connection *models.CalendarConnection) error
+	Update(ctx context.Context, connection *models.CalendarConnection) error
+	UpdateLastError(ctx context.Context, connection *models.CalendarConnection) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindByID(ctx context.Context, userID, id uuid.UUID) (*models.CalendarConnection, error)
+	GetAllByUser(ctx context.Context, userID uuid.UUID) ([]*models.CalendarConnection, error)
+	GetAll(ctx context.Context) ([]*models.CalendarConnection, error)
+	ApplySync(ctx context.Context, connection *models.CalendarConnection, previousSyncToken string, full bool, uids []string, busyBlocks []*models.BusyBlock) error
+}
+
+type calendarConnection struct {
+	*baseRepo[models.CalendarConnection]
+}
+
+func NewCalendarConnectionRepo(db *bun.DB) CalendarConnectionRepo {
+	return &calendarConnection{
+		baseRepo: newBaseRepo[models.CalendarConnection](db),
+	}
+}
+
+func (c *calendarConnection) Insert(ctx context.Context, connection *models.CalendarConnection) error {
+	return c.baseRepo.Insert(ctx, connection)
+}
+
+func (c *calendarConnection) Update(ctx context.Context, connection *models.CalendarConnection) error {
+	return c.baseRepo.Update(ctx, connection)
+}
+
+// UpdateLastError only saves the error of the last sync, leaving the sync token and times to the syncs that succeed
+func (c *calendarConnection) UpdateLastError(ctx context.Context, connection *models.CalendarConnection) error {
+	_, err := c.db.NewUpdate().
+		Model(connection).
+		Column("last_error", "updated_at").
+		WherePK().
+		Exec(ctx)
+	return err
+}
+
+// Delete removes the connection, its busy blocks are deleted by the foreign key
+func (c *calendarConnection) Delete(ctx context.Context, id uuid.UUID) error {
+	return c.baseRepo.Delete(ctx, id)
+}
+
+// FindByID returns the connection only if it belongs to the user
+func (c *calendarConnection) FindByID(ctx context.Context, userID, id uuid.UUID) (*models.CalendarConnection, error) {
+	connection := new(models.CalendarConnection)
+	if err := c.db.NewSelect().
+		Model(connection).
+		Where("id = ?", id).
+		Where("user_id = ?", userID).
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+	return connection, nil
+}
+
+func (c *calendarConnection) GetAllByUser(ctx context.Context, userID uuid.UUID) ([]*models.CalendarConnection, error) {
+	var connections []*models.CalendarConnection
+	if err := c.db.NewSelect().
+		Model(&connections).
+		Where("user_id = ?", userID).
+		OrderExpr("created_at ASC").
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+	return connections, nil
+}
+
+// GetAll returns the connections of every user, for the periodic sync
+func (c *calendarConnection) GetAll(ctx context.Context) ([]*models.CalendarConnection, error) {
+	var connections []*models.CalendarConnection
+	if err := c.db.NewSelect().
+		Model(&connections).
+		OrderExpr("created_at ASC").
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+	return connections, nil
+}
+
+// ApplySync stores the result of a sync in one transaction: the busy blocks of the connection with one of the (changed or deleted)
+// UIDs, or all of them for a full sync, are replaced by the given ones and the connection is saved with its new sync token.
+// A failed sync leaves both untouched, so the next one pulls the same changes again. The connection's row is locked so syncs
+// of a connection are applied one at a time, and only on top of the sync token they started from (ErrConcurrentSync otherwise)
+func (c *calendarConnection) ApplySync(ctx context.Context, connection *models.CalendarConnection, previousSyncToken string, full bool, uids []string, busyBlocks []*models.BusyBlock) error {
+	return c.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var syncToken string
+		if err := tx.NewSelect().
+			Model((*models.CalendarConnection)(nil)).
+			ColumnExpr("coalesce(sync_token, '')").
+			Where("id = ?", connection.ID).
+			For("UPDATE").
+			Scan(ctx, &syncToken); err != nil {
+			return err
+		}
+		if syncToken != previousSyncToken {
+			return ErrConcurrentSync
+		}
+		if full || len(uids) > 0 {
+			query := tx.NewDelete().
+				Model((*models.BusyBlock)(nil)).
+				Where("connection_id = ?", connection.ID)
+			if !full {
+				query = query.Where("uid IN (?)", bun.In(uids))
+			}
+			if _, err := query.Exec(ctx); err != nil {
+				return err
+			}
+		}
+		if len(busyBlocks) > 0 {
+			if _, err := tx.NewInsert().Model(&busyBlocks).Exec(ctx); err != nil {
+				return err
+			}
+		}
+		_, err := tx.NewUpdate().Model(connection).WherePK().Exec(ctx)
+		return err
+	})
+}
@@ -0,0 +1,68 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/niharika88/calendly-api/internal/db/models"
+	"github.com/uptrace/bun"
+)
+
+type EventTypeRepo interface {
+	Insert(ctx context.Context, eventType *models.EventType) error
+	Update(ctx context.Context, eventType *models.EventType) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.EventType, error)
+	FindBySlug(ctx context.Context, userID uuid.UUID, slug string) (*models.EventType, error)
+	GetAllByUser(ctx context.Context, userID uuid.UUID) ([]*models.EventType, error)
+}
+
+type eventType struct {
+	*baseRepo[models.EventType]
+}
+
+func NewEventTypeRepo(db *bun.DB) EventTypeRepo {
+	return &eventType{
+		baseRepo: newBaseRepo[models.EventType](db),
+	}
+}
+
+func (e *eventType) Insert(ctx context.Context, eventType *models.EventType) error {
+	return e.baseRepo.Insert(ctx, eventType)
+}
+
+func (e *eventType) Update(ctx context.Context, eventType *models.EventType) error {
+	return e.baseRepo.Update(ctx, eventType)
+}
+
+func (e *eventType) Delete(ctx context.Context, id uuid.UUID) error {
+	return e.baseRepo.Delete(ctx, id)
+}
+
+func (e *eventType) FindByID(ctx context.Context, id uuid.UUID) (*models.EventType, error) {
+	return e.baseRepo.FindByID(ctx, id, "")
+}
+
+func (e *eventType) FindBySlug(ctx context.Context, userID uuid.UUID, slug string) (*models.EventType, error) {
+	eventType := new(models.EventType)
+	if err := e.db.NewSelect().
+		Model(eventType).
+		Where("user_id = ?", userID).
+		Where("slug = ?", slug).
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+	return eventType, nil
+}
+
+func (e *eventType) GetAllByUser(ctx context.Context, userID uuid.UUID) ([]*models.EventType, error) {
+	var eventTypes []*models.EventType
+	if err := e.db.NewSelect().
+		Model(&eventTypes).
+		Where("user_id = ?", userID).
+		OrderExpr("created_at ASC").
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+	return eventTypes, nil
+}
@@ -0,0 +1,134 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/niharika88/calendly-api/internal/db/models"
+	"github.com/uptrace/bun"
+)
+
+type ScheduleRepo interface {
+	Insert(ctx context.Context, schedule *models.Schedule) error
+	Update(ctx context.Context, schedule *models.Schedule) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindByID(ctx context.Context, userID, id uuid.UUID, association bool, fromDate string) (*models.Schedule, error)
+	FindDefault(ctx context.Context, userID uuid.UUID) (*models.Schedule, error)
+	FindOrCreateDefault(ctx context.Context, schedule *models.Schedule) (*models.Schedule, error)
+	GetAllByUser(ctx context.Context, userID uuid.UUID) ([]*models.Schedule, error)
+}
+
+type schedule struct {
+	*baseRepo[models.Schedule]
+}
+
+func NewScheduleRepo(db *bun.DB) ScheduleRepo {
+	return &schedule{
+		baseRepo: newBaseRepo[models.Schedule](db),
+	}
+}
+
+// Insert creates the schedule, if it's the default one the user's previous default is unset in the same transaction
+func (s *schedule) Insert(ctx context.Context, schedule *models.Schedule) error {
+	return s.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := unsetDefaultSchedule(ctx, tx, schedule); err != nil {
+			return err
+		}
+		_, err := tx.NewInsert().Model(schedule).Exec(ctx)
+		return err
+	})
+}
+
+// Update saves the schedule, if it's the default one the user's previous default is unset in the same transaction
+func (s *schedule) Update(ctx context.Context, schedule *models.Schedule) error {
+	return s.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := unsetDefaultSchedule(ctx, tx, schedule); err != nil {
+			return err
+		}
+		_, err := tx.NewUpdate().Model(schedule).WherePK().Exec(ctx)
+		return err
+	})
+}
+
+func (s *schedule) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.baseRepo.Delete(ctx, id)
+}
+
+// FindByID returns the schedule only if it belongs to the user, association loads its day (of the weekly hours in effect on
+// fromDate, the user's today, and later ones), date and rule availabilities as well
+func (s *schedule) FindByID(ctx context.Context, userID, id uuid.UUID, association bool, fromDate string) (*models.Schedule, error) {
+	schedule := new(models.Schedule)
+	query := s.db.NewSelect().
+		Model(schedule).
+		Where("id = ?", id).
+		Where("user_id = ?", userID)
+	if association {
+		query = query.
+			Relation("DayAvailabilities", func(q *bun.SelectQuery) *bun.SelectQuery {
+				return q.Where("effective_to IS NULL OR effective_to > ?", fromDate).OrderExpr("effective_from ASC")
+			}).
+			Relation("DateAvailabilities", func(q *bun.SelectQuery) *bun.SelectQuery {
+				return q.OrderExpr("date ASC")
+			}).
+			Relation("RuleAvailabilities", func(q *bun.SelectQuery) *bun.SelectQuery {
+				return q.OrderExpr("created_at ASC")
+			})
+	}
+	if err := query.Scan(ctx); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (s *schedule) FindDefault(ctx context.Context, userID uuid.UUID) (*models.Schedule, error) {
+	schedule := new(models.Schedule)
+	if err := s.db.NewSelect().
+		Model(schedule).
+		Where("user_id = ?", userID).
+		Where("is_default").
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// FindOrCreateDefault returns the user's default schedule, schedule is inserted as the default one when there's none yet.
+// Concurrent calls all get the same schedule, the one inserted first
+func (s *schedule) FindOrCreateDefault(ctx context.Context, schedule *models.Schedule) (*models.Schedule, error) {
+	schedule.IsDefault = true
+	if _, err := s.db.NewInsert().
+		Model(schedule).
+		On("CONFLICT (user_id) WHERE is_default DO NOTHING").
+		Exec(ctx); err != nil {
+		return nil, err
+	}
+	return s.FindDefault(ctx, schedule.UserID)
+}
+
+func (s *schedule) GetAllByUser(ctx context.Context, userID uuid.UUID) ([]*models.Schedule, error) {
+	var schedules []*models.Schedule
+	if err := s.db.NewSelect().
+		Model(&schedules).
+		Where("user_id = ?", userID).
+		OrderExpr("created_at ASC").
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// unsetDefaultSchedule makes the user's other schedules non default when schedule is becoming the default one
+func unsetDefaultSchedule(ctx context.Context, tx bun.Tx, schedule *models.Schedule) error {
+	if !schedule.IsDefault {
+		return nil
+	}
+	_, err := tx.NewUpdate().
+		Table("schedules").
+		Set("is_default = FALSE").
+		Set("updated_at = CURRENT_TIMESTAMP").
+		Where("user_id = ?", schedule.UserID).
+		Where("id != ?", schedule.ID).
+		Where("is_default").
+		Exec(ctx)
+	return err
+}
@@ -0,0 +1,70 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/niharika88/calendly-api/internal/db/models"
+	"github.com/uptrace/bun"
+)
+
+type TimeOffRepo interface {
+	Insert(ctx context.Context, timeOff *models.TimeOff) error
+	Update(ctx context.Context, timeOff *models.TimeOff) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindByID(ctx context.Context, userID, id uuid.UUID) (*models.TimeOff, error)
+	GetAllByUser(ctx context.Context, userID uuid.UUID, fromDate, toDate string) ([]*models.TimeOff, error)
+}
+
+type timeOff struct {
+	*baseRepo[models.TimeOff]
+}
+
+func NewTimeOffRepo(db *bun.DB) TimeOffRepo {
+	return &timeOff{
+		baseRepo: newBaseRepo[models.TimeOff](db),
+	}
+}
+
+func (t *timeOff) Insert(ctx context.Context, timeOff *models.TimeOff) error {
+	return t.baseRepo.Insert(ctx, timeOff)
+}
+
+func (t *timeOff) Update(ctx context.Context, timeOff *models.TimeOff) error {
+	return t.baseRepo.Update(ctx, timeOff)
+}
+
+func (t *timeOff) Delete(ctx context.Context, id uuid.UUID) error {
+	return t.baseRepo.Delete(ctx, id)
+}
+
+// FindByID returns the time off only if it belongs to the user
+func (t *timeOff) FindByID(ctx context.Context, userID, id uuid.UUID) (*models.TimeOff, error) {
+	timeOff := new(models.TimeOff)
+	if err := t.db.NewSelect().
+		Model(timeOff).
+		Where("id = ?", id).
+		Where("user_id = ?", userID).
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+	return timeOff, nil
+}
+
+// GetAllByUser returns the time offs of the user overlapping the dates fromDate to toDate (both inclusive), empty dates are not limiting
+func (t *timeOff) GetAllByUser(ctx context.Context, userID uuid.UUID, fromDate, toDate string) ([]*models.TimeOff, error) {
+	var timeOffs []*models.TimeOff
+	query := t.db.NewSelect().
+		Model(&timeOffs).
+		Where("user_id = ?", userID)
+	if fromDate != "" {
+		query = query.Where("end_date >= ?", fromDate)
+	}
+	if toDate != "" {
+		query = query.Where("start_date <= ?", toDate)
+	}
+	if err := query.OrderExpr("start_date ASC").Scan(ctx); err != nil {
+		return nil, err
+	}
+	return timeOffs, nil
+}
@@ -17,6 +17,7 @@ type UserRepo interface {
 	Delete(ctx context.Context, id uuid.UUID) error
 	FindByID(ctx context.Context, id uuid.UUID, association bool) (*models.User, error)
 	FindByColumn(ctx context.Context, filterColumnName, filterColumnValue string) ([]*models.User, error)
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
 }
 
 type user struct {
@@ -58,3 +59,17 @@ func (u *user) FindByID(ctx context.Context, id uuid.UUID, association bool) (*m
 func (u *user) FindByColumn(ctx context.Context, filterColumnName, filterColumnValue string) ([]*models.User, error) {
 	return u.baseRepo.FindByColumn(ctx, filterColumnName, filterColumnValue, "")
 }
+
+// FindByEmail finds the user by email ignoring case, an exact match comes first when emails only differ by case
+func (u *user) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	user := new(models.User)
+	if err := u.db.NewSelect().
+		Model(user).
+		Where("lower(email) = lower(?)", email).
+		OrderExpr("email = ? DESC, created_at", email).
+		Limit(1).
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
@@ -1,12 +1,17 @@
 package handlers
 
 import (
+	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
-	_ "github.com/niharika88/calendly-api/internal/db/models"
+	"github.com/niharika88/calendly-api/configs"
+	"github.com/niharika88/calendly-api/internal/db/models"
 	"github.com/niharika88/calendly-api/pkg/api"
 )
 
@@ -14,13 +19,15 @@ import (
 //
 //	@Summary		Create day availability
 //	@Description	handles the creation of day-based availability
-//	@Description	every request overrides the existing availability for all days
+//	@Description	every request overrides the existing availability for all days of the schedule (the user's default one when `schedule_id` is not given)
+//	@Description	from `effective_from` on (today by default), the weekly hours applying before are kept and a version starting later still applies from its own date
 //	@Description	if day is not provided, no availability is created for that day
+//	@Description	slots are sorted and overlapping/adjacent ones merged (a day given twice gets the slots of both), `warnings` describe every merge
 //	@Tags			availability
 //	@Accept			json
 //	@Produce		json
 //	@Param			request	body		api.CreateDayAvailabilityRequest	true	"DayAvailabilityRequest"
-//	@Success		201		{array}		models.DayAvailability
+//	@Success		201		{object}	api.DayAvailabilityResponse
 //	@Failure		400		{object}	api.Response
 //	@Failure		401		{object}	api.Response
 //	@Failure		404		{object}	api.Response
@@ -40,6 +47,12 @@ func (h *handler) CreateDayAvailability(c echo.Context) error {
 	if err != nil {
 		return err
 	}
+	effectiveFrom, err := api.EffectiveDate(req.EffectiveFrom, user.Location())
+	if err != nil {
+		return err
+	}
+	req.EffectiveFrom = &effectiveFrom
+
 	dayAvailability, err := h.availabilityService.CreateDayAvailability(c.Request().Context(), user.ID, req)
 	if err != nil {
 		return api.CustomErr(http.StatusInternalServerError, api.InternalServerErr, err)
@@ -52,13 +65,15 @@ func (h *handler) CreateDayAvailability(c echo.Context) error {
 //
 //	@Summary		Create date availability
 //	@Description	handles the creation of date-specific availability
-//	@Description	every request overrides the existing availability for that date
-//	@Description	date availability ALWAYS overrides the day availability
+//	@Description	every request overrides the existing date availability of the same mode for that date
+//	@Description	`replace` (default) date availability ALWAYS overrides the day availability of the same schedule (the user's default one when `schedule_id` is not given)
+//	@Description	`add` slots are added to the availability of the date and `block` slots are removed from it (e.g. out 2-3pm next tuesday)
+//	@Description	date and slots are interpreted in the user's timezone, slots are normalized like day availability
 //	@Tags			availability
 //	@Accept			json
 //	@Produce		json
 //	@Param			request	body		api.CreateDateAvailabilityRequest	true	"DateAvailabilityRequest"
-//	@Success		201		{object}	models.DateAvailability
+//	@Success		201		{object}	api.DateAvailabilityResponse
 //	@Failure		400		{object}	api.Response
 //	@Failure		401		{object}	api.Response
 //	@Failure		404		{object}	api.Response
@@ -79,6 +94,9 @@ func (h *handler) CreateDateAvailability(c echo.Context) error {
 	if err != nil {
 		return err
 	}
+	if err := req.ValidateDate(user.Location()); err != nil {
+		return err
+	}
 
 	dateAvailability, err := h.availabilityService.CreateDateAvailability(c.Request().Context(), user.ID, req)
 	if err != nil {
@@ -88,16 +106,100 @@ func (h *handler) CreateDateAvailability(c echo.Context) error {
 	return c.JSON(http.StatusCreated, dateAvailability)
 }
 
+// CreateDateRangeAvailability godoc
+//
+//	@Summary		Create date availability over a range
+//	@Description	handles the creation of the same date-specific availability on every date from `start_date` to `end_date` (both inclusive, at most a year)
+//	@Description	only on the given `days` of the week when not empty (e.g. holiday week hours on weekdays only)
+//	@Description	every date behaves like a date availability of the given mode, all of them are created in one transaction
+//	@Tags			availability
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		api.CreateDateRangeAvailabilityRequest	true	"CreateDateRangeAvailabilityRequest"
+//	@Success		201		{object}	api.DateRangeAvailabilityResponse
+//	@Failure		400		{object}	api.Response
+//	@Failure		401		{object}	api.Response
+//	@Failure		404		{object}	api.Response
+//	@Failure		500		{object}	api.Response
+//	@Router			/availability/date/range [post]
+func (h *handler) CreateDateRangeAvailability(c echo.Context) error {
+	req := &api.CreateDateRangeAvailabilityRequest{}
+	if err := h.bindAndValidate(c, req); err != nil {
+		return err
+	}
+	slog.Info("CreateDateRangeAvailability", "req", req)
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	user, err := h.userService.GetByUsername(c.Request().Context(), req.Username)
+	if err != nil {
+		return err
+	}
+	if err := req.ValidateDate(user.Location()); err != nil {
+		return err
+	}
+
+	dateAvailabilities, err := h.availabilityService.CreateDateRangeAvailability(c.Request().Context(), user.ID, req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, dateAvailabilities)
+}
+
+// CreateRuleAvailability godoc
+//
+//	@Summary		Create rule availability
+//	@Description	handles the creation of recurring availability given as an iCalendar RRULE (e.g. every other friday, first monday of the month)
+//	@Description	the rule starts at `start_date` (DTSTART) and skips `exdates` (EXDATE), dates and slots are interpreted in the user's timezone
+//	@Description	on dates a rule occurs on its slots replace the day availability, date availability still overrides both
+//	@Description	slots are normalized like day availability
+//	@Tags			availability
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		api.CreateRuleAvailabilityRequest	true	"RuleAvailabilityRequest"
+//	@Success		201		{object}	api.RuleAvailabilityResponse
+//	@Failure		400		{object}	api.Response
+//	@Failure		401		{object}	api.Response
+//	@Failure		404		{object}	api.Response
+//	@Failure		500		{object}	api.Response
+//	@Router			/availability/rule [post]
+func (h *handler) CreateRuleAvailability(c echo.Context) error {
+	req := &api.CreateRuleAvailabilityRequest{}
+	if err := h.bindAndValidate(c, req); err != nil {
+		return err
+	}
+	slog.Info("CreateRuleAvailability", "req", req)
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	user, err := h.userService.GetByUsername(c.Request().Context(), req.Username)
+	if err != nil {
+		return err
+	}
+	ruleAvailability, err := h.availabilityService.CreateRuleAvailability(c.Request().Context(), user.ID, req)
+	if err != nil {
+		return api.CustomErr(http.StatusInternalServerError, api.InternalServerErr, err)
+	}
+
+	return c.JSON(http.StatusCreated, ruleAvailability)
+}
+
 // GetAvailability godoc
 //
 //	@Summary		Get availability
 //	@Description	handles the retrieval of overall user availability across a range of dates, takes both day/date into account
+//	@Description	user's slots are interpreted in their own timezone, dates and slots of the response are in `tz` (UTC by default)
+//	@Description	slots come from the given `schedule` (ID), the user's default schedule otherwise
 //	@Tags			availability
 //	@Accept			json
 //	@Produce		json
 //	@Param			username	query		string	true	"Username"
-//	@Param			startDate	query		string	true	"Start Date"	default(2024-12-15)
-//	@Param			endDate		query		string	true	"End Date"		default(2024-12-15)
+//	@Param			schedule	query		string	false	"Schedule ID"
+//	@Param			startDate	query		string	true	"Start Date"					default(2024-12-15)
+//	@Param			endDate		query		string	true	"End Date"						default(2024-12-15)
+//	@Param			tz			query		string	false	"IANA timezone of the response"	default(UTC)
 //	@Success		200			{array}		api.UserDateAvailability
 //	@Failure		400			{object}	api.Response
 //	@Failure		401			{object}	api.Response
@@ -123,12 +225,24 @@ func (h *handler) GetUserAvailability(c echo.Context) error {
 	if fromDate.After(toDate) {
 		return api.BadRequestErr("start date must be before end date", nil)
 	}
+	loc, err := api.ParseTimezone(c.QueryParam("tz"))
+	if err != nil {
+		return err
+	}
+	var scheduleID *uuid.UUID
+	if c.QueryParam("schedule") != "" {
+		id, err := uuid.Parse(c.QueryParam("schedule"))
+		if err != nil {
+			return api.BadRequestErr(api.ErrParsingUUID, err)
+		}
+		scheduleID = &id
+	}
 
 	user, err := h.userService.GetByUsername(c.Request().Context(), username)
 	if err != nil {
 		return err
 	}
-	availability, err := h.availabilityService.GetAvailability(c.Request().Context(), user.ID, fromDate, toDate)
+	availability, err := h.availabilityService.GetAvailability(c.Request().Context(), user, scheduleID, fromDate, toDate, loc)
 	if err != nil {
 		return api.CustomErr(http.StatusInternalServerError, api.InternalServerErr, err)
 	}
@@ -138,31 +252,46 @@ func (h *handler) GetUserAvailability(c echo.Context) error {
 // GetScheduleOverlap godoc
 //
 //	@Summary		Get schedule overlap
-//	@Description	handles the retrieval of schedule overlap between two users
+//	@Description	handles the retrieval of schedule overlap between 2 to 10 users, as per their default schedules
+//	@Description	users are given as `usernames` (repeated or comma separated), `firstUsername`/`secondUsername` are still supported
+//	@Description	with `quorum` (defaults to all users) windows where at least that many users are free are returned, each window lists who is free
+//	@Description	windows shorter than `minDuration` minutes are left out, a window continuing past midnight counts as one
+//	@Description	dates and slots of the response are in `tz` (UTC by default), from `startDate` to `endDate` (both inclusive, at most a year)
 //	@Tags			availability
 //	@Accept			json
 //	@Produce		json
-//	@Param			firstUsername	query		string	true	"First Username"
-//	@Param			secondUsername	query		string	true	"Second Username"
-//	@Param			startDate		query		string	true	"Start Date"	default(2024-12-15)
-//	@Param			endDate			query		string	true	"End Date"		default(2024-12-15)
-//	@Success		200				{array}		api.UserDateAvailability
+//	@Param			usernames		query		[]string	false	"Usernames"	collectionFormat(multi)
+//	@Param			firstUsername	query		string		false	"First Username"
+//	@Param			secondUsername	query		string		false	"Second Username"
+//	@Param			quorum			query		int			false	"Min number of free users"
+//	@Param			minDuration		query		int			false	"Min length of a window in minutes"
+//	@Param			startDate		query		string		true	"Start Date"					default(2024-12-15)
+//	@Param			endDate			query		string		true	"End Date"						default(2024-12-15)
+//	@Param			tz				query		string		false	"IANA timezone of the response"	default(UTC)
+//	@Success		200				{object}	api.ScheduleOverlap
 //	@Failure		400				{object}	api.Response
 //	@Failure		401				{object}	api.Response
 //	@Failure		404				{object}	api.Response
 //	@Failure		500				{object}	api.Response
 //	@Router			/availability/overlap [get]
 func (h *handler) GetScheduleOverlap(c echo.Context) error {
-	firstUser := c.QueryParam("firstUsername")
-	if firstUser == "" {
-		return api.BadRequestErr(api.ErrInvalidUsername, nil)
+	usernames, err := overlapUsernames(c)
+	if err != nil {
+		return err
 	}
-	secondUser := c.QueryParam("secondUsername")
-	if secondUser == "" {
-		return api.BadRequestErr(api.ErrInvalidUsername, nil)
+	quorum := len(usernames)
+	if c.QueryParam("quorum") != "" {
+		quorum, err = strconv.Atoi(c.QueryParam("quorum"))
+		if err != nil || quorum < 1 || quorum > len(usernames) {
+			return api.BadRequestErr("invalid quorum, should be between 1 and the number of users", nil)
+		}
 	}
-	if firstUser == secondUser {
-		return api.BadRequestErr("users must be different", nil)
+	minDuration := 0
+	if c.QueryParam("minDuration") != "" {
+		minDuration, err = strconv.Atoi(c.QueryParam("minDuration"))
+		if err != nil || minDuration < 0 {
+			return api.BadRequestErr("invalid min duration", nil)
+		}
 	}
 	fromDate, err := time.Parse("2006-01-02", c.QueryParam("startDate"))
 	if err != nil {
@@ -172,28 +301,131 @@ func (h *handler) GetScheduleOverlap(c echo.Context) error {
 	if err != nil {
 		return api.BadRequestErr("invalid end date", nil)
 	}
+	if fromDate, toDate, err = api.ValidateDateRange(fromDate, toDate); err != nil {
+		return err
+	}
+	loc, err := api.ParseTimezone(c.QueryParam("tz"))
+	if err != nil {
+		return err
+	}
 
 	// get users from username
-	user1, err := h.userService.GetByUsername(c.Request().Context(), firstUser)
+	users := make([]*models.User, 0, len(usernames))
+	for _, username := range usernames {
+		user, err := h.userService.GetByUsername(c.Request().Context(), username)
+		if err != nil {
+			return err
+		}
+		users = append(users, user)
+	}
+
+	availability, err := h.availabilityService.GetScheduleOverlap(c.Request().Context(), users, fromDate, toDate, loc, quorum, minDuration)
+	if err != nil {
+		return api.CustomErr(http.StatusInternalServerError, api.InternalServerErr, err)
+	}
+	return c.JSON(http.StatusOK, availability)
+}
+
+// GetNextCommonSlots godoc
+//
+//	@Summary		Get next common slots
+//	@Description	searches forward for the earliest windows where all the users are free (as per their default schedules) for at least `duration` minutes
+//	@Description	search starts at `startDate` (today by default, never in the past) and stops after NEXT_COMMON_HORIZON_DAYS days
+//	@Description	users are given the same way as for /availability/overlap, windows are rendered in `tz` (UTC by default)
+//	@Tags			availability
+//	@Accept			json
+//	@Produce		json
+//	@Param			usernames	query		[]string	false	"Usernames"					collectionFormat(multi)
+//	@Param			duration	query		int			true	"Meeting length in minutes"	default(30)
+//	@Param			count		query		int			false	"Number of windows"			default(1)
+//	@Param			startDate	query		string		false	"Start Date"
+//	@Param			tz			query		string		false	"IANA timezone of the response"	default(UTC)
+//	@Success		200			{object}	api.NextCommonSlots
+//	@Failure		400			{object}	api.Response
+//	@Failure		401			{object}	api.Response
+//	@Failure		404			{object}	api.Response
+//	@Failure		500			{object}	api.Response
+//	@Router			/availability/next-common [get]
+func (h *handler) GetNextCommonSlots(c echo.Context) error {
+	usernames, err := overlapUsernames(c)
 	if err != nil {
 		return err
 	}
-	user2, err := h.userService.GetByUsername(c.Request().Context(), secondUser)
+	duration, err := strconv.Atoi(c.QueryParam("duration"))
+	if err != nil || duration <= 0 || duration > 1440 {
+		return api.BadRequestErr("invalid duration, should be in range 1-1440 minutes", nil)
+	}
+	count := 1
+	if c.QueryParam("count") != "" {
+		count, err = strconv.Atoi(c.QueryParam("count"))
+		if err != nil || count < 1 || count > api.MaxNextCommonCount {
+			return api.BadRequestErr(fmt.Sprintf("invalid count, should be in range 1-%d", api.MaxNextCommonCount), nil)
+		}
+	}
+	loc, err := api.ParseTimezone(c.QueryParam("tz"))
 	if err != nil {
 		return err
 	}
+	from := time.Now().In(loc)
+	if c.QueryParam("startDate") != "" {
+		startDate, err := time.Parse("2006-01-02", c.QueryParam("startDate"))
+		if err != nil {
+			return api.BadRequestErr("invalid start date", nil)
+		}
+		from = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, loc)
+	}
+
+	// get users from username
+	users := make([]*models.User, 0, len(usernames))
+	for _, username := range usernames {
+		user, err := h.userService.GetByUsername(c.Request().Context(), username)
+		if err != nil {
+			return err
+		}
+		users = append(users, user)
+	}
 
-	availability, err := h.availabilityService.GetScheduleOverlap(c.Request().Context(), user1.ID, user2.ID, fromDate, toDate)
+	horizonDays := configs.Get().NextCommonHorizonDays
+	slots, err := h.availabilityService.FindNextCommonSlots(c.Request().Context(), users, from, horizonDays, duration, count, loc)
 	if err != nil {
 		return api.CustomErr(http.StatusInternalServerError, api.InternalServerErr, err)
 	}
-	return c.JSON(http.StatusOK, availability)
+	return c.JSON(http.StatusOK, slots)
+}
+
+// overlapUsernames collects the users of a schedule overlap, `usernames` can be repeated or comma separated
+// and firstUsername/secondUsername are kept for the older two users api
+func overlapUsernames(c echo.Context) ([]string, error) {
+	var usernames []string
+	for _, param := range c.QueryParams()["usernames"] {
+		usernames = append(usernames, strings.Split(param, ",")...)
+	}
+	for _, param := range []string{"firstUsername", "secondUsername"} {
+		if username := c.QueryParam(param); username != "" {
+			usernames = append(usernames, username)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, username := range usernames {
+		if username == "" {
+			return nil, api.BadRequestErr(api.ErrInvalidUsername, nil)
+		}
+		if seen[username] {
+			return nil, api.BadRequestErr("users must be different", nil)
+		}
+		seen[username] = true
+	}
+	if len(usernames) < 2 || len(usernames) > api.MaxOverlapUsers {
+		return nil, api.BadRequestErr(fmt.Sprintf("overlap needs between 2 and %d users", api.MaxOverlapUsers), nil)
+	}
+	return usernames, nil
 }
 
 // DeleteDayAvailability godoc
 //
 //	@Summary		Delete day availability
-//	@Description	handles the deletion of day-based availability (`date` param is ignored)
+//	@Description	handles the deletion of day-based availability of the schedule from `date` on (today by default), the weekly hours applying before are kept
 //	@Tags			availability
 //	@Accept			json
 //	@Produce		json
@@ -218,17 +450,204 @@ func (h *handler) DeleteDayAvailabilities(c echo.Context) error {
 		return err
 	}
 
+	effectiveFrom, err := api.EffectiveDate(req.Date, user.Location())
+	if err != nil {
+		return err
+	}
+
 	// call the service to delete the day availability
-	if err := h.availabilityService.DeleteDayAvailabilities(c.Request().Context(), user.ID); err != nil {
+	if err := h.availabilityService.DeleteDayAvailabilities(c.Request().Context(), user.ID, req.ScheduleID, effectiveFrom); err != nil {
 		return api.CustomErr(http.StatusInternalServerError, api.InternalServerErr, err)
 	}
 	return c.JSON(http.StatusNoContent, nil)
 }
 
+// UpsertDayAvailability godoc
+//
+//	@Summary		Set availability of a day
+//	@Description	handles setting the slots of a single day of the week without resending the whole week
+//	@Description	the slots apply from `effective_from` on (today by default), the other days keep the weekly hours in effect then
+//	@Description	slots are normalized like day availability
+//	@Tags			availability
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	path		string								true	"Username"
+//	@Param			day			path		string								true	"Day of the week"	Enums(monday, tuesday, wednesday, thursday, friday, saturday, sunday)
+//	@Param			request		body		api.UpsertDayAvailabilityRequest	true	"UpsertDayAvailabilityRequest"
+//	@Success		200			{object}	api.DayAvailabilityResponse
+//	@Failure		400			{object}	api.Response
+//	@Failure		401			{object}	api.Response
+//	@Failure		404			{object}	api.Response
+//	@Failure		500			{object}	api.Response
+//	@Router			/users/{username}/availability/day/{day} [put]
+func (h *handler) UpsertDayAvailability(c echo.Context) error {
+	day := models.Day(c.Param("day"))
+	if !day.IsValid() {
+		return api.BadRequestErr("invalid day", nil)
+	}
+	req := &api.UpsertDayAvailabilityRequest{}
+	if err := h.bindAndValidate(c, req); err != nil {
+		return err
+	}
+	slog.Info("UpsertDayAvailability", "day", day, "req", req)
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	user, err := h.userService.GetByUsername(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		return err
+	}
+	effectiveFrom, err := api.EffectiveDate(req.EffectiveFrom, user.Location())
+	if err != nil {
+		return err
+	}
+	req.EffectiveFrom = &effectiveFrom
+
+	dayAvailability, err := h.availabilityService.UpsertDayAvailability(c.Request().Context(), user.ID, day, req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, dayAvailability)
+}
+
+// DeleteSingleDayAvailability godoc
+//
+//	@Summary		Delete availability of a day
+//	@Description	handles the deletion of a single day of the week from the weekly hours of the schedule (the user's default one when `schedule` is not given)
+//	@Description	from `date` on (today by default), the other days keep the weekly hours in effect then
+//	@Tags			availability
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	path	string	true	"Username"
+//	@Param			day			path	string	true	"Day of the week"	Enums(monday, tuesday, wednesday, thursday, friday, saturday, sunday)
+//	@Param			schedule	query	string	false	"Schedule ID"
+//	@Param			date		query	string	false	"Date the day stops to be available"
+//	@Success		204
+//	@Failure		400	{object}	api.Response
+//	@Failure		401	{object}	api.Response
+//	@Failure		404	{object}	api.Response
+//	@Failure		500	{object}	api.Response
+//	@Router			/users/{username}/availability/day/{day} [delete]
+func (h *handler) DeleteSingleDayAvailability(c echo.Context) error {
+	day := models.Day(c.Param("day"))
+	if !day.IsValid() {
+		return api.BadRequestErr("invalid day", nil)
+	}
+	var scheduleID *uuid.UUID
+	if c.QueryParam("schedule") != "" {
+		id, err := uuid.Parse(c.QueryParam("schedule"))
+		if err != nil {
+			return api.BadRequestErr(api.ErrParsingUUID, err)
+		}
+		scheduleID = &id
+	}
+	var date *time.Time
+	if c.QueryParam("date") != "" {
+		d, err := time.Parse("2006-01-02", c.QueryParam("date"))
+		if err != nil {
+			return api.BadRequestErr("invalid date", nil)
+		}
+		date = &d
+	}
+	slog.Info("DeleteSingleDayAvailability", "day", day, "schedule", scheduleID, "date", date)
+
+	user, err := h.userService.GetByUsername(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		return err
+	}
+	effectiveFrom, err := api.EffectiveDate(date, user.Location())
+	if err != nil {
+		return err
+	}
+
+	if err := h.availabilityService.DeleteDayAvailability(c.Request().Context(), user.ID, scheduleID, effectiveFrom, day); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// CopyAvailability godoc
+//
+//	@Summary		Copy a week of availability forward
+//	@Description	handles copying the availability of the week (monday to sunday) of `source_week` onto the next `weeks` weeks of the schedule (the user's default one when `schedule_id` is not given)
+//	@Description	every date of the target weeks gets a `replace` date availability with the schedule's slots of the same weekday of the source week (weekly hours, rules and date availabilities)
+//	@Description	bookings, time off and busy time are not copied: they don't leave gaps in the target weeks
+//	@Description	dates that already have date availabilities are skipped (`conflict` skip, the default) or their date availabilities are replaced (`conflict` overwrite)
+//	@Description	dates in the past, public holidays of the user's holiday region and dates whose source date is a public holiday are always skipped
+//	@Tags			availability
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	path		string						true	"Username"
+//	@Param			request		body		api.CopyAvailabilityRequest	true	"CopyAvailabilityRequest"
+//	@Success		201			{object}	api.CopyAvailabilityResponse
+//	@Failure		400			{object}	api.Response
+//	@Failure		401			{object}	api.Response
+//	@Failure		404			{object}	api.Response
+//	@Failure		500			{object}	api.Response
+//	@Router			/users/{username}/availability/copy [post]
+func (h *handler) CopyAvailability(c echo.Context) error {
+	req := &api.CopyAvailabilityRequest{}
+	if err := h.bindAndValidate(c, req); err != nil {
+		return err
+	}
+	slog.Info("CopyAvailability", "req", req)
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	user, err := h.userService.GetByUsername(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		return err
+	}
+	res, err := h.availabilityService.CopyWeekAvailability(c.Request().Context(), user, req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+// GetDayAvailabilityHistory godoc
+//
+//	@Summary		Get day availability history
+//	@Description	handles the retrieval of every version of the weekly hours of the schedule (the user's default one when `schedule` is not given)
+//	@Description	past, in effect and future versions are sorted by `effective_from`, a version applies until its `effective_to` (excluded)
+//	@Tags			availability
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	path		string	true	"Username"
+//	@Param			schedule	query		string	false	"Schedule ID"
+//	@Success		200			{array}		api.WeeklyHoursVersion
+//	@Failure		400			{object}	api.Response
+//	@Failure		401			{object}	api.Response
+//	@Failure		404			{object}	api.Response
+//	@Failure		500			{object}	api.Response
+//	@Router			/users/{username}/availability/day/history [get]
+func (h *handler) GetDayAvailabilityHistory(c echo.Context) error {
+	var scheduleID *uuid.UUID
+	if c.QueryParam("schedule") != "" {
+		id, err := uuid.Parse(c.QueryParam("schedule"))
+		if err != nil {
+			return api.BadRequestErr(api.ErrParsingUUID, err)
+		}
+		scheduleID = &id
+	}
+
+	user, err := h.userService.GetByUsername(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		return err
+	}
+	versions, err := h.availabilityService.GetDayAvailabilityHistory(c.Request().Context(), user.ID, scheduleID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, versions)
+}
+
 // DeleteDateAvailability godoc
 //
 //	@Summary		Delete date availability
-//	@Description	handles the deletion of date-based availability
+//	@Description	handles the deletion of date-based availability of the schedule, all dates when `date` is not given and all modes when `mode` is not given
 //	@Tags			availability
 //	@Accept			json
 //	@Produce		json
@@ -254,7 +673,76 @@ func (h *handler) DeleteDateAvailability(c echo.Context) error {
 	}
 
 	// call the service to delete the date availability
-	if err := h.availabilityService.DeleteDateAvailabilities(c.Request().Context(), user.ID, req.Date); err != nil {
+	if err := h.availabilityService.DeleteDateAvailabilities(c.Request().Context(), user.ID, req.ScheduleID, req.Date, req.Date, req.Mode); err != nil {
+		return api.CustomErr(http.StatusInternalServerError, api.InternalServerErr, err)
+	}
+	return c.JSON(http.StatusNoContent, nil)
+}
+
+// DeleteDateRangeAvailability godoc
+//
+//	@Summary		Delete date availability over a range
+//	@Description	handles the deletion of the date-based availability of the schedule from `start_date` to `end_date` (both inclusive, at most a year)
+//	@Description	all modes when `mode` is not given, the whole range is deleted in one transaction
+//	@Tags			availability
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body	api.DeleteDateRangeAvailabilityRequest	true	"DeleteDateRangeAvailabilityRequest"
+//	@Success		204
+//	@Failure		400	{object}	api.Response
+//	@Failure		401	{object}	api.Response
+//	@Failure		404	{object}	api.Response
+//	@Failure		500	{object}	api.Response
+//	@Router			/availability/date/range [delete]
+func (h *handler) DeleteDateRangeAvailability(c echo.Context) error {
+	req := &api.DeleteDateRangeAvailabilityRequest{}
+	if err := h.bindAndValidate(c, req); err != nil {
+		return err
+	}
+	slog.Info("DeleteDateRangeAvailability", "req", req)
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	user, err := h.userService.GetByUsername(c.Request().Context(), req.Username)
+	if err != nil {
+		return err
+	}
+
+	if err := h.availabilityService.DeleteDateAvailabilities(c.Request().Context(), user.ID, req.ScheduleID, &req.StartDate, &req.EndDate, req.Mode); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DeleteRuleAvailability godoc
+//
+//	@Summary		Delete rule availability
+//	@Description	handles the deletion of a recurring availability rule
+//	@Tags			availability
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body	api.DeleteRuleAvailabilityRequest	true	"DeleteRuleAvailabilityRequest"
+//	@Success		204
+//	@Failure		400	{object}	api.Response
+//	@Failure		401	{object}	api.Response
+//	@Failure		404	{object}	api.Response
+//	@Failure		500	{object}	api.Response
+//	@Router			/availability/rule [delete]
+func (h *handler) DeleteRuleAvailability(c echo.Context) error {
+	req := &api.DeleteRuleAvailabilityRequest{}
+	if err := h.bindAndValidate(c, req); err != nil {
+		return err
+	}
+	slog.Info("DeleteRuleAvailability", "req", req)
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	user, err := h.userService.GetByUsername(c.Request().Context(), req.Username)
+	if err != nil {
+		return err
+	}
+
+	if err := h.availabilityService.DeleteRuleAvailability(c.Request().Context(), user.ID, req.ID); err != nil {
 		return api.CustomErr(http.StatusInternalServerError, api.InternalServerErr, err)
 	}
 	return c.JSON(http.StatusNoContent, nil)
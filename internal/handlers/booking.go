@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/niharika88/calendly-api/internal/db/models"
+	"github.com/niharika88/calendly-api/pkg/api"
+)
+
+// CreateBooking godoc
+//
+//	@Summary		Create a booking
+//	@Description	handles the booking of a meeting on the host's calendar
+//	@Description	requested time must be fully inside the host's availability and not overlap any confirmed booking
+//	@Description	with `event_type` only `start_time` is needed, the end comes from the event's duration and its rules (min notice, horizon, buffers) apply
+//	@Description	the response has a `token` the invitee can use to cancel or reschedule, it's never returned again
+//	@Tags			booking
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		api.CreateBookingRequest	true	"CreateBookingRequest"
+//	@Success		201		{object}	models.Booking
+//	@Failure		400		{object}	api.Response
+//	@Failure		401		{object}	api.Response
+//	@Failure		404		{object}	api.Response
+//	@Failure		409		{object}	api.Response
+//	@Failure		500		{object}	api.Response
+//	@Router			/bookings [post]
+func (h *handler) CreateBooking(c echo.Context) error {
+	req := &api.CreateBookingRequest{}
+	if err := h.bindAndValidate(c, req); err != nil {
+		return err
+	}
+	slog.Info("CreateBooking", "req", req)
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	host, err := h.userService.GetByUsername(c.Request().Context(), req.Username)
+	if err != nil {
+		return err
+	}
+	booking, err := h.bookingService.Create(c.Request().Context(), host, req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, booking)
+}
+
+// GetBookings godoc
+//
+//	@Summary		Get bookings
+//	@Description	handles the retrieval of the host's bookings overlapping with the given range of dates
+//	@Description	every booking comes with its history of cancellations and reschedules
+//	@Tags			booking
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	query		string	true	"Username"
+//	@Param			startDate	query		string	true	"Start Date"	default(2024-12-15)
+//	@Param			endDate		query		string	true	"End Date"		default(2024-12-15)
+//	@Param			status		query		string	false	"Status"		Enums(confirmed, cancelled)
+//	@Success		200			{array}		models.Booking
+//	@Failure		400			{object}	api.Response
+//	@Failure		401			{object}	api.Response
+//	@Failure		404			{object}	api.Response
+//	@Failure		500			{object}	api.Response
+//	@Router			/bookings [get]
+func (h *handler) GetBookings(c echo.Context) error {
+	username := c.QueryParam("username")
+	if username == "" {
+		return api.BadRequestErr(api.ErrInvalidUsername, nil)
+	}
+
+	fromDate, err := time.Parse("2006-01-02", c.QueryParam("startDate"))
+	if err != nil {
+		return api.BadRequestErr("invalid start date", nil)
+	}
+	toDate, err := time.Parse("2006-01-02", c.QueryParam("endDate"))
+	if err != nil {
+		return api.BadRequestErr("invalid end date", nil)
+	}
+	if fromDate.After(toDate) {
+		return api.BadRequestErr("start date must be before end date", nil)
+	}
+
+	status := models.BookingStatus(c.QueryParam("status"))
+	if status != "" && !status.IsValid() {
+		return api.BadRequestErr("invalid status", nil)
+	}
+
+	host, err := h.userService.GetByUsername(c.Request().Context(), username)
+	if err != nil {
+		return err
+	}
+	bookings, err := h.bookingService.GetBookings(c.Request().Context(), host.ID, fromDate, toDate, status)
+	if err != nil {
+		return api.CustomErr(http.StatusInternalServerError, api.InternalServerErr, err)
+	}
+	if bookings == nil {
+		bookings = []*models.Booking{}
+	}
+	return c.JSON(http.StatusOK, bookings)
+}
+
+// CancelBooking godoc
+//
+//	@Summary		Cancel a booking
+//	@Description	handles the cancellation of a booking by the invitee using the token returned on booking
+//	@Description	the original time and the reason are kept in the booking's history
+//	@Tags			booking
+//	@Accept			json
+//	@Produce		json
+//	@Param			token	path		string						true	"Booking token"
+//	@Param			request	body		api.CancelBookingRequest	true	"CancelBookingRequest"
+//	@Success		200		{object}	models.Booking
+//	@Failure		400		{object}	api.Response
+//	@Failure		401		{object}	api.Response
+//	@Failure		404		{object}	api.Response
+//	@Failure		409		{object}	api.Response
+//	@Failure		500		{object}	api.Response
+//	@Router			/bookings/{token}/cancel [post]
+func (h *handler) CancelBooking(c echo.Context) error {
+	req := &api.CancelBookingRequest{}
+	if err := h.bindAndValidate(c, req); err != nil {
+		return err
+	}
+	slog.Info("CancelBooking", "req", req)
+
+	booking, err := h.bookingService.Cancel(c.Request().Context(), c.Param("token"), req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, booking)
+}
+
+// RescheduleBooking godoc
+//
+//	@Summary		Reschedule a booking
+//	@Description	handles moving a booking to a new start time by the invitee using the token returned on booking
+//	@Description	booking keeps its duration, the new time must be inside the host's availability
+//	@Description	the original time and the reason are kept in the booking's history
+//	@Tags			booking
+//	@Accept			json
+//	@Produce		json
+//	@Param			token	path		string							true	"Booking token"
+//	@Param			request	body		api.RescheduleBookingRequest	true	"RescheduleBookingRequest"
+//	@Success		200		{object}	models.Booking
+//	@Failure		400		{object}	api.Response
+//	@Failure		401		{object}	api.Response
+//	@Failure		404		{object}	api.Response
+//	@Failure		409		{object}	api.Response
+//	@Failure		500		{object}	api.Response
+//	@Router			/bookings/{token}/reschedule [post]
+func (h *handler) RescheduleBooking(c echo.Context) error {
+	req := &api.RescheduleBookingRequest{}
+	if err := h.bindAndValidate(c, req); err != nil {
+		return err
+	}
+	slog.Info("RescheduleBooking", "req", req)
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	booking, err := h.bookingService.Reschedule(c.Request().Context(), c.Param("token"), req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, booking)
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/niharika88/calendly-api/configs"
+	"github.com/niharika88/calendly-api/internal/db/models"
+	"github.com/niharika88/calendly-api/pkg/api"
+)
+
+// ImportBusy godoc
+//
+//	@Summary		Import busy time
+//	@Description	handles the import of the events of an iCalendar (.ics) file as busy blocks of the user, they are subtracted from availability and overlap
+//	@Description	the file is sent as the `file` field of a multipart form or as the raw request body (text/calendar)
+//	@Description	recurring events (RRULE, RDATE, EXDATE, RECURRENCE-ID) are expanded from today on for `BUSY_IMPORT_HORIZON_DAYS` days, all-day and floating events are in the user's timezone
+//	@Description	transparent and cancelled events don't block time, importing an event again (same UID) replaces its busy blocks
+//	@Tags			busy
+//	@Accept			multipart/form-data,text/calendar
+//	@Produce		json
+//	@Param			username	path		string	true	"Username"
+//	@Param			file		formData	file	false	"iCalendar file"
+//	@Success		200			{object}	api.BusyImportResponse
+//	@Failure		400			{object}	api.Response
+//	@Failure		401			{object}	api.Response
+//	@Failure		404			{object}	api.Response
+//	@Failure		500			{object}	api.Response
+//	@Router			/users/{username}/busy/import [post]
+func (h *handler) ImportBusy(c echo.Context) error {
+	username := c.Param("username")
+	slog.Info("ImportBusy", "username", username)
+	user, err := h.userService.GetByUsername(c.Request().Context(), username)
+	if err != nil {
+		return err
+	}
+
+	body := http.MaxBytesReader(c.Response(), c.Request().Body, api.MaxCalendarFileSize)
+	var r io.Reader = body
+	if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		c.Request().Body = body
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return api.BadRequestErr("file is required", err)
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return api.BadRequestErr("invalid file", err)
+		}
+		defer file.Close()
+		r = file
+	}
+
+	res, err := h.busyService.Import(c.Request().Context(), user, r, configs.Get().BusyImportHorizonDays)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetBusyBlocks godoc
+//
+//	@Summary		Get busy blocks
+//	@Description	handles the retrieval of the user's busy blocks between given dates (of the user's timezone)
+//	@Tags			busy
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	path		string	true	"Username"
+//	@Param			startDate	query		string	true	"Start Date"	default(2024-12-15)
+//	@Param			endDate		query		string	true	"End Date"		default(2024-12-21)
+//	@Success		200			{array}		models.BusyBlock
+//	@Failure		400			{object}	api.Response
+//	@Failure		401			{object}	api.Response
+//	@Failure		404			{object}	api.Response
+//	@Failure		500			{object}	api.Response
+//	@Router			/users/{username}/busy [get]
+func (h *handler) GetBusyBlocks(c echo.Context) error {
+	fromDate, err := time.Parse("2006-01-02", c.QueryParam("startDate"))
+	if err != nil {
+		return api.BadRequestErr("invalid start date", nil)
+	}
+	toDate, err := time.Parse("2006-01-02", c.QueryParam("endDate"))
+	if err != nil {
+		return api.BadRequestErr("invalid end date", nil)
+	}
+	if fromDate.After(toDate) {
+		return api.BadRequestErr("start date must be before end date", nil)
+	}
+
+	user, err := h.userService.GetByUsername(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		return err
+	}
+	busyBlocks, err := h.busyService.GetAll(c.Request().Context(), user, fromDate, toDate)
+	if err != nil {
+		return err
+	}
+	if busyBlocks == nil {
+		busyBlocks = []*models.BusyBlock{}
+	}
+	return c.JSON(http.StatusOK, busyBlocks)
+}
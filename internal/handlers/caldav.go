@@ -0,0 +1,314 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/niharika88/calendly-api/configs"
+	"github.com/niharika88/calendly-api/internal/db/models"
+	"github.com/niharika88/calendly-api/pkg/api"
+	"github.com/niharika88/calendly-api/pkg/caldav"
+	"github.com/niharika88/calendly-api/pkg/icalendar"
+)
+
+// CalDAVPrefix is the root of the CalDAV resources: /dav/{username}/ is both the principal and its calendar home,
+// /dav/{username}/{calendar}/ a calendar and /dav/{username}/{calendar}/{object}.ics an object of it
+const CalDAVPrefix = "/dav"
+
+// CalDAVMethods are the methods served on the CalDAV resources, calendars are read-only
+var CalDAVMethods = []string{http.MethodOptions, http.MethodGet, http.MethodHead, echo.PROPFIND, echo.REPORT}
+
+// CalDAVWellKnown redirects calendar clients discovering the service (RFC 6764) to the CalDAV root,
+// CalDAV isn't part of the REST api so it's left out of the swagger docs
+func (h *handler) CalDAVWellKnown(c echo.Context) error {
+	return c.Redirect(http.StatusMovedPermanently, CalDAVPrefix+"/")
+}
+
+// CalDAV serves the bookings and the availability of the users to CalDAV clients (RFC 4791): PROPFIND to discover the principal,
+// its calendars and their objects, REPORT for calendar-query, calendar-multiget and free-busy-query, and GET for an object.
+// Clients log in with HTTP basic auth, the username and the calendar feed token as password, and can only read their own calendars.
+// Every object has an ETag and every calendar a getctag, so clients only fetch what changed since their last sync
+func (h *handler) CalDAV(c echo.Context) error {
+	req := c.Request()
+	slog.Info("CalDAV", "method", req.Method, "path", req.URL.Path)
+	if req.Method == http.MethodOptions {
+		c.Response().Header().Set("DAV", caldav.Capabilities)
+		c.Response().Header().Set(echo.HeaderAllow, strings.Join(CalDAVMethods, ", "))
+		return c.NoContent(http.StatusOK)
+	}
+
+	user, err := h.calDAVUser(c)
+	if err != nil {
+		return err
+	}
+	if username := c.Param("username"); username != "" && username != user.Username {
+		return api.CustomErr(http.StatusForbidden, api.ErrCalDAVForbidden, nil)
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		return h.getCalendarObject(c, user)
+	case echo.PROPFIND:
+		return h.propFind(c, user)
+	case echo.REPORT:
+		return h.report(c, user)
+	}
+	return echo.ErrMethodNotAllowed
+}
+
+// calDAVUser authenticates the client with HTTP basic auth, the password being the user's calendar feed token. That token is a
+// credential of its own: it's only issued to the owner of the current one or with the admin token, users without one can't log in
+func (h *handler) calDAVUser(c echo.Context) (*models.User, error) {
+	c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Basic realm="calendly-api", charset="UTF-8"`)
+	username, token, ok := c.Request().BasicAuth()
+	if !ok {
+		return nil, api.CustomErr(http.StatusUnauthorized, api.ErrInvalidFeedToken, nil)
+	}
+	user, err := h.userService.GetByUsername(h.ctx(c), username)
+	if err != nil {
+		// don't tell unknown users apart from wrong tokens
+		if code := errorCode(err); code != 0 && code < http.StatusInternalServerError {
+			return nil, api.CustomErr(http.StatusUnauthorized, api.ErrInvalidFeedToken, nil)
+		}
+		return nil, err
+	}
+	if err := h.calendarService.CheckFeedToken(user, token); err != nil {
+		return nil, err
+	}
+	c.Response().Header().Del(echo.HeaderWWWAuthenticate)
+	return user, nil
+}
+
+func (h *handler) getCalendarObject(c echo.Context, user *models.User) error {
+	if c.Param("object") == "" {
+		return echo.ErrMethodNotAllowed
+	}
+	object, err := h.calendarService.CalendarObject(h.ctx(c), user, c.Param("calendar"), c.Param("object"))
+	if err != nil {
+		return err
+	}
+	etag := object.ETag()
+	c.Response().Header().Set("ETag", etag)
+	if !object.ModTime.IsZero() {
+		c.Response().Header().Set(echo.HeaderLastModified, object.ModTime.UTC().Format(http.TimeFormat))
+	}
+	if matchETag(c.Request().Header.Get("If-None-Match"), etag) {
+		return c.NoContent(http.StatusNotModified)
+	}
+	return c.Blob(http.StatusOK, icalendar.ContentType, object.Data)
+}
+
+// propFind returns the properties of the resource, and of its members unless the Depth is 0
+func (h *handler) propFind(c echo.Context, user *models.User) error {
+	propfind, err := caldav.ParsePropFind(http.MaxBytesReader(c.Response(), c.Request().Body, api.MaxCalendarFileSize))
+	if err != nil {
+		return api.BadRequestErr(err.Error(), err)
+	}
+	members := c.Request().Header.Get("Depth") != "0"
+	calendar, name := c.Param("calendar"), c.Param("object")
+
+	var responses []caldav.Response
+	switch {
+	case c.Param("username") == "":
+		responses = append(responses, propfind.Response(CalDAVPrefix+"/", []caldav.Property{
+			caldav.NewElement(caldav.PropResourceType, caldav.NewElement(caldav.ElemCollection)),
+			caldav.NewHref(caldav.PropCurrentUserPrincipal, principalHref(user)),
+		}))
+		if members {
+			responses = append(responses, propfind.Response(principalHref(user), principalProperties(user)))
+		}
+	case calendar == "":
+		responses = append(responses, propfind.Response(principalHref(user), principalProperties(user)))
+		if members {
+			for _, cal := range h.calendarService.Calendars(user) {
+				props, _, err := h.calendarProperties(c, user, cal)
+				if err != nil {
+					return err
+				}
+				responses = append(responses, propfind.Response(calendarHref(user, cal.Name), props))
+			}
+		}
+	case name == "":
+		cal, ok := findCalendar(h.calendarService.Calendars(user), calendar)
+		if !ok {
+			return api.NotFoundErr(api.ErrCalendarNotFound, nil)
+		}
+		props, objects, err := h.calendarProperties(c, user, cal)
+		if err != nil {
+			return err
+		}
+		responses = append(responses, propfind.Response(calendarHref(user, cal.Name), props))
+		if members {
+			for _, object := range objects {
+				responses = append(responses, propfind.Response(objectHref(user, cal.Name, object.Name), caldav.ObjectProperties(object)))
+			}
+		}
+	default:
+		object, err := h.calendarService.CalendarObject(h.ctx(c), user, calendar, name)
+		if err != nil {
+			return err
+		}
+		responses = append(responses, propfind.Response(objectHref(user, calendar, object.Name), caldav.ObjectProperties(object)))
+	}
+	return c.XML(http.StatusMultiStatus, &caldav.MultiStatus{Responses: responses})
+}
+
+// report answers the calendar-query, calendar-multiget and free-busy-query reports on a calendar, the free/busy time
+// is the user's (from the availability) whatever the calendar
+func (h *handler) report(c echo.Context, user *models.User) error {
+	calendar := c.Param("calendar")
+	if calendar == "" || c.Param("object") != "" {
+		return api.BadRequestErr("reports are only supported on calendars", nil)
+	}
+	cal, ok := findCalendar(h.calendarService.Calendars(user), calendar)
+	if !ok {
+		return api.NotFoundErr(api.ErrCalendarNotFound, nil)
+	}
+	report, err := caldav.ParseReport(http.MaxBytesReader(c.Response(), c.Request().Body, api.MaxCalendarFileSize))
+	if err != nil {
+		return api.BadRequestErr(err.Error(), err)
+	}
+
+	var responses []caldav.Response
+	switch {
+	case report.FreeBusy != nil:
+		start, end, err := report.FreeBusy.TimeRange.Bounds()
+		if err != nil {
+			return api.BadRequestErr(err.Error(), err)
+		}
+		if start.IsZero() {
+			return api.BadRequestErr("the time-range of a free-busy-query needs a start", nil)
+		}
+		if end.IsZero() {
+			end = start.AddDate(0, 0, api.MaxDateRangeDays)
+		}
+		if end.Sub(start) > api.MaxDateRangeDays*24*time.Hour {
+			return api.BadRequestErr(fmt.Sprintf("invalid time-range, should span at most %d days", api.MaxDateRangeDays), nil)
+		}
+		res, err := h.calendarService.FreeBusyRange(h.ctx(c), user, start, end)
+		if err != nil {
+			return err
+		}
+		return c.Blob(http.StatusOK, icalendar.ContentType, res)
+	case report.Query != nil:
+		events, timeRange := report.Query.Events()
+		if !events {
+			break
+		}
+		var start, end time.Time
+		if timeRange != nil {
+			if start, end, err = timeRange.Bounds(); err != nil {
+				return api.BadRequestErr(err.Error(), err)
+			}
+		}
+		objects, err := h.calendarService.CalendarObjects(h.ctx(c), user, cal.Name, start, end, configs.Get().CalendarFeedDays)
+		if err != nil {
+			return err
+		}
+		for _, object := range objects {
+			responses = append(responses, report.Query.Response(objectHref(user, cal.Name, object.Name), caldav.ObjectProperties(object)))
+		}
+	case report.Multiget != nil:
+		for _, href := range report.Multiget.Hrefs {
+			hrefURL, err := url.Parse(href)
+			if err != nil || path.Dir(hrefURL.Path)+"/" != calendarHref(user, cal.Name) {
+				responses = append(responses, caldav.StatusResponse(href, http.StatusNotFound))
+				continue
+			}
+			object, err := h.calendarService.CalendarObject(h.ctx(c), user, cal.Name, path.Base(hrefURL.Path))
+			if errorCode(err) == http.StatusNotFound {
+				responses = append(responses, caldav.StatusResponse(href, http.StatusNotFound))
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			responses = append(responses, report.Multiget.Response(href, caldav.ObjectProperties(object)))
+		}
+	}
+	return c.XML(http.StatusMultiStatus, &caldav.MultiStatus{Responses: responses})
+}
+
+// calendarProperties returns the properties of the calendar along with its objects (from today for CALENDAR_FEED_DAYS days)
+// its getctag is computed from
+func (h *handler) calendarProperties(c echo.Context, user *models.User, cal caldav.Calendar) ([]caldav.Property, []*caldav.Object, error) {
+	objects, err := h.calendarService.CalendarObjects(h.ctx(c), user, cal.Name, time.Time{}, time.Time{}, configs.Get().CalendarFeedDays)
+	if err != nil {
+		return nil, nil, err
+	}
+	return []caldav.Property{
+		caldav.NewElement(caldav.PropResourceType, caldav.NewElement(caldav.ElemCollection), caldav.NewElement(caldav.ElemCalendar)),
+		caldav.NewProperty(caldav.PropDisplayName, cal.DisplayName),
+		caldav.NewProperty(caldav.PropCalendarDescription, cal.Description),
+		caldav.NewComponentSet("VEVENT"),
+		caldav.NewProperty(caldav.PropCTag, caldav.CTag(objects)),
+		caldav.NewHref(caldav.PropOwner, principalHref(user)),
+		caldav.NewHref(caldav.PropCurrentUserPrincipal, principalHref(user)),
+		caldav.NewPrivilegeSet(caldav.ElemRead, caldav.ElemReadFreeBusy),
+		caldav.NewReportSet(caldav.ElemCalendarQuery, caldav.ElemCalendarMultiget, caldav.ElemFreeBusyQuery),
+	}, objects, nil
+}
+
+// principalProperties returns the properties of the user's principal, which is its calendar home as well
+func principalProperties(user *models.User) []caldav.Property {
+	props := []caldav.Property{
+		caldav.NewElement(caldav.PropResourceType, caldav.NewElement(caldav.ElemCollection), caldav.NewElement(caldav.ElemPrincipal)),
+		caldav.NewProperty(caldav.PropDisplayName, user.Username),
+		caldav.NewHref(caldav.PropCurrentUserPrincipal, principalHref(user)),
+		caldav.NewHref(caldav.PropPrincipalURL, principalHref(user)),
+		caldav.NewHref(caldav.PropCalendarHomeSet, principalHref(user)),
+		caldav.NewPrivilegeSet(caldav.ElemRead),
+	}
+	if user.Email != "" {
+		props = append(props, caldav.NewHref(caldav.PropCalendarUserAddresses, "mailto:"+user.Email))
+	}
+	return props
+}
+
+func findCalendar(calendars []caldav.Calendar, name string) (caldav.Calendar, bool) {
+	for _, cal := range calendars {
+		if cal.Name == name {
+			return cal, true
+		}
+	}
+	return caldav.Calendar{}, false
+}
+
+func principalHref(user *models.User) string {
+	return CalDAVPrefix + "/" + url.PathEscape(user.Username) + "/"
+}
+
+func calendarHref(user *models.User, calendar string) string {
+	return principalHref(user) + calendar + "/"
+}
+
+func objectHref(user *models.User, calendar, name string) string {
+	return calendarHref(user, calendar) + name
+}
+
+// errorCode returns the status code of an api error, 0 for any other error
+func errorCode(err error) int {
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code
+	}
+	return 0
+}
+
+// matchETag tells if the If-None-Match header lists the entity tag
+func matchETag(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/niharika88/calendly-api/configs"
+	"github.com/niharika88/calendly-api/pkg/api"
+	"github.com/niharika88/calendly-api/pkg/icalendar"
+)
+
+// RotateCalendarFeedToken godoc
+//
+//	@Summary		Rotate calendar feed token
+//	@Description	handles the creation of a new secret token for the user's iCalendar feed, the previous token stops working
+//	@Description	the token is only returned once, along with the feed url to subscribe to from a calendar client
+//	@Description	the request is authorized with `Authorization: Bearer <token>`, the current feed token or the `ADMIN_TOKEN` (needed for the first token of a user)
+//	@Tags			calendar
+//	@Accept			json
+//	@Produce		json
+//	@Param			username		path		string	true	"Username"
+//	@Param			Authorization	header		string	true	"Bearer current feed token or admin token"
+//	@Success		201				{object}	api.CalendarFeedTokenResponse
+//	@Failure		400				{object}	api.Response
+//	@Failure		401				{object}	api.Response
+//	@Failure		404				{object}	api.Response
+//	@Failure		500				{object}	api.Response
+//	@Router			/users/{username}/calendar/token [post]
+func (h *handler) RotateCalendarFeedToken(c echo.Context) error {
+	username := c.Param("username")
+	slog.Info("RotateCalendarFeedToken", "username", username)
+	user, err := h.userService.GetByUsername(c.Request().Context(), username)
+	if err != nil {
+		return err
+	}
+	token, err := h.calendarService.RotateFeedToken(c.Request().Context(), user, bearerToken(c), configs.Get().AdminToken)
+	if err != nil {
+		return err
+	}
+	feedURL := url.URL{
+		Scheme:   c.Scheme(),
+		Host:     c.Request().Host,
+		Path:     "/api/users/" + user.Username + "/calendar.ics",
+		RawQuery: url.Values{"token": {token}}.Encode(),
+	}
+	return c.JSON(http.StatusCreated, api.CalendarFeedTokenResponse{Token: token, URL: feedURL.String()})
+}
+
+// bearerToken returns the token of the `Authorization: Bearer` header, empty without one
+func bearerToken(c echo.Context) string {
+	scheme, token, ok := strings.Cut(c.Request().Header.Get(echo.HeaderAuthorization), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// GetCalendarFeed godoc
+//
+//	@Summary		Get calendar feed
+//	@Description	handles the retrieval of the user's iCalendar (RFC 5545) feed, to subscribe to from any calendar client
+//	@Description	the free time of the default schedule (as transparent "Available" events) and the bookings are listed from today on
+//	@Description	for `CALENDAR_FEED_DAYS` days, in the user's timezone. The `token` is the one issued by /users/{username}/calendar/token
+//	@Tags			calendar
+//	@Produce		text/calendar
+//	@Param			username	path		string	true	"Username"
+//	@Param			token		query		string	true	"Feed Token"
+//	@Success		200			{string}	string	"iCalendar object"
+//	@Failure		400			{object}	api.Response
+//	@Failure		401			{object}	api.Response
+//	@Failure		500			{object}	api.Response
+//	@Router			/users/{username}/calendar.ics [get]
+func (h *handler) GetCalendarFeed(c echo.Context) error {
+	username := c.Param("username")
+	slog.Info("GetCalendarFeed", "username", username)
+	user, err := h.userService.GetByUsername(c.Request().Context(), username)
+	if err != nil {
+		return err
+	}
+	feed, err := h.calendarService.Feed(c.Request().Context(), user, c.QueryParam("token"), configs.Get().CalendarFeedDays)
+	if err != nil {
+		return err
+	}
+	return c.Blob(http.StatusOK, icalendar.ContentType, feed)
+}
+
+// GetFreeBusy godoc
+//
+//	@Summary		Get free/busy time
+//	@Description	handles the retrieval of the user's free and busy time between given dates (of the user's timezone) as an iCalendar VFREEBUSY (RFC 5545)
+//	@Description	free periods are the availability of the default schedule, the rest of the range is busy, periods are in UTC
+//	@Tags			calendar
+//	@Produce		text/calendar
+//	@Param			username	path		string	true	"Username"
+//	@Param			startDate	query		string	true	"Start Date"	default(2024-12-15)
+//	@Param			endDate		query		string	true	"End Date"		default(2024-12-21)
+//	@Success		200			{string}	string	"iCalendar object"
+//	@Failure		400			{object}	api.Response
+//	@Failure		500			{object}	api.Response
+//	@Router			/users/{username}/freebusy [get]
+func (h *handler) GetFreeBusy(c echo.Context) error {
+	fromDate, err := time.Parse("2006-01-02", c.QueryParam("startDate"))
+	if err != nil {
+		return api.BadRequestErr("invalid start date", nil)
+	}
+	toDate, err := time.Parse("2006-01-02", c.QueryParam("endDate"))
+	if err != nil {
+		return api.BadRequestErr("invalid end date", nil)
+	}
+	if fromDate, toDate, err = api.ValidateDateRange(fromDate, toDate); err != nil {
+		return err
+	}
+	slog.Info("GetFreeBusy", "username", c.Param("username"), "startDate", fromDate, "endDate", toDate)
+
+	user, err := h.userService.GetByUsername(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		return err
+	}
+	res, err := h.calendarService.FreeBusy(c.Request().Context(), user, fromDate, toDate)
+	if err != nil {
+		return err
+	}
+	return c.Blob(http.StatusOK, icalendar.ContentType, res)
+}
+
+// QueryFreeBusy godoc
+//
+//	@Summary		Query free/busy time
+//	@Description	handles an iTIP free/busy request (RFC 5546): the body is an iCalendar object with METHOD:REQUEST and a VFREEBUSY giving DTSTART, DTEND and an ATTENDEE per user
+//	@Description	attendees are users found by the (case insensitive) email of their `mailto:` address, the REPLY has a VFREEBUSY per attendee, unknown ones are busy for the whole range
+//	@Tags			calendar
+//	@Accept			text/calendar
+//	@Produce		text/calendar
+//	@Param			request	body		string	true	"VFREEBUSY request"
+//	@Success		200		{string}	string	"iCalendar object"
+//	@Failure		400		{object}	api.Response
+//	@Failure		500		{object}	api.Response
+//	@Router			/freebusy [post]
+func (h *handler) QueryFreeBusy(c echo.Context) error {
+	slog.Info("QueryFreeBusy")
+	body := http.MaxBytesReader(c.Response(), c.Request().Body, api.MaxCalendarFileSize)
+	res, err := h.calendarService.FreeBusyReply(c.Request().Context(), body)
+	if err != nil {
+		return err
+	}
+	return c.Blob(http.StatusOK, icalendar.ContentType, res)
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/niharika88/calendly-api/internal/db/models"
+	"github.com/niharika88/calendly-api/pkg/api"
+)
+
+// CreateCalendarConnection godoc
+//
+//	@Summary		Connect a calendar
+//	@Description	handles the connection of an external calendar of the user, its events are synced periodically (every `CALENDAR_SYNC_INTERVAL`) as busy blocks
+//	@Description	that are subtracted from availability, the first sync runs right away and its error (if any) is kept in `last_error`
+//	@Description	the `fake` provider (enabled with `FAKE_CALENDAR_PROVIDER`) reads a JSON calendar from a file of `FAKE_CALENDAR_DIR` or an http(s) url
+//	@Tags			calendar-connection
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	path		string								true	"Username"
+//	@Param			request		body		api.CreateCalendarConnectionRequest	true	"CreateCalendarConnectionRequest"
+//	@Success		201			{object}	models.CalendarConnection
+//	@Failure		400			{object}	api.Response
+//	@Failure		401			{object}	api.Response
+//	@Failure		404			{object}	api.Response
+//	@Failure		500			{object}	api.Response
+//	@Router			/users/{username}/calendars [post]
+func (h *handler) CreateCalendarConnection(c echo.Context) error {
+	req := &api.CreateCalendarConnectionRequest{}
+	if err := h.bindAndValidate(c, req); err != nil {
+		return err
+	}
+	slog.Info("CreateCalendarConnection", "req", req)
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	user, err := h.userService.GetByUsername(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		return err
+	}
+	connection, err := h.calendarSyncService.Connect(c.Request().Context(), user, req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, connection)
+}
+
+// GetCalendarConnections godoc
+//
+//	@Summary		Get calendar connections
+//	@Description	handles the retrieval of the user's connected calendars along with the state of their last sync
+//	@Tags			calendar-connection
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	path		string	true	"Username"
+//	@Success		200			{array}		models.CalendarConnection
+//	@Failure		400			{object}	api.Response
+//	@Failure		401			{object}	api.Response
+//	@Failure		404			{object}	api.Response
+//	@Failure		500			{object}	api.Response
+//	@Router			/users/{username}/calendars [get]
+func (h *handler) GetCalendarConnections(c echo.Context) error {
+	user, err := h.userService.GetByUsername(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		return err
+	}
+	connections, err := h.calendarSyncService.GetConnections(c.Request().Context(), user.ID)
+	if err != nil {
+		return err
+	}
+	if connections == nil {
+		connections = []*models.CalendarConnection{}
+	}
+	return c.JSON(http.StatusOK, connections)
+}
+
+// DeleteCalendarConnection godoc
+//
+//	@Summary		Disconnect a calendar
+//	@Description	handles the deletion of a calendar connection, the busy blocks synced from it are deleted as well
+//	@Tags			calendar-connection
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	path	string	true	"Username"
+//	@Param			id			path	string	true	"Calendar connection ID"
+//	@Success		204
+//	@Failure		400	{object}	api.Response
+//	@Failure		401	{object}	api.Response
+//	@Failure		404	{object}	api.Response
+//	@Failure		500	{object}	api.Response
+//	@Router			/users/{username}/calendars/{id} [delete]
+func (h *handler) DeleteCalendarConnection(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return api.BadRequestErr(api.ErrParsingUUID, err)
+	}
+	user, err := h.userService.GetByUsername(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		return err
+	}
+	slog.Info("DeleteCalendarConnection", "id", id)
+	if err := h.calendarSyncService.DeleteConnection(c.Request().Context(), user.ID, id); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// SyncCalendarConnection godoc
+//
+//	@Summary		Sync a calendar
+//	@Description	handles an immediate sync of a connected calendar: the events changed or deleted since the last sync replace their busy blocks
+//	@Description	every event is pulled again once a day or when the provider expired the sync token (`full`)
+//	@Tags			calendar-connection
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	path		string	true	"Username"
+//	@Param			id			path		string	true	"Calendar connection ID"
+//	@Success		200			{object}	api.CalendarSyncResponse
+//	@Failure		400			{object}	api.Response
+//	@Failure		401			{object}	api.Response
+//	@Failure		404			{object}	api.Response
+//	@Failure		409			{object}	api.Response
+//	@Failure		500			{object}	api.Response
+//	@Failure		502			{object}	api.Response
+//	@Router			/users/{username}/calendars/{id}/sync [post]
+func (h *handler) SyncCalendarConnection(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return api.BadRequestErr(api.ErrParsingUUID, err)
+	}
+	user, err := h.userService.GetByUsername(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		return err
+	}
+	slog.Info("SyncCalendarConnection", "id", id)
+	res, err := h.calendarSyncService.Sync(c.Request().Context(), user, id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/niharika88/calendly-api/internal/db/models"
+	"github.com/niharika88/calendly-api/pkg/api"
+)
+
+// CreateEventType godoc
+//
+//	@Summary		Create event type
+//	@Description	handles the creation of an event type (e.g. "30-min intro") for the user
+//	@Description	slug has to be unique per user, slot_increment defaults to 15 minutes
+//	@Description	the slot_increment grid, min_notice, booking_horizon and buffer_before/after are applied to the bookable slots and enforced on booking
+//	@Description	daily_cap/weekly_cap limit the confirmed bookings of the event type per day/week, 0 means no cap
+//	@Description	slots come from the schedule `schedule_id`, the user's default schedule when not given
+//	@Tags			event-type
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	path		string						true	"Username"
+//	@Param			request		body		api.CreateEventTypeRequest	true	"CreateEventTypeRequest"
+//	@Success		201			{object}	models.EventType
+//	@Failure		400			{object}	api.Response
+//	@Failure		401			{object}	api.Response
+//	@Failure		404			{object}	api.Response
+//	@Failure		500			{object}	api.Response
+//	@Router			/users/{username}/event-types [post]
+func (h *handler) CreateEventType(c echo.Context) error {
+	req := &api.CreateEventTypeRequest{}
+	if err := h.bindAndValidate(c, req); err != nil {
+		return err
+	}
+	slog.Info("CreateEventType", "req", req)
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	user, err := h.userService.GetByUsername(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		return err
+	}
+	eventType, err := h.eventTypeService.Create(c.Request().Context(), user.ID, req)
+	if err != nil {
+		return api.CustomErr(http.StatusInternalServerError, api.InternalServerErr, err)
+	}
+	return c.JSON(http.StatusCreated, eventType)
+}
+
+// GetEventTypes godoc
+//
+//	@Summary		Get event types
+//	@Description	handles the retrieval of all event types of the user
+//	@Tags			event-type
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	path		string	true	"Username"
+//	@Success		200			{array}		models.EventType
+//	@Failure		400			{object}	api.Response
+//	@Failure		401			{object}	api.Response
+//	@Failure		404			{object}	api.Response
+//	@Failure		500			{object}	api.Response
+//	@Router			/users/{username}/event-types [get]
+func (h *handler) GetEventTypes(c echo.Context) error {
+	user, err := h.userService.GetByUsername(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		return err
+	}
+	eventTypes, err := h.eventTypeService.GetAll(c.Request().Context(), user.ID)
+	if err != nil {
+		return api.CustomErr(http.StatusInternalServerError, api.InternalServerErr, err)
+	}
+	if eventTypes == nil {
+		eventTypes = []*models.EventType{}
+	}
+	return c.JSON(http.StatusOK, eventTypes)
+}
+
+// GetEventType godoc
+//
+//	@Summary		Get event type
+//	@Description	handles the retrieval of an event type by slug
+//	@Tags			event-type
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	path		string	true	"Username"
+//	@Param			slug		path		string	true	"Event type slug"
+//	@Success		200			{object}	models.EventType
+//	@Failure		400			{object}	api.Response
+//	@Failure		401			{object}	api.Response
+//	@Failure		404			{object}	api.Response
+//	@Failure		500			{object}	api.Response
+//	@Router			/users/{username}/event-types/{slug} [get]
+func (h *handler) GetEventType(c echo.Context) error {
+	user, err := h.userService.GetByUsername(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		return err
+	}
+	eventType, err := h.eventTypeService.GetBySlug(c.Request().Context(), user.ID, c.Param("slug"))
+	if err != nil {
+		return api.CustomErr(http.StatusInternalServerError, api.InternalServerErr, err)
+	}
+	return c.JSON(http.StatusOK, eventType)
+}
+
+// UpdateEventType godoc
+//
+//	@Summary		Update event type
+//	@Description	handles the update of an event type, only the provided fields are updated
+//	@Description	a nil UUID `schedule_id` switches the event type back to the user's default schedule
+//	@Tags			event-type
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	path		string						true	"Username"
+//	@Param			slug		path		string						true	"Event type slug"
+//	@Param			request		body		api.UpdateEventTypeRequest	true	"UpdateEventTypeRequest"
+//	@Success		200			{object}	models.EventType
+//	@Failure		400			{object}	api.Response
+//	@Failure		401			{object}	api.Response
+//	@Failure		404			{object}	api.Response
+//	@Failure		500			{object}	api.Response
+//	@Router			/users/{username}/event-types/{slug} [put]
+func (h *handler) UpdateEventType(c echo.Context) error {
+	req := &api.UpdateEventTypeRequest{}
+	if err := h.bindAndValidate(c, req); err != nil {
+		return err
+	}
+	slog.Info("UpdateEventType", "slug", c.Param("slug"), "req", req)
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	user, err := h.userService.GetByUsername(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		return err
+	}
+	eventType, err := h.eventTypeService.Update(c.Request().Context(), user.ID, c.Param("slug"), req)
+	if err != nil {
+		return api.CustomErr(http.StatusInternalServerError, api.InternalServerErr, err)
+	}
+	return c.JSON(http.StatusOK, eventType)
+}
+
+// DeleteEventType godoc
+//
+//	@Summary		Delete event type
+//	@Description	handles the deletion of an event type
+//	@Tags			event-type
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	path	string	true	"Username"
+//	@Param			slug		path	string	true	"Event type slug"
+//	@Success		204
+//	@Failure		400	{object}	api.Response
+//	@Failure		401	{object}	api.Response
+//	@Failure		404	{object}	api.Response
+//	@Failure		500	{object}	api.Response
+//	@Router			/users/{username}/event-types/{slug} [delete]
+func (h *handler) DeleteEventType(c echo.Context) error {
+	user, err := h.userService.GetByUsername(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		return err
+	}
+	slog.Info("DeleteEventType", "slug", c.Param("slug"))
+	if err := h.eventTypeService.Delete(c.Request().Context(), user.ID, c.Param("slug")); err != nil {
+		return api.CustomErr(http.StatusInternalServerError, api.InternalServerErr, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetEventTypeSlots godoc
+//
+//	@Summary		Get bookable slots of an event type
+//	@Description	handles the retrieval of bookable start times of an event type between the given dates
+//	@Description	start times are generated from the host's availability every `slot_increment` minutes, each fits a full meeting
+//	@Description	start times breaking the event's min notice, booking horizon or buffers around other bookings are left out
+//	@Description	days and weeks (in the host's timezone) where the host's or the event's booking caps are reached have no start times
+//	@Tags			event-type
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	path		string	true	"Username"
+//	@Param			slug		path		string	true	"Event type slug"
+//	@Param			startDate	query		string	true	"Start Date"					default(2024-12-15)
+//	@Param			endDate		query		string	true	"End Date"						default(2024-12-15)
+//	@Param			tz			query		string	false	"IANA timezone of the response"	default(UTC)
+//	@Success		200			{object}	api.EventTypeSlots
+//	@Failure		400			{object}	api.Response
+//	@Failure		401			{object}	api.Response
+//	@Failure		404			{object}	api.Response
+//	@Failure		500			{object}	api.Response
+//	@Router			/users/{username}/event-types/{slug}/slots [get]
+func (h *handler) GetEventTypeSlots(c echo.Context) error {
+	fromDate, err := time.Parse("2006-01-02", c.QueryParam("startDate"))
+	if err != nil {
+		return api.BadRequestErr("invalid start date", nil)
+	}
+	toDate, err := time.Parse("2006-01-02", c.QueryParam("endDate"))
+	if err != nil {
+		return api.BadRequestErr("invalid end date", nil)
+	}
+	if fromDate.After(toDate) {
+		return api.BadRequestErr("start date must be before end date", nil)
+	}
+	loc, err := api.ParseTimezone(c.QueryParam("tz"))
+	if err != nil {
+		return err
+	}
+
+	user, err := h.userService.GetByUsername(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		return err
+	}
+	slots, err := h.eventTypeService.GetSlots(c.Request().Context(), user, c.Param("slug"), fromDate, toDate, loc)
+	if err != nil {
+		return api.CustomErr(http.StatusInternalServerError, api.InternalServerErr, err)
+	}
+	return c.JSON(http.StatusOK, slots)
+}
@@ -23,15 +23,74 @@ type Handler interface {
 
 	CreateDayAvailability(c echo.Context) error
 	CreateDateAvailability(c echo.Context) error
+	CreateDateRangeAvailability(c echo.Context) error
+	CreateRuleAvailability(c echo.Context) error
 	DeleteDayAvailabilities(c echo.Context) error
 	DeleteDateAvailability(c echo.Context) error
+	DeleteDateRangeAvailability(c echo.Context) error
+	DeleteRuleAvailability(c echo.Context) error
+	UpsertDayAvailability(c echo.Context) error
+	DeleteSingleDayAvailability(c echo.Context) error
+	GetDayAvailabilityHistory(c echo.Context) error
+	CopyAvailability(c echo.Context) error
 	GetUserAvailability(c echo.Context) error
 	GetScheduleOverlap(c echo.Context) error
+	GetNextCommonSlots(c echo.Context) error
+
+	CreateBooking(c echo.Context) error
+	GetBookings(c echo.Context) error
+	CancelBooking(c echo.Context) error
+	RescheduleBooking(c echo.Context) error
+
+	CreateEventType(c echo.Context) error
+	GetEventTypes(c echo.Context) error
+	GetEventType(c echo.Context) error
+	UpdateEventType(c echo.Context) error
+	DeleteEventType(c echo.Context) error
+	GetEventTypeSlots(c echo.Context) error
+
+	CreateSchedule(c echo.Context) error
+	GetSchedules(c echo.Context) error
+	GetSchedule(c echo.Context) error
+	UpdateSchedule(c echo.Context) error
+	DeleteSchedule(c echo.Context) error
+
+	CreateTimeOff(c echo.Context) error
+	GetTimeOffs(c echo.Context) error
+	GetTimeOff(c echo.Context) error
+	UpdateTimeOff(c echo.Context) error
+	DeleteTimeOff(c echo.Context) error
+
+	GetHolidayRegions(c echo.Context) error
+	GetHolidays(c echo.Context) error
+
+	RotateCalendarFeedToken(c echo.Context) error
+	GetCalendarFeed(c echo.Context) error
+	GetFreeBusy(c echo.Context) error
+	QueryFreeBusy(c echo.Context) error
+
+	ImportBusy(c echo.Context) error
+	GetBusyBlocks(c echo.Context) error
+
+	CreateCalendarConnection(c echo.Context) error
+	GetCalendarConnections(c echo.Context) error
+	DeleteCalendarConnection(c echo.Context) error
+	SyncCalendarConnection(c echo.Context) error
+
+	CalDAVWellKnown(c echo.Context) error
+	CalDAV(c echo.Context) error
 }
 
 type handler struct {
 	userService         services.UserService
 	availabilityService services.AvailabilityService
+	bookingService      services.BookingService
+	eventTypeService    services.EventTypeService
+	scheduleService     services.ScheduleService
+	timeOffService      services.TimeOffService
+	calendarService     services.CalendarService
+	busyService         services.BusyService
+	calendarSyncService services.CalendarSyncService
 }
 
 var _ Handler = (*handler)(nil)
@@ -39,10 +98,24 @@ var _ Handler = (*handler)(nil)
 func NewHandler(
 	userService services.UserService,
 	availabilityService services.AvailabilityService,
+	bookingService services.BookingService,
+	eventTypeService services.EventTypeService,
+	scheduleService services.ScheduleService,
+	timeOffService services.TimeOffService,
+	calendarService services.CalendarService,
+	busyService services.BusyService,
+	calendarSyncService services.CalendarSyncService,
 ) Handler {
 	return &handler{
 		userService:         userService,
 		availabilityService: availabilityService,
+		bookingService:      bookingService,
+		eventTypeService:    eventTypeService,
+		scheduleService:     scheduleService,
+		timeOffService:      timeOffService,
+		calendarService:     calendarService,
+		busyService:         busyService,
+		calendarSyncService: calendarSyncService,
 	}
 }
 
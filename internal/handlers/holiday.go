@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/niharika88/calendly-api/pkg/api"
+	"github.com/niharika88/calendly-api/pkg/holidays"
+)
+
+// GetHolidayRegions godoc
+//
+//	@Summary		Get holiday regions
+//	@Description	handles the retrieval of the regions of the embedded holiday dataset, usable as a user's `holiday_region`
+//	@Tags			holiday
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{array}	holidays.Region
+//	@Router			/holidays/regions [get]
+func (h *handler) GetHolidayRegions(c echo.Context) error {
+	return c.JSON(http.StatusOK, holidays.Regions())
+}
+
+// GetHolidays godoc
+//
+//	@Summary		Get holidays
+//	@Description	handles the retrieval of the public holidays of a region between given dates, a holiday moved off a weekend is listed on both dates
+//	@Tags			holiday
+//	@Accept			json
+//	@Produce		json
+//	@Param			region		query		string	true	"Region"		default(US)
+//	@Param			startDate	query		string	true	"Start Date"	default(2024-12-01)
+//	@Param			endDate		query		string	true	"End Date"		default(2024-12-31)
+//	@Success		200			{array}		holidays.Holiday
+//	@Failure		400			{object}	api.Response
+//	@Router			/holidays [get]
+func (h *handler) GetHolidays(c echo.Context) error {
+	region := c.QueryParam("region")
+	if region == "" {
+		return api.BadRequestErr("region is required", nil)
+	}
+	if err := api.ValidateHolidayRegion(region); err != nil {
+		return err
+	}
+
+	fromDate, err := time.Parse("2006-01-02", c.QueryParam("startDate"))
+	if err != nil {
+		return api.BadRequestErr("invalid start date", nil)
+	}
+	toDate, err := time.Parse("2006-01-02", c.QueryParam("endDate"))
+	if err != nil {
+		return api.BadRequestErr("invalid end date", nil)
+	}
+	if fromDate.After(toDate) {
+		return api.BadRequestErr("start date must be before end date", nil)
+	}
+	slog.Info("GetHolidays", "region", region, "startDate", fromDate, "endDate", toDate)
+
+	res := holidays.Between(region, fromDate, toDate)
+	if res == nil {
+		res = []holidays.Holiday{}
+	}
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/niharika88/calendly-api/internal/db/models"
+	"github.com/niharika88/calendly-api/pkg/api"
+)
+
+// CreateSchedule godoc
+//
+//	@Summary		Create schedule
+//	@Description	handles the creation of a named availability schedule (e.g. "Evening support") for the user
+//	@Description	name has to be unique per user, the user's first schedule is always the default one
+//	@Description	setting `is_default` makes it the default schedule in place of the previous one
+//	@Tags			schedule
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	path		string						true	"Username"
+//	@Param			request		body		api.CreateScheduleRequest	true	"CreateScheduleRequest"
+//	@Success		201			{object}	models.Schedule
+//	@Failure		400			{object}	api.Response
+//	@Failure		401			{object}	api.Response
+//	@Failure		404			{object}	api.Response
+//	@Failure		500			{object}	api.Response
+//	@Router			/users/{username}/schedules [post]
+func (h *handler) CreateSchedule(c echo.Context) error {
+	req := &api.CreateScheduleRequest{}
+	if err := h.bindAndValidate(c, req); err != nil {
+		return err
+	}
+	slog.Info("CreateSchedule", "req", req)
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	user, err := h.userService.GetByUsername(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		return err
+	}
+	schedule, err := h.scheduleService.Create(c.Request().Context(), user.ID, req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, schedule)
+}
+
+// GetSchedules godoc
+//
+//	@Summary		Get schedules
+//	@Description	handles the retrieval of all schedules of the user
+//	@Tags			schedule
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	path		string	true	"Username"
+//	@Success		200			{array}		models.Schedule
+//	@Failure		400			{object}	api.Response
+//	@Failure		401			{object}	api.Response
+//	@Failure		404			{object}	api.Response
+//	@Failure		500			{object}	api.Response
+//	@Router			/users/{username}/schedules [get]
+func (h *handler) GetSchedules(c echo.Context) error {
+	user, err := h.userService.GetByUsername(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		return err
+	}
+	schedules, err := h.scheduleService.GetAll(c.Request().Context(), user.ID)
+	if err != nil {
+		return err
+	}
+	if schedules == nil {
+		schedules = []*models.Schedule{}
+	}
+	return c.JSON(http.StatusOK, schedules)
+}
+
+// GetSchedule godoc
+//
+//	@Summary		Get schedule
+//	@Description	handles the retrieval of a schedule along with its day availabilities, date overrides and recurring rules
+//	@Tags			schedule
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	path		string	true	"Username"
+//	@Param			id			path		string	true	"Schedule ID"
+//	@Success		200			{object}	models.Schedule
+//	@Failure		400			{object}	api.Response
+//	@Failure		401			{object}	api.Response
+//	@Failure		404			{object}	api.Response
+//	@Failure		500			{object}	api.Response
+//	@Router			/users/{username}/schedules/{id} [get]
+func (h *handler) GetSchedule(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return api.BadRequestErr(api.ErrParsingUUID, err)
+	}
+	user, err := h.userService.GetByUsername(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		return err
+	}
+	// weekly hours that ended before the user's today are left out
+	today, err := api.EffectiveDate(nil, user.Location())
+	if err != nil {
+		return err
+	}
+	schedule, err := h.scheduleService.GetByID(c.Request().Context(), user.ID, id, today)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, schedule)
+}
+
+// UpdateSchedule godoc
+//
+//	@Summary		Update schedule
+//	@Description	handles the renaming of a schedule or making it the default one, only the provided fields are updated
+//	@Tags			schedule
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	path		string						true	"Username"
+//	@Param			id			path		string						true	"Schedule ID"
+//	@Param			request		body		api.UpdateScheduleRequest	true	"UpdateScheduleRequest"
+//	@Success		200			{object}	models.Schedule
+//	@Failure		400			{object}	api.Response
+//	@Failure		401			{object}	api.Response
+//	@Failure		404			{object}	api.Response
+//	@Failure		500			{object}	api.Response
+//	@Router			/users/{username}/schedules/{id} [put]
+func (h *handler) UpdateSchedule(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return api.BadRequestErr(api.ErrParsingUUID, err)
+	}
+	req := &api.UpdateScheduleRequest{}
+	if err := h.bindAndValidate(c, req); err != nil {
+		return err
+	}
+	slog.Info("UpdateSchedule", "id", id, "req", req)
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	user, err := h.userService.GetByUsername(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		return err
+	}
+	schedule, err := h.scheduleService.Update(c.Request().Context(), user.ID, id, req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, schedule)
+}
+
+// DeleteSchedule godoc
+//
+//	@Summary		Delete schedule
+//	@Description	handles the deletion of a schedule along with its availability, event types using it fall back to the default schedule
+//	@Description	the default schedule can't be deleted
+//	@Tags			schedule
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	path	string	true	"Username"
+//	@Param			id			path	string	true	"Schedule ID"
+//	@Success		204
+//	@Failure		400	{object}	api.Response
+//	@Failure		401	{object}	api.Response
+//	@Failure		404	{object}	api.Response
+//	@Failure		500	{object}	api.Response
+//	@Router			/users/{username}/schedules/{id} [delete]
+func (h *handler) DeleteSchedule(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return api.BadRequestErr(api.ErrParsingUUID, err)
+	}
+	user, err := h.userService.GetByUsername(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		return err
+	}
+	slog.Info("DeleteSchedule", "id", id)
+	if err := h.scheduleService.Delete(c.Request().Context(), user.ID, id); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
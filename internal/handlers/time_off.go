@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/niharika88/calendly-api/internal/db/models"
+	"github.com/niharika88/calendly-api/pkg/api"
+)
+
+// CreateTimeOff godoc
+//
+//	@Summary		Create time off
+//	@Description	handles the creation of an out-of-office range (e.g. a vacation) for the user, the user is unavailable during it whatever their schedules say
+//	@Description	dates are interpreted in the user's timezone, `start_minute`/`end_minute` make the first/last day partial
+//	@Tags			time-off
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	path		string						true	"Username"
+//	@Param			request		body		api.CreateTimeOffRequest	true	"CreateTimeOffRequest"
+//	@Success		201			{object}	models.TimeOff
+//	@Failure		400			{object}	api.Response
+//	@Failure		401			{object}	api.Response
+//	@Failure		404			{object}	api.Response
+//	@Failure		500			{object}	api.Response
+//	@Router			/users/{username}/time-off [post]
+func (h *handler) CreateTimeOff(c echo.Context) error {
+	req := &api.CreateTimeOffRequest{}
+	if err := h.bindAndValidate(c, req); err != nil {
+		return err
+	}
+	slog.Info("CreateTimeOff", "req", req)
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	user, err := h.userService.GetByUsername(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		return err
+	}
+	timeOff, err := h.timeOffService.Create(c.Request().Context(), user.ID, req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, timeOff)
+}
+
+// GetTimeOffs godoc
+//
+//	@Summary		Get time offs
+//	@Description	handles the retrieval of all time offs of the user
+//	@Tags			time-off
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	path		string	true	"Username"
+//	@Success		200			{array}		models.TimeOff
+//	@Failure		400			{object}	api.Response
+//	@Failure		401			{object}	api.Response
+//	@Failure		404			{object}	api.Response
+//	@Failure		500			{object}	api.Response
+//	@Router			/users/{username}/time-off [get]
+func (h *handler) GetTimeOffs(c echo.Context) error {
+	user, err := h.userService.GetByUsername(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		return err
+	}
+	timeOffs, err := h.timeOffService.GetAll(c.Request().Context(), user.ID)
+	if err != nil {
+		return err
+	}
+	if timeOffs == nil {
+		timeOffs = []*models.TimeOff{}
+	}
+	return c.JSON(http.StatusOK, timeOffs)
+}
+
+// GetTimeOff godoc
+//
+//	@Summary		Get time off
+//	@Description	handles the retrieval of a time off by ID
+//	@Tags			time-off
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	path		string	true	"Username"
+//	@Param			id			path		string	true	"Time off ID"
+//	@Success		200			{object}	models.TimeOff
+//	@Failure		400			{object}	api.Response
+//	@Failure		401			{object}	api.Response
+//	@Failure		404			{object}	api.Response
+//	@Failure		500			{object}	api.Response
+//	@Router			/users/{username}/time-off/{id} [get]
+func (h *handler) GetTimeOff(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return api.BadRequestErr(api.ErrParsingUUID, err)
+	}
+	user, err := h.userService.GetByUsername(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		return err
+	}
+	timeOff, err := h.timeOffService.GetByID(c.Request().Context(), user.ID, id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, timeOff)
+}
+
+// UpdateTimeOff godoc
+//
+//	@Summary		Update time off
+//	@Description	handles the update of a time off, only the provided fields are updated
+//	@Tags			time-off
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	path		string						true	"Username"
+//	@Param			id			path		string						true	"Time off ID"
+//	@Param			request		body		api.UpdateTimeOffRequest	true	"UpdateTimeOffRequest"
+//	@Success		200			{object}	models.TimeOff
+//	@Failure		400			{object}	api.Response
+//	@Failure		401			{object}	api.Response
+//	@Failure		404			{object}	api.Response
+//	@Failure		500			{object}	api.Response
+//	@Router			/users/{username}/time-off/{id} [put]
+func (h *handler) UpdateTimeOff(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return api.BadRequestErr(api.ErrParsingUUID, err)
+	}
+	req := &api.UpdateTimeOffRequest{}
+	if err := h.bindAndValidate(c, req); err != nil {
+		return err
+	}
+	slog.Info("UpdateTimeOff", "id", id, "req", req)
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	user, err := h.userService.GetByUsername(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		return err
+	}
+	timeOff, err := h.timeOffService.Update(c.Request().Context(), user.ID, id, req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, timeOff)
+}
+
+// DeleteTimeOff godoc
+//
+//	@Summary		Delete time off
+//	@Description	handles the deletion of a time off
+//	@Tags			time-off
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	path	string	true	"Username"
+//	@Param			id			path	string	true	"Time off ID"
+//	@Success		204
+//	@Failure		400	{object}	api.Response
+//	@Failure		401	{object}	api.Response
+//	@Failure		404	{object}	api.Response
+//	@Failure		500	{object}	api.Response
+//	@Router			/users/{username}/time-off/{id} [delete]
+func (h *handler) DeleteTimeOff(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return api.BadRequestErr(api.ErrParsingUUID, err)
+	}
+	user, err := h.userService.GetByUsername(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		return err
+	}
+	slog.Info("DeleteTimeOff", "id", id)
+	if err := h.timeOffService.Delete(c.Request().Context(), user.ID, id); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -30,6 +30,18 @@ func (h *handler) CreateUser(c echo.Context) error {
 		return err
 	}
 	slog.Info("CreateUser", "req", req)
+	if req.Timezone == "" {
+		req.Timezone = "UTC"
+	}
+	if err := api.ValidateTimezone(req.Timezone); err != nil {
+		return err
+	}
+	if err := api.ValidateHolidayRegion(req.HolidayRegion); err != nil {
+		return err
+	}
+	if err := api.ValidateBookingCaps(&req.DailyBookingCap, &req.WeeklyBookingCap); err != nil {
+		return err
+	}
 	user, err := h.userService.Create(c.Request().Context(), req)
 	if err != nil {
 		return api.CustomErr(http.StatusInternalServerError, api.InternalServerErr, err)
@@ -89,6 +101,9 @@ func (h *handler) UpdateUser(c echo.Context) error {
 		return err
 	}
 	slog.Info("UpdateUser", "id", id, "req", req)
+	if err := req.Validate(); err != nil {
+		return err
+	}
 	user, err := h.userService.Update(c.Request().Context(), id, *req)
 	if err != nil {
 		return err
@@ -0,0 +1,43 @@
+// Package mailer sends the emails of the service, through SMTP or only to the logs when no SMTP server is configured
+package mailer
+
+import (
+	"context"
+	"log/slog"
+	"net/mail"
+)
+
+// Message is an email with a text and an HTML body, along with an optional iTIP (RFC 6047) iCalendar object
+type Message struct {
+	To      []mail.Address
+	Subject string
+	Text    string
+	HTML    string
+
+	// Calendar is sent inline and as an invite.ics attachment, Method is its METHOD (e.g. REQUEST or CANCEL)
+	Calendar []byte
+	Method   string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// LogMailer only logs the messages, it stands in for the SMTP mailer when no server is configured
+type LogMailer struct{}
+
+var _ Mailer = LogMailer{}
+
+func NewLogMailer() LogMailer {
+	return LogMailer{}
+}
+
+func (LogMailer) Send(ctx context.Context, msg *Message) error {
+	to := make([]string, 0, len(msg.To))
+	for _, address := range msg.To {
+		to = append(to, address.String())
+	}
+	slog.InfoContext(ctx, "mail not sent, SMTP is not configured", "to", to, "subject", msg.Subject, "method", msg.Method)
+	slog.DebugContext(ctx, "mail body", "text", msg.Text)
+	return nil
+}
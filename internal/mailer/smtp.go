@@ -0,0 +1,231 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPMailer sends the messages through an SMTP server, upgrading the connection with STARTTLS when the server offers it
+type SMTPMailer struct {
+	host string
+	addr string
+	auth smtp.Auth
+	from mail.Address
+}
+
+var _ Mailer = (*SMTPMailer)(nil)
+
+// NewSMTPMailer returns a mailer sending from `from` (e.g. "Calendly API <no-reply@example.com>") through host:port,
+// it only authenticates when a username is given
+func NewSMTPMailer(host string, port int, username, password, from string) (*SMTPMailer, error) {
+	fromAddress, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address %q: %w", from, err)
+	}
+	m := &SMTPMailer{
+		host: host,
+		addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		from: *fromAddress,
+	}
+	if username != "" {
+		m.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return m, nil
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg *Message) error {
+	if len(msg.To) == 0 {
+		return nil
+	}
+	data, err := m.encode(msg)
+	if err != nil {
+		return err
+	}
+
+	conn, err := (&net.Dialer{Timeout: 10 * time.Second}).DialContext(ctx, "tcp", m.addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			return err
+		}
+	}
+	if m.auth != nil {
+		if err := client.Auth(m.auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(m.from.Address); err != nil {
+		return err
+	}
+	for _, to := range msg.To {
+		if err := client.Rcpt(to.Address); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// encode renders the message as MIME: a multipart/alternative of the text, the HTML and the calendar (for clients showing
+// the invitation inline), within a multipart/mixed along with the calendar as an attachment
+func (m *SMTPMailer) encode(msg *Message) ([]byte, error) {
+	var buf bytes.Buffer
+	to := make([]string, 0, len(msg.To))
+	for _, address := range msg.To {
+		to = append(to, address.String())
+	}
+	messageID, err := m.messageID()
+	if err != nil {
+		return nil, err
+	}
+	header := textproto.MIMEHeader{}
+	header.Set("From", m.from.String())
+	header.Set("To", strings.Join(to, ", "))
+	header.Set("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header.Set("Date", time.Now().Format(time.RFC1123Z))
+	header.Set("Message-ID", messageID)
+	header.Set("MIME-Version", "1.0")
+
+	var alternative bytes.Buffer
+	alternativeWriter := multipart.NewWriter(&alternative)
+	if err := writeQuotedPrintable(alternativeWriter, "text/plain; charset=utf-8", msg.Text); err != nil {
+		return nil, err
+	}
+	if msg.HTML != "" {
+		if err := writeQuotedPrintable(alternativeWriter, "text/html; charset=utf-8", msg.HTML); err != nil {
+			return nil, err
+		}
+	}
+	if msg.Calendar != nil {
+		calendarType := fmt.Sprintf("text/calendar; charset=utf-8; method=%s", msg.Method)
+		if err := writeBase64(alternativeWriter, textproto.MIMEHeader{"Content-Type": {calendarType}}, msg.Calendar); err != nil {
+			return nil, err
+		}
+	}
+	if err := alternativeWriter.Close(); err != nil {
+		return nil, err
+	}
+	alternativeType := "multipart/alternative; boundary=" + alternativeWriter.Boundary()
+
+	if msg.Calendar == nil {
+		header.Set("Content-Type", alternativeType)
+		writeHeader(&buf, header)
+		buf.Write(alternative.Bytes())
+		return buf.Bytes(), nil
+	}
+
+	var mixed bytes.Buffer
+	mixedWriter := multipart.NewWriter(&mixed)
+	part, err := mixedWriter.CreatePart(textproto.MIMEHeader{"Content-Type": {alternativeType}})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(alternative.Bytes()); err != nil {
+		return nil, err
+	}
+	attachmentHeader := textproto.MIMEHeader{
+		"Content-Type":        {fmt.Sprintf(`application/ics; name="invite.ics"; method=%s`, msg.Method)},
+		"Content-Disposition": {`attachment; filename="invite.ics"`},
+	}
+	if err := writeBase64(mixedWriter, attachmentHeader, msg.Calendar); err != nil {
+		return nil, err
+	}
+	if err := mixedWriter.Close(); err != nil {
+		return nil, err
+	}
+	header.Set("Content-Type", "multipart/mixed; boundary="+mixedWriter.Boundary())
+	writeHeader(&buf, header)
+	buf.Write(mixed.Bytes())
+	return buf.Bytes(), nil
+}
+
+// messageID returns a unique Message-ID within the domain of the sender
+func (m *SMTPMailer) messageID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	domain := m.from.Address[strings.LastIndex(m.from.Address, "@")+1:]
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(b), domain), nil
+}
+
+// writeHeader writes the header fields in a stable order, followed by the blank line ending the header
+func writeHeader(w io.Writer, header textproto.MIMEHeader) {
+	for _, key := range []string{"From", "To", "Subject", "Date", "Message-ID", "MIME-Version", "Content-Type"} {
+		fmt.Fprintf(w, "%s: %s\r\n", key, header.Get(key))
+	}
+	fmt.Fprint(w, "\r\n")
+}
+
+func writeQuotedPrintable(w *multipart.Writer, contentType, body string) error {
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+	qp := quotedprintable.NewWriter(part)
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// writeBase64 writes the part with its data in base64, in lines of 76 characters
+func writeBase64(w *multipart.Writer, header textproto.MIMEHeader, data []byte) error {
+	header.Set("Content-Transfer-Encoding", "base64")
+	part, err := w.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		if _, err := io.WriteString(part, encoded[:76]+"\r\n"); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+	_, err = io.WriteString(part, encoded+"\r\n")
+	return err
+}
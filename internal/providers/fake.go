@@ -0,0 +1,326 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/niharika88/calendly-api/internal/db/models"
+	"github.com/niharika88/calendly-api/pkg/icalendar"
+)
+
+// FakeProviderName is the name of the fake provider in calendar connections
+const FakeProviderName = "fake"
+
+// FakeProvider is a calendar provider without any real calendar service behind it, for local development and tests.
+// A calendar is a JSON document (see fakeCalendar) in a file of the provider's directory or behind an http(s) url under the
+// provider's base url (read with GET and written back with PUT), urls are refused without a base url. Every change bumps the
+// version of the calendar and of the changed event, deleted events are kept as tombstones, so sync tokens (versions) give
+// incremental changes like a real provider. Tombstones up to purged_version are gone, an older sync token is expired
+type FakeProvider struct {
+	dir     string
+	baseURL *url.URL // nil when calendars can't be read over http
+	client  *http.Client
+	mu      sync.Mutex // serializes the read-modify-write of the documents
+}
+
+var _ CalendarProvider = (*FakeProvider)(nil)
+
+// fakeCalendar is the document of a fake calendar
+type fakeCalendar struct {
+	Version       int         `json:"version"`
+	PurgedVersion int         `json:"purged_version,omitempty"`
+	Events        []fakeEvent `json:"events"`
+}
+
+// fakeEvent is an event of a fake calendar, Start and End are written in Timezone (UTC by default) so that recurring events keep
+// their wall clock time, all-day events only use the dates of Start and End (exclusive)
+type fakeEvent struct {
+	UID      string    `json:"uid"`
+	Summary  string    `json:"summary,omitempty"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Timezone string    `json:"timezone,omitempty"`
+	AllDay   bool      `json:"all_day,omitempty"`
+	RRule    string    `json:"rrule,omitempty"`
+	Version  int       `json:"version"`
+	Deleted  bool      `json:"deleted,omitempty"`
+}
+
+// NewFakeProvider returns a fake provider reading the calendar files from dir, and the calendars at the urls under baseURL
+// (e.g. "http://fake-calendars:8080/calendars/") unless it's empty. Locations are user input, so the provider doesn't reach
+// any other url nor follow redirects
+func NewFakeProvider(dir, baseURL string) (*FakeProvider, error) {
+	f := &FakeProvider{
+		dir: dir,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+	if baseURL != "" {
+		u, err := url.Parse(baseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid fake calendar base url %q", baseURL)
+		}
+		if !strings.HasSuffix(u.Path, "/") {
+			u.Path += "/"
+		}
+		f.baseURL = u
+	}
+	return f, nil
+}
+
+func (f *FakeProvider) Name() string {
+	return FakeProviderName
+}
+
+func (f *FakeProvider) Validate(ctx context.Context, connection *models.CalendarConnection) error {
+	if isURL(connection.Location) {
+		_, err := f.url(connection.Location)
+		return err
+	}
+	_, err := f.path(connection.Location)
+	return err
+}
+
+func (f *FakeProvider) ListBusy(ctx context.Context, connection *models.CalendarConnection, query BusyQuery) (*BusyChanges, error) {
+	f.mu.Lock()
+	doc, err := f.load(ctx, connection.Location)
+	f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	since := 0
+	if query.SyncToken != "" {
+		since, err = strconv.Atoi(query.SyncToken)
+		if err != nil || since < doc.PurgedVersion || since > doc.Version {
+			return nil, ErrSyncTokenExpired
+		}
+	}
+
+	changes := &BusyChanges{SyncToken: strconv.Itoa(doc.Version)}
+	cal := icalendar.NewCalendar()
+	for _, e := range doc.Events {
+		if e.Version <= since {
+			continue
+		}
+		if e.Deleted {
+			if since > 0 {
+				changes.Deleted = append(changes.Deleted, e.UID)
+			}
+			continue
+		}
+		comp, err := e.component()
+		if err != nil {
+			changes.Warnings = append(changes.Warnings, fmt.Sprintf("event %s skipped: %s", e.UID, err))
+			continue
+		}
+		cal.Children = append(cal.Children, comp)
+	}
+	events, warnings := icalendar.Busy(cal, query.Location, query.From, query.To)
+	changes.Events = events
+	changes.Warnings = append(changes.Warnings, warnings...)
+	return changes, nil
+}
+
+func (f *FakeProvider) CreateEvent(ctx context.Context, connection *models.CalendarConnection, event Event) error {
+	return f.update(ctx, connection.Location, func(doc *fakeCalendar) {
+		doc.Version++
+		e := fakeEvent{
+			UID:     event.UID,
+			Summary: event.Summary,
+			Start:   event.Start.UTC(),
+			End:     event.End.UTC(),
+			Version: doc.Version,
+		}
+		for i := range doc.Events {
+			if doc.Events[i].UID == event.UID {
+				doc.Events[i] = e
+				return
+			}
+		}
+		doc.Events = append(doc.Events, e)
+	})
+}
+
+func (f *FakeProvider) DeleteEvent(ctx context.Context, connection *models.CalendarConnection, uid string) error {
+	return f.update(ctx, connection.Location, func(doc *fakeCalendar) {
+		for i := range doc.Events {
+			if doc.Events[i].UID == uid && !doc.Events[i].Deleted {
+				doc.Version++
+				doc.Events[i] = fakeEvent{UID: uid, Version: doc.Version, Deleted: true}
+				return
+			}
+		}
+	})
+}
+
+// update applies the change to the calendar document and writes it back
+func (f *FakeProvider) update(ctx context.Context, location string, change func(doc *fakeCalendar)) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	doc, err := f.load(ctx, location)
+	if err != nil {
+		return err
+	}
+	change(doc)
+	return f.save(ctx, location, doc)
+}
+
+// load reads the calendar document, a missing file is an empty calendar
+func (f *FakeProvider) load(ctx context.Context, location string) (*fakeCalendar, error) {
+	var data []byte
+	if isURL(location) {
+		u, err := f.url(location)
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := f.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound {
+			return &fakeCalendar{}, nil
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("fake calendar %s: unexpected status %s", location, resp.Status)
+		}
+		if data, err = io.ReadAll(resp.Body); err != nil {
+			return nil, err
+		}
+	} else {
+		path, err := f.path(location)
+		if err != nil {
+			return nil, err
+		}
+		data, err = os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			return &fakeCalendar{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	doc := &fakeCalendar{}
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("fake calendar %s: %w", location, err)
+	}
+	return doc, nil
+}
+
+func (f *FakeProvider) save(ctx context.Context, location string, doc *fakeCalendar) error {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	if !isURL(location) {
+		path, err := f.path(location)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(path, data, 0o644)
+	}
+
+	u, err := f.url(location)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("fake calendar %s: unexpected status %s", location, resp.Status)
+	}
+	return nil
+}
+
+// path resolves the file name within the provider's directory, it can't point outside of it
+func (f *FakeProvider) path(location string) (string, error) {
+	name := filepath.Clean("/" + location)
+	if name == "/" {
+		return "", fmt.Errorf("fake calendar: a file name or an http(s) url is required")
+	}
+	return filepath.Join(f.dir, name), nil
+}
+
+// url checks the location is a url under the provider's base url, once its path is cleaned
+func (f *FakeProvider) url(location string) (string, error) {
+	if f.baseURL == nil {
+		return "", fmt.Errorf("fake calendar: urls are not allowed, use a file name")
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		return "", fmt.Errorf("fake calendar: %w", err)
+	}
+	u.Path = path.Clean("/" + u.Path)
+	u.RawPath = ""
+	if u.Scheme != f.baseURL.Scheme || u.Host != f.baseURL.Host || u.User != nil ||
+		!strings.HasPrefix(u.Path, f.baseURL.Path) || u.Path+"/" == f.baseURL.Path {
+		return "", fmt.Errorf("fake calendar: the url should be under %s", f.baseURL)
+	}
+	return u.String(), nil
+}
+
+func isURL(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+// component renders the event as a VEVENT
+func (e fakeEvent) component() (*ical.Component, error) {
+	event := ical.NewEvent()
+	event.Props.SetText(ical.PropUID, e.UID)
+	if e.Summary != "" {
+		event.Props.SetText(ical.PropSummary, e.Summary)
+	}
+	if e.AllDay {
+		event.Props.SetDate(ical.PropDateTimeStart, e.Start)
+		event.Props.SetDate(ical.PropDateTimeEnd, e.End)
+	} else {
+		loc, err := time.LoadLocation(e.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		event.Props.SetDateTime(ical.PropDateTimeStart, e.Start.In(loc))
+		event.Props.SetDateTime(ical.PropDateTimeEnd, e.End.In(loc))
+	}
+	if e.RRule != "" {
+		rrule := ical.NewProp(ical.PropRecurrenceRule)
+		rrule.Value = e.RRule
+		event.Props.Set(rrule)
+	}
+	return event.Component, nil
+}
@@ -0,0 +1,200 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/niharika88/calendly-api/internal/db/models"
+)
+
+var fakeQueryFrom = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func newTestFakeProvider(t *testing.T) (*FakeProvider, *models.CalendarConnection) {
+	t.Helper()
+	f, err := NewFakeProvider(t.TempDir(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f, &models.CalendarConnection{Provider: FakeProviderName, Location: "work.json"}
+}
+
+func fakeQuery(syncToken string) BusyQuery {
+	return BusyQuery{SyncToken: syncToken, From: fakeQueryFrom, To: fakeQueryFrom.AddDate(0, 0, 7), Location: time.UTC}
+}
+
+func createFakeEvent(t *testing.T, f *FakeProvider, connection *models.CalendarConnection, uid string, start time.Time) {
+	t.Helper()
+	event := Event{UID: uid, Summary: uid, Start: start, End: start.Add(time.Hour)}
+	if err := f.CreateEvent(context.Background(), connection, event); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func eventUIDs(changes *BusyChanges) []string {
+	uids := make([]string, 0, len(changes.Events))
+	for _, e := range changes.Events {
+		uids = append(uids, e.UID)
+	}
+	slices.Sort(uids)
+	return uids
+}
+
+func TestFakeProviderFullSync(t *testing.T) {
+	ctx := context.Background()
+	f, connection := newTestFakeProvider(t)
+
+	changes, err := f.ListBusy(ctx, connection, fakeQuery(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes.Events) != 0 || changes.SyncToken != "0" {
+		t.Fatalf("a missing calendar should be empty, got %d events and sync token %q", len(changes.Events), changes.SyncToken)
+	}
+
+	createFakeEvent(t, f, connection, "a", fakeQueryFrom.Add(9*time.Hour))
+	createFakeEvent(t, f, connection, "b", fakeQueryFrom.Add(33*time.Hour))
+	createFakeEvent(t, f, connection, "later", fakeQueryFrom.AddDate(0, 1, 0))
+	if err := f.DeleteEvent(ctx, connection, "b"); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err = f.ListBusy(ctx, connection, fakeQuery(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := eventUIDs(changes); !slices.Equal(got, []string{"a", "later"}) {
+		t.Errorf("events = %v, want [a later]", got)
+	}
+	if len(changes.Deleted) != 0 {
+		t.Errorf("a full sync shouldn't return tombstones, got %v", changes.Deleted)
+	}
+	if changes.SyncToken != "4" {
+		t.Errorf("sync token = %q, want 4", changes.SyncToken)
+	}
+	for _, e := range changes.Events {
+		switch e.UID {
+		case "a":
+			want := fakeQueryFrom.Add(9 * time.Hour)
+			if len(e.Periods) != 1 || !e.Periods[0].Start.Equal(want) || !e.Periods[0].End.Equal(want.Add(time.Hour)) {
+				t.Errorf("periods of a = %v, want one hour from %s", e.Periods, want)
+			}
+		case "later":
+			if len(e.Periods) != 0 {
+				t.Errorf("an event after the query's range shouldn't have periods, got %v", e.Periods)
+			}
+		}
+	}
+}
+
+func TestFakeProviderIncrementalSync(t *testing.T) {
+	ctx := context.Background()
+	f, connection := newTestFakeProvider(t)
+	createFakeEvent(t, f, connection, "a", fakeQueryFrom.Add(9*time.Hour))
+	createFakeEvent(t, f, connection, "b", fakeQueryFrom.Add(10*time.Hour))
+	createFakeEvent(t, f, connection, "c", fakeQueryFrom.Add(11*time.Hour))
+
+	changes, err := f.ListBusy(ctx, connection, fakeQuery(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	syncToken := changes.SyncToken
+
+	changes, err = f.ListBusy(ctx, connection, fakeQuery(syncToken))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes.Events) != 0 || len(changes.Deleted) != 0 || changes.SyncToken != syncToken {
+		t.Fatalf("nothing changed, got %d events, %v deleted and sync token %q", len(changes.Events), changes.Deleted, changes.SyncToken)
+	}
+
+	createFakeEvent(t, f, connection, "a", fakeQueryFrom.Add(14*time.Hour))
+	createFakeEvent(t, f, connection, "d", fakeQueryFrom.Add(15*time.Hour))
+	if err := f.DeleteEvent(ctx, connection, "b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.DeleteEvent(ctx, connection, "missing"); err != nil {
+		t.Fatalf("deleting a missing event shouldn't fail, got %v", err)
+	}
+
+	changes, err = f.ListBusy(ctx, connection, fakeQuery(syncToken))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := eventUIDs(changes); !slices.Equal(got, []string{"a", "d"}) {
+		t.Errorf("changed events = %v, want [a d]", got)
+	}
+	if !slices.Equal(changes.Deleted, []string{"b"}) {
+		t.Errorf("deleted = %v, want [b]", changes.Deleted)
+	}
+	if changes.SyncToken != "6" {
+		t.Errorf("sync token = %q, want 6", changes.SyncToken)
+	}
+}
+
+func TestFakeProviderExpiredSyncToken(t *testing.T) {
+	ctx := context.Background()
+	f, connection := newTestFakeProvider(t)
+	doc := fakeCalendar{
+		Version:       10,
+		PurgedVersion: 5,
+		Events: []fakeEvent{
+			{UID: "a", Start: fakeQueryFrom.Add(9 * time.Hour), End: fakeQueryFrom.Add(10 * time.Hour), Version: 7},
+		},
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(f.dir, connection.Location), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, syncToken := range []string{"4", "11", "not-a-version"} {
+		if _, err := f.ListBusy(ctx, connection, fakeQuery(syncToken)); !errors.Is(err, ErrSyncTokenExpired) {
+			t.Errorf("sync token %q: got %v, want ErrSyncTokenExpired", syncToken, err)
+		}
+	}
+	changes, err := f.ListBusy(ctx, connection, fakeQuery("5"))
+	if err != nil {
+		t.Fatalf("the purged version is still a valid sync token, got %v", err)
+	}
+	if got := eventUIDs(changes); !slices.Equal(got, []string{"a"}) {
+		t.Errorf("changed events = %v, want [a]", got)
+	}
+}
+
+func TestFakeProviderLocations(t *testing.T) {
+	ctx := context.Background()
+	f, err := NewFakeProvider(t.TempDir(), "http://calendars.test/fake")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for location, valid := range map[string]bool{
+		"work.json":                          true,
+		"../../etc/passwd":                   true, // cleaned into the provider's directory
+		"/":                                  false,
+		"http://calendars.test/fake/a.json":  true,
+		"http://calendars.test/fake":         false,
+		"http://calendars.test/fake/../a":    false,
+		"http://calendars.test/other/a.json": false,
+		"https://calendars.test/fake/a.json": false,
+		"http://169.254.169.254/latest":      false,
+		"http://user@calendars.test/fake/a":  false,
+	} {
+		err := f.Validate(ctx, &models.CalendarConnection{Location: location})
+		if (err == nil) != valid {
+			t.Errorf("location %q: got %v, want valid=%v", location, err, valid)
+		}
+	}
+
+	f, _ = newTestFakeProvider(t)
+	if err := f.Validate(ctx, &models.CalendarConnection{Location: "http://calendars.test/fake/a.json"}); err == nil {
+		t.Error("urls should be refused without a base url")
+	}
+}
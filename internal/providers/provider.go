@@ -0,0 +1,75 @@
+// Package providers connects external calendars of the users, the sync service pulls their busy time into busy blocks
+package providers
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/niharika88/calendly-api/internal/db/models"
+	"github.com/niharika88/calendly-api/pkg/icalendar"
+)
+
+// ErrSyncTokenExpired is returned by ListBusy when the sync token can't be used anymore, every event has to be pulled again
+var ErrSyncTokenExpired = errors.New("sync token expired")
+
+// BusyQuery asks for the busy time changed since SyncToken (every event when empty), occurrences are expanded within [From, To)
+// and all-day or floating events are in Location
+type BusyQuery struct {
+	SyncToken string
+	From      time.Time
+	To        time.Time
+	Location  *time.Location
+}
+
+// BusyChanges are the events changed since the sync token of the query, a changed event comes with all of its busy periods
+// (none when it doesn't block time anymore), SyncToken is the one to pass to the next query
+type BusyChanges struct {
+	Events    []icalendar.BusyEvent
+	Deleted   []string // UIDs of the deleted events, only set for an incremental sync
+	SyncToken string
+	Warnings  []string // events that couldn't be interpreted
+}
+
+// Event is an event to be created on an external calendar
+type Event struct {
+	UID     string
+	Summary string
+	Start   time.Time
+	End     time.Time
+}
+
+type CalendarProvider interface {
+	// Name identifies the provider in calendar connections, e.g. "fake"
+	Name() string
+	// Validate checks the location of the connection can be used with the provider
+	Validate(ctx context.Context, connection *models.CalendarConnection) error
+	// ListBusy returns the busy time of the connected calendar changed since the query's sync token
+	ListBusy(ctx context.Context, connection *models.CalendarConnection, query BusyQuery) (*BusyChanges, error)
+	// CreateEvent creates the event on the connected calendar, an existing event with the same UID is replaced
+	CreateEvent(ctx context.Context, connection *models.CalendarConnection, event Event) error
+	// DeleteEvent deletes the event with the UID from the connected calendar, deleting a missing event is not an error
+	DeleteEvent(ctx context.Context, connection *models.CalendarConnection, uid string) error
+}
+
+// Registry holds the enabled providers by name
+type Registry map[string]CalendarProvider
+
+func NewRegistry(providers ...CalendarProvider) Registry {
+	registry := make(Registry, len(providers))
+	for _, p := range providers {
+		registry[p.Name()] = p
+	}
+	return registry
+}
+
+// Names returns the names of the enabled providers, sorted
+func (r Registry) Names() []string {
+	names := make([]string, 0, len(r))
+	for name := range r {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
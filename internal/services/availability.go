@@ -3,6 +3,9 @@ package services
 import (
 	"cmp"
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"slices"
 	"strings"
 	"time"
@@ -11,197 +14,990 @@ import (
 	"github.com/niharika88/calendly-api/internal/db/models"
 	"github.com/niharika88/calendly-api/internal/db/repo"
 	"github.com/niharika88/calendly-api/pkg/api"
+	"github.com/niharika88/calendly-api/pkg/holidays"
+	"github.com/teambition/rrule-go"
 )
 
 type AvailabilityService interface {
-	CreateDayAvailability(ctx context.Context, userID uuid.UUID, req *api.CreateDayAvailabilityRequest) ([]*models.DayAvailability, error)
-	CreateDateAvailability(ctx context.Context, userID uuid.UUID, req *api.CreateDateAvailabilityRequest) (*models.DateAvailability, error)
-	DeleteDayAvailabilities(ctx context.Context, userID uuid.UUID) error
-	DeleteDateAvailabilities(ctx context.Context, userID uuid.UUID, date *time.Time) error
-	GetAvailability(ctx context.Context, userID uuid.UUID, fromDate, toDate time.Time) (*api.UserDateAvailability, error)
-	GetScheduleOverlap(ctx context.Context, user1ID, user2ID uuid.UUID, fromDate, toDate time.Time) (*api.UserDateAvailability, error)
+	CreateDayAvailability(ctx context.Context, userID uuid.UUID, req *api.CreateDayAvailabilityRequest) (*api.DayAvailabilityResponse, error)
+	CreateDateAvailability(ctx context.Context, userID uuid.UUID, req *api.CreateDateAvailabilityRequest) (*api.DateAvailabilityResponse, error)
+	CreateRuleAvailability(ctx context.Context, userID uuid.UUID, req *api.CreateRuleAvailabilityRequest) (*api.RuleAvailabilityResponse, error)
+	CopyWeekAvailability(ctx context.Context, user *models.User, req *api.CopyAvailabilityRequest) (*api.CopyAvailabilityResponse, error)
+	DeleteDayAvailabilities(ctx context.Context, userID uuid.UUID, scheduleID *uuid.UUID, effectiveFrom time.Time) error
+	UpsertDayAvailability(ctx context.Context, userID uuid.UUID, day models.Day, req *api.UpsertDayAvailabilityRequest) (*api.DayAvailabilityResponse, error)
+	DeleteDayAvailability(ctx context.Context, userID uuid.UUID, scheduleID *uuid.UUID, effectiveFrom time.Time, day models.Day) error
+	CreateDateRangeAvailability(ctx context.Context, userID uuid.UUID, req *api.CreateDateRangeAvailabilityRequest) (*api.DateRangeAvailabilityResponse, error)
+	DeleteDateAvailabilities(ctx context.Context, userID uuid.UUID, scheduleID *uuid.UUID, fromDate, toDate *time.Time, mode models.OverrideMode) error
+	DeleteRuleAvailability(ctx context.Context, userID, id uuid.UUID) error
+	GetDayAvailabilityHistory(ctx context.Context, userID uuid.UUID, scheduleID *uuid.UUID) ([]*api.WeeklyHoursVersion, error)
+	GetAvailability(ctx context.Context, user *models.User, scheduleID *uuid.UUID, fromDate, toDate time.Time, loc *time.Location) (*api.UserDateAvailability, error)
+	GetScheduleOverlap(ctx context.Context, users []*models.User, fromDate, toDate time.Time, loc *time.Location, quorum, minDuration int) (*api.ScheduleOverlap, error)
+	FindNextCommonSlots(ctx context.Context, users []*models.User, from time.Time, horizonDays, duration, count int, loc *time.Location) (*api.NextCommonSlots, error)
+	IsAvailable(ctx context.Context, user *models.User, scheduleID *uuid.UUID, start, end time.Time, ignoreBookingID uuid.UUID) (bool, error)
 }
 
 type availabilityService struct {
 	availabilityRepo repo.AvailabilityRepo
+	bookingRepo      repo.BookingRepo
+	scheduleRepo     repo.ScheduleRepo
+	timeOffRepo      repo.TimeOffRepo
+	busyBlockRepo    repo.BusyBlockRepo
 }
 
 func NewAvailabilityService(
 	availabilityRepo repo.AvailabilityRepo,
+	bookingRepo repo.BookingRepo,
+	scheduleRepo repo.ScheduleRepo,
+	timeOffRepo repo.TimeOffRepo,
+	busyBlockRepo repo.BusyBlockRepo,
 ) AvailabilityService {
 	return &availabilityService{
 		availabilityRepo: availabilityRepo,
+		bookingRepo:      bookingRepo,
+		scheduleRepo:     scheduleRepo,
+		timeOffRepo:      timeOffRepo,
+		busyBlockRepo:    busyBlockRepo,
 	}
 }
 
-func (as *availabilityService) CreateDayAvailability(ctx context.Context, userID uuid.UUID, req *api.CreateDayAvailabilityRequest) ([]*models.DayAvailability, error) {
+// CreateDayAvailability stores the weekly slots as a new version of the schedule's weekly hours starting at req.EffectiveFrom (set by the handler),
+// the slots of a day given more than once are put together and every day's slots are normalized
+func (as *availabilityService) CreateDayAvailability(ctx context.Context, userID uuid.UUID, req *api.CreateDayAvailabilityRequest) (*api.DayAvailabilityResponse, error) {
+	schedule, err := as.findSchedule(ctx, userID, req.ScheduleID, true)
+	if err != nil {
+		return nil, err
+	}
 	avl := []*models.DayAvailability{}
+	var warnings []string
 
+	dayAvlMap := make(map[models.Day]*models.DayAvailability)
 	for _, uda := range req.Availability {
-		slices.SortFunc(uda.Slots, func(a, b models.Slot) int {
-			return cmp.Compare(a.Start, b.Start)
-		})
+		if dayAvl, ok := dayAvlMap[uda.Day]; ok {
+			warnings = append(warnings, fmt.Sprintf("%s: given more than once, slots are put together", uda.Day))
+			dayAvl.Slots = append(dayAvl.Slots, uda.Slots...)
+			continue
+		}
+		dayAvl := &models.DayAvailability{
+			UserID:     userID,
+			ScheduleID: schedule.ID,
+			Day:        uda.Day,
+			Slots:      slices.Clone(uda.Slots),
+		}
+		dayAvlMap[uda.Day] = dayAvl
+		avl = append(avl, dayAvl)
 	}
-	for _, uda := range req.Availability {
-		avl = append(avl, &models.DayAvailability{
-			UserID: userID,
-			Day:    uda.Day,
-			Slots:  uda.Slots,
-		})
+	for _, dayAvl := range avl {
+		var slotWarnings []string
+		dayAvl.Slots, slotWarnings = normalizeSlots(dayAvl.Slots)
+		for _, w := range slotWarnings {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", dayAvl.Day, w))
+		}
 	}
 
-	if err := as.availabilityRepo.InsertDayAvailability(ctx, avl); err != nil {
+	if err := as.availabilityRepo.InsertDayAvailability(ctx, schedule.ID, *req.EffectiveFrom, avl); err != nil {
 		return nil, err
 	}
 
-	return avl, nil
+	return &api.DayAvailabilityResponse{Availability: avl, Warnings: warnings}, nil
 }
 
-func (as *availabilityService) CreateDateAvailability(ctx context.Context, userID uuid.UUID, req *api.CreateDateAvailabilityRequest) (*models.DateAvailability, error) {
-
-	slices.SortFunc(req.Slots, func(a, b models.Slot) int {
-		return cmp.Compare(a.Start, b.Start)
-	})
+func (as *availabilityService) CreateDateAvailability(ctx context.Context, userID uuid.UUID, req *api.CreateDateAvailabilityRequest) (*api.DateAvailabilityResponse, error) {
+	schedule, err := as.findSchedule(ctx, userID, req.ScheduleID, true)
+	if err != nil {
+		return nil, err
+	}
 
+	slots, warnings := normalizeSlots(req.Slots)
 	dateAvailability := &models.DateAvailability{
-		UserID: userID,
-		Date:   req.Date.UTC(), // it's already assumed that input date/time is always in UTC
-		Slots:  req.Slots,
+		UserID:     userID,
+		ScheduleID: schedule.ID,
+		Date:       req.Date, // calendar date in the user's timezone
+		Mode:       req.Mode,
+		Slots:      slots,
 	}
 
 	if err := as.availabilityRepo.InsertDateAvailability(ctx, dateAvailability); err != nil {
 		return nil, err
 	}
 
-	return dateAvailability, nil
+	return &api.DateAvailabilityResponse{DateAvailability: *dateAvailability, Warnings: warnings}, nil
 }
 
-func (as *availabilityService) DeleteDayAvailabilities(ctx context.Context, userID uuid.UUID) error {
-	return as.availabilityRepo.DeleteDayAvailabilities(ctx, userID)
+// CreateDateRangeAvailability sets the same normalized slots on every date of the range (on the requested days of the week),
+// all the date availabilities are stored in one transaction
+func (as *availabilityService) CreateDateRangeAvailability(ctx context.Context, userID uuid.UUID, req *api.CreateDateRangeAvailabilityRequest) (*api.DateRangeAvailabilityResponse, error) {
+	dates := req.Dates()
+	if len(dates) == 0 {
+		return nil, api.BadRequestErr("no date of the range is on the given days", nil)
+	}
+	schedule, err := as.findSchedule(ctx, userID, req.ScheduleID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	slots, warnings := normalizeSlots(req.Slots)
+	dateAvailabilities := make([]*models.DateAvailability, 0, len(dates))
+	for _, date := range dates {
+		dateAvailabilities = append(dateAvailabilities, &models.DateAvailability{
+			UserID:     userID,
+			ScheduleID: schedule.ID,
+			Date:       date, // calendar date in the user's timezone
+			Mode:       req.Mode,
+			Slots:      slots,
+		})
+	}
+
+	if err := as.availabilityRepo.InsertDateAvailabilities(ctx, dateAvailabilities); err != nil {
+		return nil, err
+	}
+
+	return &api.DateRangeAvailabilityResponse{Availability: dateAvailabilities, Warnings: warnings}, nil
 }
 
-func (as *availabilityService) DeleteDateAvailabilities(ctx context.Context, userID uuid.UUID, date *time.Time) error {
-	return as.availabilityRepo.DeleteDateAvailabilities(ctx, userID, date)
+// CopyWeekAvailability stamps the schedule's slots of the source week (monday to sunday, dates of the user's timezone, as resolved
+// by resolveSlots so bookings, time off and busy blocks are not part of them) as `replace` date availabilities onto the same weekdays
+// of the next req.Weeks weeks, a date without slots gets one without slots. Dates in the past, public holidays, dates whose source
+// date is a public holiday and, with the skip policy, dates that have date availabilities already are left as they are
+func (as *availabilityService) CopyWeekAvailability(ctx context.Context, user *models.User, req *api.CopyAvailabilityRequest) (*api.CopyAvailabilityResponse, error) {
+	schedule, err := as.findSchedule(ctx, user.ID, req.ScheduleID, true)
+	if err != nil {
+		return nil, err
+	}
+	loc := user.Location()
+
+	sourceStart := weekStart(req.SourceWeek, time.UTC)
+	source, err := as.resolveSlots(ctx, schedule.ID, user.HolidayRegion, sourceStart, sourceStart.AddDate(0, 0, 6))
+	if err != nil {
+		return nil, err
+	}
+
+	targetStart, targetEnd := sourceStart.AddDate(0, 0, 7), sourceStart.AddDate(0, 0, 7*(req.Weeks+1)-1)
+	// a `replace` date availability wins over a holiday, copying onto one would open it and copying from one would close a regular day
+	holidayMap := make(map[string]bool)
+	for _, holiday := range holidays.Between(user.HolidayRegion, sourceStart, targetEnd) {
+		holidayMap[holiday.Date.Format("2006-01-02")] = true
+	}
+	existing, err := as.availabilityRepo.GetAllDateAvailabilities(ctx, &schedule.ID, targetStart.Format("2006-01-02"), targetEnd.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	hasDateAvl := make(map[string]bool)
+	for _, dateAvl := range existing {
+		hasDateAvl[dateAvl.Date.Format("2006-01-02")] = true
+	}
+
+	res := &api.CopyAvailabilityResponse{Copied: []*models.DateAvailability{}, Skipped: []time.Time{}}
+	today := calendarDate(time.Now().In(loc))
+	for date := targetStart; !date.After(targetEnd); date = date.AddDate(0, 0, 1) {
+		dateStr := date.Format("2006-01-02")
+		sourceDate := sourceStart.AddDate(0, 0, int(date.Sub(targetStart).Hours()/24)%7).Format("2006-01-02")
+		if date.Before(today) || holidayMap[dateStr] || holidayMap[sourceDate] || (req.Conflict == api.CopyConflictSkip && hasDateAvl[dateStr]) {
+			res.Skipped = append(res.Skipped, date)
+			continue
+		}
+
+		slots := source[sourceDate]
+		if slots == nil {
+			slots = []models.Slot{}
+		}
+		res.Copied = append(res.Copied, &models.DateAvailability{
+			UserID:     user.ID,
+			ScheduleID: schedule.ID,
+			Date:       date, // calendar date in the user's timezone
+			Mode:       models.OverrideModeReplace,
+			Slots:      slots,
+		})
+	}
+	if len(res.Copied) == 0 {
+		return res, nil
+	}
+
+	if req.Conflict == api.CopyConflictOverwrite {
+		err = as.availabilityRepo.ReplaceDateAvailabilities(ctx, schedule.ID, res.Copied)
+	} else {
+		err = as.availabilityRepo.InsertDateAvailabilities(ctx, res.Copied)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
 }
 
-func (as *availabilityService) GetAvailability(ctx context.Context, userID uuid.UUID, fromDate, toDate time.Time) (*api.UserDateAvailability, error) {
-	daysAvl, err := as.availabilityRepo.GetAllDayAvailabilities(ctx, &userID)
+func (as *availabilityService) CreateRuleAvailability(ctx context.Context, userID uuid.UUID, req *api.CreateRuleAvailabilityRequest) (*api.RuleAvailabilityResponse, error) {
+	schedule, err := as.findSchedule(ctx, userID, req.ScheduleID, true)
 	if err != nil {
 		return nil, err
 	}
-	datesAvl, err := as.availabilityRepo.GetAllDateAvailabilities(ctx, &userID, fromDate.Format("2006-01-02"), toDate.Format("2006-01-02"))
+
+	slots, warnings := normalizeSlots(req.Slots)
+
+	ruleAvailability := &models.RuleAvailability{
+		UserID:     userID,
+		ScheduleID: schedule.ID,
+		RRule:      req.RRule,
+		StartDate:  req.StartDate, // calendar date in the user's timezone
+		ExDates:    req.ExDates,
+		Slots:      slots,
+	}
+
+	if err := as.availabilityRepo.InsertRuleAvailability(ctx, ruleAvailability); err != nil {
+		return nil, err
+	}
+
+	return &api.RuleAvailabilityResponse{RuleAvailability: *ruleAvailability, Warnings: warnings}, nil
+}
+
+// DeleteDayAvailabilities ends the schedule's weekly hours at effectiveFrom, the past versions are kept
+func (as *availabilityService) DeleteDayAvailabilities(ctx context.Context, userID uuid.UUID, scheduleID *uuid.UUID, effectiveFrom time.Time) error {
+	schedule, err := as.findSchedule(ctx, userID, scheduleID, false)
+	if err != nil || schedule == nil {
+		return err
+	}
+	return as.availabilityRepo.DeleteDayAvailabilities(ctx, schedule.ID, effectiveFrom)
+}
+
+// DeleteDateAvailabilities deletes the date availabilities of the schedule from fromDate to toDate (both inclusive, nil is not bounding),
+// only of the mode when given
+func (as *availabilityService) DeleteDateAvailabilities(ctx context.Context, userID uuid.UUID, scheduleID *uuid.UUID, fromDate, toDate *time.Time, mode models.OverrideMode) error {
+	schedule, err := as.findSchedule(ctx, userID, scheduleID, false)
+	if err != nil || schedule == nil {
+		return err
+	}
+	return as.availabilityRepo.DeleteDateAvailabilities(ctx, schedule.ID, fromDate, toDate, mode)
+}
+
+func (as *availabilityService) DeleteRuleAvailability(ctx context.Context, userID, id uuid.UUID) error {
+	return as.availabilityRepo.DeleteRuleAvailability(ctx, userID, id)
+}
+
+// UpsertDayAvailability sets the normalized slots of the day from req.EffectiveFrom (set by the handler) on,
+// the other days of the weekly hours in effect then are kept
+func (as *availabilityService) UpsertDayAvailability(ctx context.Context, userID uuid.UUID, day models.Day, req *api.UpsertDayAvailabilityRequest) (*api.DayAvailabilityResponse, error) {
+	schedule, err := as.findSchedule(ctx, userID, req.ScheduleID, true)
 	if err != nil {
 		return nil, err
 	}
-	// fmt.Printf("daysAvl: %+v\n", daysAvl)
-	// fmt.Printf("datesAvl: %+v\n", datesAvl)
 
-	userAvailability := api.UserDateAvailability{}
-	userAvailability.Availability = make(map[string][]models.Slot)
+	slots, slotWarnings := normalizeSlots(req.Slots)
+	dayAvailability := &models.DayAvailability{
+		UserID:     userID,
+		ScheduleID: schedule.ID,
+		Day:        day,
+		Slots:      slots,
+	}
+	if err := as.availabilityRepo.UpsertDayAvailability(ctx, schedule.ID, *req.EffectiveFrom, dayAvailability); err != nil {
+		return nil, err
+	}
 
-	// create maps for dayAvl and dateAvl for fast access
-	dayAvlMap := make(map[models.Day][]models.Slot)
-	dateAvlMap := make(map[time.Time][]models.Slot)
+	var warnings []string
+	for _, w := range slotWarnings {
+		warnings = append(warnings, fmt.Sprintf("%s: %s", day, w))
+	}
+	return &api.DayAvailabilityResponse{Availability: []*models.DayAvailability{dayAvailability}, Warnings: warnings}, nil
+}
+
+// DeleteDayAvailability removes the day from the weekly hours from effectiveFrom on, the other days are kept
+func (as *availabilityService) DeleteDayAvailability(ctx context.Context, userID uuid.UUID, scheduleID *uuid.UUID, effectiveFrom time.Time, day models.Day) error {
+	schedule, err := as.findSchedule(ctx, userID, scheduleID, false)
+	if err != nil || schedule == nil {
+		return err
+	}
+	return as.availabilityRepo.DeleteDayAvailability(ctx, schedule.ID, effectiveFrom, day)
+}
 
+// GetDayAvailabilityHistory returns every version of the schedule's weekly hours (past, in effect and future ones) sorted by EffectiveFrom
+func (as *availabilityService) GetDayAvailabilityHistory(ctx context.Context, userID uuid.UUID, scheduleID *uuid.UUID) ([]*api.WeeklyHoursVersion, error) {
+	schedule, err := as.findSchedule(ctx, userID, scheduleID, false)
+	if err != nil || schedule == nil {
+		return []*api.WeeklyHoursVersion{}, err
+	}
+	daysAvl, err := as.availabilityRepo.GetAllDayAvailabilities(ctx, &schedule.ID, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	versions := []*api.WeeklyHoursVersion{}
 	for _, dayAvl := range daysAvl {
-		dayAvlMap[dayAvl.Day] = dayAvl.Slots
+		if n := len(versions); n == 0 || !versions[n-1].EffectiveFrom.Equal(dayAvl.EffectiveFrom) {
+			versions = append(versions, &api.WeeklyHoursVersion{
+				EffectiveFrom: dayAvl.EffectiveFrom,
+				EffectiveTo:   dayAvl.EffectiveTo,
+			})
+		}
+		version := versions[len(versions)-1]
+		version.Availability = append(version.Availability, api.UserDayAvailability{Day: dayAvl.Day, Slots: dayAvl.Slots})
 	}
+	return versions, nil
+}
 
-	for _, dateAvl := range datesAvl {
-		dateAvlMap[dateAvl.Date] = dateAvl.Slots
+// GetAvailability returns the free slots of the user for every date from fromDate to toDate (both inclusive),
+// the slots of the schedule (the user's default one when nil) are interpreted in the user's timezone and rendered in `loc`
+func (as *availabilityService) GetAvailability(ctx context.Context, user *models.User, scheduleID *uuid.UUID, fromDate, toDate time.Time, loc *time.Location) (*api.UserDateAvailability, error) {
+	schedule, err := as.findSchedule(ctx, user.ID, scheduleID, false)
+	if err != nil {
+		return nil, err
+	}
+	rangeStart, rangeEnd := dateRange(fromDate, toDate, loc)
+	free, err := as.freeIntervals(ctx, user, schedule, rangeStart, rangeEnd, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+
+	userAvailability := api.UserDateAvailability{}
+	userAvailability.Availability = slotsByDate(free, loc)
+	return &userAvailability, nil
+}
+
+// GetScheduleOverlap returns the windows where at least `quorum` of the users are free, each window lists who is free,
+// with quorum == len(users) it's the plain intersection of everyone's availability. Windows shorter than minDuration are dropped
+func (as *availabilityService) GetScheduleOverlap(ctx context.Context, users []*models.User, fromDate, toDate time.Time, loc *time.Location, quorum, minDuration int) (*api.ScheduleOverlap, error) {
+	usersAvl := make([]*api.UserDateAvailability, 0, len(users))
+	usernames := make([]string, 0, len(users))
+	for _, user := range users {
+		userAvl, err := as.GetAvailability(ctx, user, nil, fromDate, toDate, loc)
+		if err != nil {
+			return nil, err
+		}
+		usersAvl = append(usersAvl, userAvl)
+		usernames = append(usernames, user.Username)
 	}
 
+	overlap := api.ScheduleOverlap{}
+	overlap.Availability = make(map[string][]api.OverlapSlot)
+
+	// Iterate through dates in the range
 	for date := fromDate; date.Before(toDate) || date.Equal(toDate); date = date.AddDate(0, 0, 1) {
-		// if DATE over ride slot is available, use that, otherwise use DAY slot if present
 		dateStr := date.Format("2006-01-02")
-		if dateSlots, ok := dateAvlMap[date]; ok {
-			userAvailability.Availability[dateStr] = dateSlots
-			continue
+
+		slots := make([][]models.Slot, len(usersAvl))
+		for i, userAvl := range usersAvl {
+			slots[i] = userAvl.Availability[dateStr]
 		}
-		if daySlots, ok := dayAvlMap[models.Day(strings.ToLower(date.Weekday().String()))]; ok {
-			userAvailability.Availability[dateStr] = daySlots
+
+		if intersection := findIntersection(slots, usernames, quorum); len(intersection) > 0 {
+			overlap.Availability[dateStr] = intersection
 		}
 	}
 
-	// fmt.Printf("userAvailability: %+v\n ", userAvailability)
-	return &userAvailability, nil
+	if minDuration > 0 {
+		dropShortWindows(overlap.Availability, fromDate, toDate, minDuration)
+	}
+
+	return &overlap, nil
+}
+
+// FindNextCommonSlots searches forward a week at a time (weeks of loc starting at `from`) for the first `count` windows where
+// all the users are free (as per their default schedules) for at least `duration` minutes, the search stops after horizonDays.
+// Each user's availability is loaded once per week, so the queries don't grow with every day of the horizon
+func (as *availabilityService) FindNextCommonSlots(ctx context.Context, users []*models.User, from time.Time, horizonDays, duration, count int, loc *time.Location) (*api.NextCommonSlots, error) {
+	schedules := make([]*models.Schedule, len(users))
+	for i, user := range users {
+		schedule, err := as.findSchedule(ctx, user.ID, nil, false)
+		if err != nil {
+			return nil, err
+		}
+		schedules[i] = schedule
+	}
+
+	// nothing can be booked in the past, start from the next full minute
+	if now := time.Now().Truncate(time.Minute).Add(time.Minute); from.Before(now) {
+		from = now
+	}
+	searchEnd := from.AddDate(0, 0, horizonDays)
+	minLength := time.Duration(duration) * time.Minute
+
+	result := api.NextCommonSlots{Duration: duration, Windows: []api.TimeWindow{}}
+	addIfLongEnough := func(iv interval) {
+		if iv.end.Sub(iv.start) >= minLength && len(result.Windows) < count {
+			result.Windows = append(result.Windows, api.TimeWindow{Start: iv.start.In(loc), End: iv.end.In(loc)})
+		}
+	}
+
+	// a window that runs until the end of the week may continue on the next one, it's only judged once we know where it ends
+	var pending *interval
+	for weekStart := from; weekStart.Before(searchEnd) && len(result.Windows) < count; {
+		y, m, d := weekStart.In(loc).Date()
+		weekEnd := time.Date(y, m, d+7, 0, 0, 0, 0, loc)
+		if weekEnd.After(searchEnd) {
+			weekEnd = searchEnd
+		}
+
+		common, err := as.commonIntervals(ctx, users, schedules, weekStart, weekEnd)
+		if err != nil {
+			return nil, err
+		}
+		if pending != nil {
+			if len(common) > 0 && common[0].start.Equal(pending.end) {
+				common[0].start = pending.start
+			} else {
+				addIfLongEnough(*pending)
+			}
+			pending = nil
+		}
+		for i, iv := range common {
+			if i == len(common)-1 && iv.end.Equal(weekEnd) {
+				pending = &iv
+				break
+			}
+			addIfLongEnough(iv)
+		}
+		weekStart = weekEnd
+	}
+	if pending != nil {
+		addIfLongEnough(*pending)
+	}
+
+	return &result, nil
+}
+
+// commonIntervals returns the intervals within [rangeStart, rangeEnd) where all the users are free, schedules[i] is the schedule of users[i]
+func (as *availabilityService) commonIntervals(ctx context.Context, users []*models.User, schedules []*models.Schedule, rangeStart, rangeEnd time.Time) ([]interval, error) {
+	var common []interval
+	for i, user := range users {
+		free, err := as.freeIntervals(ctx, user, schedules[i], rangeStart, rangeEnd, uuid.Nil)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			common = free
+		} else {
+			common = intersectIntervals(common, free)
+		}
+		if len(common) == 0 {
+			break
+		}
+	}
+	return common, nil
+}
+
+// IsAvailable checks if the user is free for the whole [start, end) range as per the schedule (the default one when nil),
+// the range can span multiple dates. ignoreBookingID (if not nil) is treated as free, so that a booking can be moved
+// onto a time overlapping itself
+func (as *availabilityService) IsAvailable(ctx context.Context, user *models.User, scheduleID *uuid.UUID, start, end time.Time, ignoreBookingID uuid.UUID) (bool, error) {
+	schedule, err := as.findSchedule(ctx, user.ID, scheduleID, false)
+	if err != nil {
+		return false, err
+	}
+	free, err := as.freeIntervals(ctx, user, schedule, start, end, ignoreBookingID)
+	if err != nil {
+		return false, err
+	}
+	// free time is clipped to the range, so it has to be a single interval covering all of it
+	return len(free) == 1 && free[0].start.Equal(start) && free[0].end.Equal(end), nil
 }
 
-func (as *availabilityService) GetScheduleOverlap(ctx context.Context, user1ID, user2ID uuid.UUID, fromDate, toDate time.Time) (*api.UserDateAvailability, error) {
-	user1Avl, err := as.GetAvailability(ctx, user1ID, fromDate, toDate)
+// freeIntervals computes the free time of the user within [rangeStart, rangeEnd): slots of the schedule for every date
+// (in the user's timezone) that overlaps the range, minus the confirmed bookings except ignoreBookingID, the user's time off
+// and busy blocks. A nil schedule has no slots, the rest is subtracted regardless of the schedule
+func (as *availabilityService) freeIntervals(ctx context.Context, user *models.User, schedule *models.Schedule, rangeStart, rangeEnd time.Time, ignoreBookingID uuid.UUID) ([]interval, error) {
+	if schedule == nil {
+		return nil, nil
+	}
+	userLoc := user.Location()
+	fromDate := calendarDate(rangeStart.In(userLoc))
+	toDate := calendarDate(rangeEnd.Add(-time.Nanosecond).In(userLoc))
+
+	// overnight slots of the date before the range spill over into it
+	slotsByUserDate, err := as.resolveSlots(ctx, schedule.ID, user.HolidayRegion, fromDate.AddDate(0, 0, -1), toDate)
 	if err != nil {
 		return nil, err
 	}
 
-	user2Avl, err := as.GetAvailability(ctx, user2ID, fromDate, toDate)
+	var available []interval
+	for date := fromDate.AddDate(0, 0, -1); !date.After(toDate); date = date.AddDate(0, 0, 1) {
+		for _, slot := range slotsByUserDate[date.Format("2006-01-02")] {
+			available = append(available, slotInterval(date, slot, userLoc))
+		}
+	}
+
+	// confirmed bookings are not available anymore
+	bookings, err := as.bookingRepo.GetBookings(ctx, user.ID, rangeStart, rangeEnd, models.BookingStatusConfirmed, false)
 	if err != nil {
 		return nil, err
 	}
+	busy := make([]interval, 0, len(bookings))
+	for _, b := range bookings {
+		if b.ID == ignoreBookingID {
+			continue
+		}
+		busy = append(busy, interval{start: b.StartTime, end: b.EndTime})
+	}
 
-	overlap := api.UserDateAvailability{}
-	overlap.Availability = make(map[string][]models.Slot)
+	// so is the time off
+	timeOffs, err := as.timeOffRepo.GetAllByUser(ctx, user.ID, fromDate.Format("2006-01-02"), toDate.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range timeOffs {
+		busy = append(busy, timeOffInterval(t, userLoc))
+	}
 
-	// Iterate through dates in the range
-	for date := fromDate; date.Before(toDate) || date.Equal(toDate); date = date.AddDate(0, 0, 1) {
-		dateStr := date.Format("2006-01-02")
+	// and the events of the user's other calendars
+	busyBlocks, err := as.busyBlockRepo.GetAllByUser(ctx, user.ID, rangeStart, rangeEnd)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range busyBlocks {
+		busy = append(busy, interval{start: b.StartTime, end: b.EndTime})
+	}
+
+	free := subtractIntervals(mergeIntervals(available), mergeIntervals(busy))
+	return clipIntervals(free, rangeStart, rangeEnd), nil
+}
+
+// findSchedule returns the user's schedule, the default one when scheduleID is nil. A user without a default schedule
+// gets a "Working hours" one when create is set, otherwise the schedule is nil
+func (as *availabilityService) findSchedule(ctx context.Context, userID uuid.UUID, scheduleID *uuid.UUID, create bool) (*models.Schedule, error) {
+	if scheduleID != nil {
+		schedule, err := as.scheduleRepo.FindByID(ctx, userID, *scheduleID, false, "")
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, api.NotFoundErr(api.ErrScheduleNotFound, err)
+		}
+		return schedule, err
+	}
+
+	schedule, err := as.scheduleRepo.FindDefault(ctx, userID)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return schedule, err
+	}
+	if !create {
+		return nil, nil
+	}
+	return as.scheduleRepo.FindOrCreateDefault(ctx, &models.Schedule{
+		UserID: userID,
+		Name:   defaultScheduleName,
+	})
+}
+
+// resolveSlots returns the slots of the schedule for every date from fromDate to toDate (dates of the user's timezone).
+// The base slots of a date come from a `replace` DATE override, otherwise from the RULEs occurring on the date (all of their slots)
+// and last from the DAY availability of that weekday in the weekly hours in effect on the date, then the slots of `add` DATE overrides are added and `block` ones removed.
+// Public holidays of the holidayRegion have no base slots, only DATE overrides make the user available on them
+// Slots of a date can end past 1440 (overnight), `block` overrides of the next date also remove their spill-over
+func (as *availabilityService) resolveSlots(ctx context.Context, scheduleID uuid.UUID, holidayRegion string, fromDate, toDate time.Time) (map[string][]models.Slot, error) {
+	daysAvl, err := as.availabilityRepo.GetAllDayAvailabilities(ctx, &scheduleID, fromDate.Format("2006-01-02"), toDate.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	datesAvl, err := as.availabilityRepo.GetAllDateAvailabilities(ctx, &scheduleID, fromDate.Format("2006-01-02"), toDate.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	rulesAvl, err := as.availabilityRepo.GetAllRuleAvailabilities(ctx, &scheduleID, toDate.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+
+	// create maps for dayAvl and dateAvl for fast access, a day has one dayAvl per version of the weekly hours
+	dayAvlMap := make(map[models.Day][]*models.DayAvailability)
+	dateAvlMap := make(map[string]map[models.OverrideMode][]models.Slot)
+
+	for _, dayAvl := range daysAvl {
+		dayAvlMap[dayAvl.Day] = append(dayAvlMap[dayAvl.Day], dayAvl)
+	}
+
+	for _, dateAvl := range datesAvl {
+		dateStr := dateAvl.Date.Format("2006-01-02")
+		if dateAvlMap[dateStr] == nil {
+			dateAvlMap[dateStr] = make(map[models.OverrideMode][]models.Slot)
+		}
+		dateAvlMap[dateStr][dateAvl.Mode] = dateAvl.Slots
+	}
 
-		u1Slots, ok1 := user1Avl.Availability[dateStr]
-		u2Slots, ok2 := user2Avl.Availability[dateStr]
+	ruleAvlMap := make(map[string][]models.Slot)
+	for _, ruleAvl := range rulesAvl {
+		dates, err := ruleDates(ruleAvl, fromDate, toDate)
+		if err != nil {
+			return nil, err
+		}
+		for _, date := range dates {
+			dateStr := date.Format("2006-01-02")
+			ruleAvlMap[dateStr] = append(ruleAvlMap[dateStr], ruleAvl.Slots...)
+		}
+	}
+
+	holidayMap := make(map[string]bool)
+	for _, holiday := range holidays.Between(holidayRegion, fromDate, toDate) {
+		holidayMap[holiday.Date.Format("2006-01-02")] = true
+	}
 
-		// If both users have availability on this date, find the intersection
-		if ok1 && ok2 {
-			intersection := findIntersection(u1Slots, u2Slots)
-			if len(intersection) > 0 {
-				overlap.Availability[dateStr] = intersection
+	slots := make(map[string][]models.Slot)
+	for date := fromDate; !date.After(toDate); date = date.AddDate(0, 0, 1) {
+		dateStr := date.Format("2006-01-02")
+		overrides := dateAvlMap[dateStr]
+
+		// if DATE over ride slot is available, use that, otherwise use RULE slots or DAY slot if present (not on holidays)
+		base, ok := overrides[models.OverrideModeReplace]
+		if !ok && !holidayMap[dateStr] {
+			base, ok = ruleAvlMap[dateStr]
+			if !ok {
+				base = effectiveSlots(dayAvlMap[models.Day(strings.ToLower(date.Weekday().String()))], date)
 			}
 		}
+
+		blocked := slices.Clone(overrides[models.OverrideModeBlock])
+		for _, slot := range dateAvlMap[date.AddDate(0, 0, 1).Format("2006-01-02")][models.OverrideModeBlock] {
+			blocked = append(blocked, models.Slot{Start: slot.Start + 1440, End: slot.End + 1440})
+		}
+
+		dateSlots := base
+		if added := overrides[models.OverrideModeAdd]; len(added) > 0 || len(blocked) > 0 {
+			dateSlots = subtractSlots(mergeSlots(append(slices.Clone(base), added...)), mergeSlots(blocked))
+		}
+		if len(dateSlots) > 0 {
+			slots[dateStr] = dateSlots
+		}
 	}
+	return slots, nil
+}
 
-	return &overlap, nil
+// effectiveSlots returns the slots of the day availability whose version of the weekly hours is in effect on the date
+func effectiveSlots(dayAvls []*models.DayAvailability, date time.Time) []models.Slot {
+	for _, dayAvl := range dayAvls {
+		if calendarDate(dayAvl.EffectiveFrom).After(date) {
+			continue
+		}
+		if dayAvl.EffectiveTo == nil || calendarDate(*dayAvl.EffectiveTo).After(date) {
+			return dayAvl.Slots
+		}
+	}
+	return nil
+}
+
+// mergeSlots sorts the slots and merges the overlapping or adjacent ones, the slice is sorted in place
+func mergeSlots(slots []models.Slot) []models.Slot {
+	slices.SortFunc(slots, func(a, b models.Slot) int {
+		return cmp.Compare(a.Start, b.Start)
+	})
+	var merged []models.Slot
+	for _, slot := range slots {
+		if n := len(merged); n > 0 && slot.Start <= merged[n-1].End {
+			merged[n-1].End = max(merged[n-1].End, slot.End)
+			continue
+		}
+		merged = append(merged, slot)
+	}
+	return merged
+}
+
+// normalizeSlots sorts the slots and merges the overlapping and adjacent ones like mergeSlots,
+// every merge is described in the returned warnings. The given slice is left untouched
+func normalizeSlots(slots []models.Slot) ([]models.Slot, []string) {
+	sorted := slices.Clone(slots)
+	slices.SortFunc(sorted, func(a, b models.Slot) int {
+		return cmp.Or(cmp.Compare(a.Start, b.Start), cmp.Compare(a.End, b.End))
+	})
+
+	var normalized []models.Slot
+	var warnings []string
+	for _, slot := range sorted {
+		n := len(normalized)
+		if n == 0 || slot.Start > normalized[n-1].End {
+			normalized = append(normalized, slot)
+			continue
+		}
+
+		last := &normalized[n-1]
+		if slot == *last {
+			warnings = append(warnings, fmt.Sprintf("slot [%d,%d] is given more than once", slot.Start, slot.End))
+			continue
+		}
+		relation := "overlaps"
+		if slot.Start == last.End {
+			relation = "is adjacent to"
+		}
+		merged := models.Slot{Start: last.Start, End: max(last.End, slot.End)}
+		warnings = append(warnings, fmt.Sprintf("slot [%d,%d] %s [%d,%d], merged into [%d,%d]",
+			slot.Start, slot.End, relation, last.Start, last.End, merged.Start, merged.End))
+		*last = merged
+	}
+	return normalized, warnings
 }
 
-func findIntersection(a, b []models.Slot) []models.Slot {
+// subtractSlots removes the blocked minutes from the slots, both have to be sorted and merged
+func subtractSlots(slots, blocked []models.Slot) []models.Slot {
 	var result []models.Slot
+	for _, slot := range slots {
+		start := slot.Start
+		for _, b := range blocked {
+			if b.End <= start || b.Start >= slot.End {
+				continue
+			}
+			if b.Start > start {
+				result = append(result, models.Slot{Start: start, End: b.Start})
+			}
+			start = max(start, b.End)
+		}
+		if start < slot.End {
+			result = append(result, models.Slot{Start: start, End: slot.End})
+		}
+	}
+	return result
+}
 
-	i, j := 0, 0
+// ruleDates expands the rule into the dates from fromDate to toDate (both inclusive) it occurs on, leaving out its EXDATEs
+func ruleDates(ruleAvl *models.RuleAvailability, fromDate, toDate time.Time) ([]time.Time, error) {
+	rule, err := api.ParseRRule(ruleAvl.RRule, calendarDate(ruleAvl.StartDate))
+	if err != nil {
+		return nil, err
+	}
+	set := rrule.Set{}
+	set.RRule(rule)
+	for _, exDate := range ruleAvl.ExDates {
+		set.ExDate(calendarDate(exDate))
+	}
+	return set.Between(fromDate, toDate, true), nil
+}
 
-	// Use two-pointer technique since both arrays are sorted
-	for i < len(a) && j < len(b) {
-		s1 := a[i]
-		s2 := b[j]
-
-		// Check if slots overlap
-		if s1.End > s2.Start && s2.End > s1.Start {
-			// Calculate the intersected slot
-			intersectedSlot := models.Slot{
-				Start: max(s1.Start, s2.Start),
-				End:   min(s1.End, s2.End),
+// findIntersection finds the windows where at least quorum of the users are free, slots[i] are the sorted slots of usernames[i].
+// It sweeps over every start/end point of all the slots, between two consecutive points the set of free users doesn't change,
+// consecutive windows with the same set of users are merged back together
+func findIntersection(slots [][]models.Slot, usernames []string, quorum int) []api.OverlapSlot {
+	var points []int
+	for _, userSlots := range slots {
+		for _, slot := range userSlots {
+			points = append(points, slot.Start, slot.End)
+		}
+	}
+	slices.Sort(points)
+	points = slices.Compact(points)
+
+	var result []api.OverlapSlot
+	for i := 0; i+1 < len(points); i++ {
+		start, end := points[i], points[i+1]
+
+		var free []string
+		for u, userSlots := range slots {
+			if slices.ContainsFunc(userSlots, func(s models.Slot) bool { return s.Start <= start && end <= s.End }) {
+				free = append(free, usernames[u])
 			}
-			result = append(result, intersectedSlot)
+		}
+		if len(free) < quorum {
+			continue
 		}
 
-		// Move to the next slot in the list that ends first
-		if s1.End < s2.End {
-			i++
+		if n := len(result); n > 0 && result[n-1].End == start && slices.Equal(result[n-1].Users, free) {
+			result[n-1].End = end
+			continue
+		}
+		result = append(result, api.OverlapSlot{
+			Slot:  models.Slot{Start: start, End: end},
+			Users: free,
+		})
+	}
+
+	return result
+}
+
+// dropShortWindows removes the windows shorter than minDuration minutes, a window that continues on the next date
+// (ends at 1440 and the next date's first window starts at 0 with the same users) is measured as a whole
+func dropShortWindows(availability map[string][]api.OverlapSlot, fromDate, toDate time.Time, minDuration int) {
+	type windowRef struct {
+		date string
+		idx  int
+	}
+	kept := make(map[string][]api.OverlapSlot)
+	var chain []windowRef
+	chainLength := 0
+	flush := func() {
+		if chainLength >= minDuration {
+			for _, ref := range chain {
+				kept[ref.date] = append(kept[ref.date], availability[ref.date][ref.idx])
+			}
+		}
+		chain, chainLength = nil, 0
+	}
+
+	var prev *api.OverlapSlot
+	for date := fromDate; !date.After(toDate); date = date.AddDate(0, 0, 1) {
+		dateStr := date.Format("2006-01-02")
+		windows := availability[dateStr]
+		for i := range windows {
+			w := &windows[i]
+			continues := i == 0 && w.Start == 0 && prev != nil && prev.End == 1440 && slices.Equal(prev.Users, w.Users)
+			if !continues {
+				flush()
+			}
+			chain = append(chain, windowRef{date: dateStr, idx: i})
+			chainLength += w.End - w.Start
+			prev = w
+		}
+		if len(windows) == 0 {
+			prev = nil
+		}
+	}
+	flush()
+
+	for dateStr := range availability {
+		if windows, ok := kept[dateStr]; ok {
+			availability[dateStr] = windows
 		} else {
-			j++
+			delete(availability, dateStr)
+		}
+	}
+}
+
+// interval is an absolute [start, end) range of time
+type interval struct {
+	start, end time.Time
+}
+
+// calendarDate returns the date of t (in t's location) as midnight UTC, the way dates are passed around
+func calendarDate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// dateRange returns the absolute [start, end) range covered by the dates fromDate to toDate (both inclusive) in loc
+func dateRange(fromDate, toDate time.Time, loc *time.Location) (time.Time, time.Time) {
+	return time.Date(fromDate.Year(), fromDate.Month(), fromDate.Day(), 0, 0, 0, 0, loc),
+		time.Date(toDate.Year(), toDate.Month(), toDate.Day()+1, 0, 0, 0, 0, loc)
+}
+
+// slotInterval interprets the slot as wall clock time of the date in loc, time.Date normalizes the minutes
+// so DST transitions are handled (e.g. 09:00 is still 09:00 on the day clocks are moved)
+func slotInterval(date time.Time, slot models.Slot, loc *time.Location) interval {
+	y, m, d := date.Date()
+	return interval{
+		start: time.Date(y, m, d, 0, slot.Start, 0, 0, loc),
+		end:   time.Date(y, m, d, 0, slot.End, 0, 0, loc),
+	}
+}
+
+// timeOffInterval interprets the time off's dates and minutes as wall clock time in loc
+func timeOffInterval(t *models.TimeOff, loc *time.Location) interval {
+	return interval{
+		start: time.Date(t.StartDate.Year(), t.StartDate.Month(), t.StartDate.Day(), 0, t.StartMinute, 0, 0, loc),
+		end:   time.Date(t.EndDate.Year(), t.EndDate.Month(), t.EndDate.Day(), 0, t.EndMinute, 0, 0, loc),
+	}
+}
+
+// slotsByDate renders the intervals as slots per date of loc, intervals crossing midnight are split on both dates
+func slotsByDate(intervals []interval, loc *time.Location) map[string][]models.Slot {
+	slots := make(map[string][]models.Slot)
+	for _, iv := range intervals {
+		for start := iv.start.In(loc); start.Before(iv.end); {
+			y, m, d := start.Date()
+			nextMidnight := time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+			end := iv.end.In(loc)
+			slot := models.Slot{Start: start.Hour()*60 + start.Minute(), End: 1440}
+			if end.Before(nextMidnight) {
+				slot.End = end.Hour()*60 + end.Minute()
+			} else {
+				end = nextMidnight
+			}
+			// wall clock can go backwards within the repeated hour when DST ends, such slivers can't be represented
+			if slot.Start < slot.End {
+				dateStr := start.Format("2006-01-02")
+				slots[dateStr] = append(slots[dateStr], slot)
+			}
+			start = end
+		}
+	}
+	return slots
+}
+
+// toIntervals is the inverse of slotsByDate, it turns the per date slots (in loc) into absolute intervals
+// and stitches together the slots that continue on the next date (e.g. [1380,1440] followed by [0,60])
+func toIntervals(availability *api.UserDateAvailability, fromDate, toDate time.Time, loc *time.Location) []interval {
+	var intervals []interval
+	for date := fromDate; !date.After(toDate); date = date.AddDate(0, 0, 1) {
+		for _, slot := range availability.Availability[date.Format("2006-01-02")] {
+			iv := slotInterval(date, slot, loc)
+			if n := len(intervals); n > 0 && intervals[n-1].end.Equal(iv.start) {
+				intervals[n-1].end = iv.end
+				continue
+			}
+			intervals = append(intervals, iv)
+		}
+	}
+	return intervals
+}
+
+// mergeIntervals sorts the intervals and merges the overlapping or adjacent ones
+func mergeIntervals(intervals []interval) []interval {
+	slices.SortFunc(intervals, func(a, b interval) int {
+		return a.start.Compare(b.start)
+	})
+	var merged []interval
+	for _, iv := range intervals {
+		if !iv.start.Before(iv.end) {
+			continue
 		}
+		if n := len(merged); n > 0 && !iv.start.After(merged[n-1].end) {
+			if iv.end.After(merged[n-1].end) {
+				merged[n-1].end = iv.end
+			}
+			continue
+		}
+		merged = append(merged, iv)
 	}
+	return merged
+}
 
+// subtractIntervals removes the busy intervals from the free ones, both have to be sorted and disjoint
+func subtractIntervals(free, busy []interval) []interval {
+	var result []interval
+	j := 0
+	for _, iv := range free {
+		cur := iv.start
+		// skip busy intervals that end before this free interval even starts
+		for j < len(busy) && !busy[j].end.After(cur) {
+			j++
+		}
+		for k := j; k < len(busy) && busy[k].start.Before(iv.end); k++ {
+			if busy[k].start.After(cur) {
+				result = append(result, interval{start: cur, end: busy[k].start})
+			}
+			if busy[k].end.After(cur) {
+				cur = busy[k].end
+			}
+		}
+		if cur.Before(iv.end) {
+			result = append(result, interval{start: cur, end: iv.end})
+		}
+	}
 	return result
 }
 
-// Helper functions to get max and min of two integers
-func max(a, b int) int {
-	if a > b {
-		return a
+// intersectIntervals returns the ranges covered by both a and b, both have to be sorted and disjoint
+func intersectIntervals(a, b []interval) []interval {
+	var result []interval
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		start, end := a[i].start, a[i].end
+		if b[j].start.After(start) {
+			start = b[j].start
+		}
+		if b[j].end.Before(end) {
+			end = b[j].end
+		}
+		if start.Before(end) {
+			result = append(result, interval{start: start, end: end})
+		}
+		// Move to the next interval in the list that ends first
+		if a[i].end.Before(b[j].end) {
+			i++
+		} else {
+			j++
+		}
 	}
-	return b
+	return result
 }
 
-func min(a, b int) int {
-	if a < b {
-		return a
+// clipIntervals cuts the sorted intervals to [start, end)
+func clipIntervals(intervals []interval, start, end time.Time) []interval {
+	var result []interval
+	for _, iv := range intervals {
+		if iv.start.Before(start) {
+			iv.start = start
+		}
+		if iv.end.After(end) {
+			iv.end = end
+		}
+		if iv.start.Before(iv.end) {
+			result = append(result, iv)
+		}
 	}
-	return b
+	return result
 }
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/niharika88/calendly-api/internal/db/models"
+	"github.com/niharika88/calendly-api/internal/db/repo"
+	"github.com/niharika88/calendly-api/pkg/api"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+// pg error code for exclusion constraint violations, raised by bookings_no_overlap
+const pgExclusionViolation = "23P01"
+
+type BookingService interface {
+	Create(ctx context.Context, host *models.User, req *api.CreateBookingRequest) (*models.Booking, error)
+	GetBookings(ctx context.Context, hostID uuid.UUID, fromDate, toDate time.Time, status models.BookingStatus) ([]*models.Booking, error)
+	Cancel(ctx context.Context, token string, req *api.CancelBookingRequest) (*models.Booking, error)
+	Reschedule(ctx context.Context, token string, req *api.RescheduleBookingRequest) (*models.Booking, error)
+}
+
+type bookingService struct {
+	bookingRepo         repo.BookingRepo
+	userRepo            repo.UserRepo
+	availabilityService AvailabilityService
+	eventTypeService    EventTypeService
+	invitationService   InvitationService
+}
+
+func NewBookingService(
+	bookingRepo repo.BookingRepo,
+	userRepo repo.UserRepo,
+	availabilityService AvailabilityService,
+	eventTypeService EventTypeService,
+	invitationService InvitationService,
+) BookingService {
+	return &bookingService{
+		bookingRepo:         bookingRepo,
+		userRepo:            userRepo,
+		availabilityService: availabilityService,
+		eventTypeService:    eventTypeService,
+		invitationService:   invitationService,
+	}
+}
+
+func (bs *bookingService) Create(ctx context.Context, host *models.User, req *api.CreateBookingRequest) (*models.Booking, error) {
+	var eventType *models.EventType
+	if req.EventType != "" {
+		var err error
+		eventType, err = bs.eventTypeService.GetBySlug(ctx, host.ID, req.EventType)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, api.NotFoundErr(api.ErrEventTypeNotFound, err)
+			}
+			return nil, api.ServerErr(err)
+		}
+		req.EndTime = req.StartTime.Add(time.Duration(eventType.Duration) * time.Minute)
+	}
+	// the booking is checked under the host's lock: the caps count the host's other bookings and the rules of an
+	// event type (notice, buffers) look around them, bookings_no_overlap only keeps the booking times themselves apart
+	validate := func(ctx context.Context) error {
+		return bs.validateTime(ctx, host, eventType, req.StartTime, req.EndTime, uuid.Nil)
+	}
+
+	token, tokenHash, err := newSecretToken()
+	if err != nil {
+		return nil, api.ServerErr(err)
+	}
+
+	booking := &models.Booking{
+		UserID:       host.ID,
+		InviteeName:  req.InviteeName,
+		InviteeEmail: req.InviteeEmail,
+		StartTime:    req.StartTime,
+		EndTime:      req.EndTime,
+		Status:       models.BookingStatusConfirmed,
+		Notes:        req.Notes,
+		TokenHash:    tokenHash,
+		Token:        token,
+	}
+	if eventType != nil {
+		booking.EventTypeID = &eventType.ID
+	}
+
+	if err := bs.bookingRepo.InsertChecked(ctx, booking, validate); err != nil {
+		if api.IsHTTPError(err) {
+			return nil, err
+		}
+		if isExclusionViolation(err) {
+			return nil, api.ConflictErr(api.ErrSlotUnavailable, err)
+		}
+		return nil, api.ServerErr(err)
+	}
+
+	bs.invitationService.Send(ctx, host, booking, InvitationCreated)
+	return booking, nil
+}
+
+func (bs *bookingService) GetBookings(ctx context.Context, hostID uuid.UUID, fromDate, toDate time.Time, status models.BookingStatus) ([]*models.Booking, error) {
+	return bs.bookingRepo.GetBookings(ctx, hostID, fromDate, toDate.AddDate(0, 0, 1), status, true)
+}
+
+func (bs *bookingService) Cancel(ctx context.Context, token string, req *api.CancelBookingRequest) (*models.Booking, error) {
+	booking, err := bs.findChangeableBooking(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	change := &models.BookingChange{
+		BookingID:         booking.ID,
+		Action:            models.BookingChangeCancelled,
+		PreviousStartTime: booking.StartTime,
+		PreviousEndTime:   booking.EndTime,
+		Reason:            req.Reason,
+	}
+	booking.Status = models.BookingStatusCancelled
+
+	if err := bs.bookingRepo.UpdateWithChange(ctx, booking, change, nil); err != nil {
+		if errors.Is(err, repo.ErrBookingNotConfirmed) {
+			return nil, api.ConflictErr(api.ErrBookingCancelled, err)
+		}
+		return nil, api.ServerErr(err)
+	}
+	if host, err := bs.userRepo.FindByID(ctx, booking.UserID, false); err != nil {
+		slog.ErrorContext(ctx, "unable to find the host of the cancelled booking", "booking", booking.ID, "error", err)
+	} else {
+		bs.invitationService.Send(ctx, host, booking, InvitationCancelled)
+	}
+	return booking, nil
+}
+
+// Reschedule moves the booking to the new start time keeping its duration, the new time is validated against
+// the host's availability (and the event type's rules) the same way a new booking is
+func (bs *bookingService) Reschedule(ctx context.Context, token string, req *api.RescheduleBookingRequest) (*models.Booking, error) {
+	booking, err := bs.findChangeableBooking(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	host, err := bs.userRepo.FindByID(ctx, booking.UserID, false)
+	if err != nil {
+		return nil, api.ServerErr(err)
+	}
+
+	var eventType *models.EventType
+	if booking.EventTypeID != nil {
+		eventType, err = bs.eventTypeService.GetByID(ctx, *booking.EventTypeID)
+		if err != nil {
+			return nil, api.ServerErr(err)
+		}
+	}
+
+	newStart := req.StartTime
+	newEnd := newStart.Add(booking.EndTime.Sub(booking.StartTime))
+	validate := func(ctx context.Context) error {
+		return bs.validateTime(ctx, host, eventType, newStart, newEnd, booking.ID)
+	}
+
+	change := &models.BookingChange{
+		BookingID:         booking.ID,
+		Action:            models.BookingChangeRescheduled,
+		PreviousStartTime: booking.StartTime,
+		PreviousEndTime:   booking.EndTime,
+		StartTime:         &newStart,
+		EndTime:           &newEnd,
+		Reason:            req.Reason,
+	}
+	booking.StartTime = newStart
+	booking.EndTime = newEnd
+
+	if err := bs.bookingRepo.UpdateWithChange(ctx, booking, change, validate); err != nil {
+		switch {
+		case api.IsHTTPError(err):
+			return nil, err
+		case errors.Is(err, repo.ErrBookingNotConfirmed):
+			return nil, api.ConflictErr(api.ErrBookingCancelled, err)
+		case isExclusionViolation(err):
+			return nil, api.ConflictErr(api.ErrSlotUnavailable, err)
+		}
+		return nil, api.ServerErr(err)
+	}
+	bs.invitationService.Send(ctx, host, booking, InvitationRescheduled)
+	return booking, nil
+}
+
+// validateTime checks the host is free for [start, end) and under their booking caps, bookings of an event type
+// also have to follow its rules
+func (bs *bookingService) validateTime(ctx context.Context, host *models.User, eventType *models.EventType, start, end time.Time, ignoreBookingID uuid.UUID) error {
+	if eventType != nil {
+		return bs.eventTypeService.ValidateStart(ctx, host, eventType, start, ignoreBookingID)
+	}
+	available, err := bs.availabilityService.IsAvailable(ctx, host, nil, start, end, ignoreBookingID)
+	if err != nil {
+		return api.ServerErr(err)
+	}
+	if !available {
+		return api.ConflictErr(api.ErrSlotUnavailable, nil)
+	}
+	return bs.eventTypeService.CheckCaps(ctx, host, nil, start, ignoreBookingID)
+}
+
+// findChangeableBooking looks up the booking by the invitee's token, only upcoming confirmed bookings can be changed
+func (bs *bookingService) findChangeableBooking(ctx context.Context, token string) (*models.Booking, error) {
+	booking, err := bs.bookingRepo.FindByTokenHash(ctx, hashSecretToken(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, api.NotFoundErr(api.ErrBookingNotFound, err)
+		}
+		return nil, api.ServerErr(err)
+	}
+	if booking.Status != models.BookingStatusConfirmed {
+		return nil, api.ConflictErr(api.ErrBookingCancelled, nil)
+	}
+	if !booking.StartTime.After(time.Now()) {
+		return nil, api.BadRequestErr("booking has already started", nil)
+	}
+	return booking, nil
+}
+
+// newSecretToken returns a random url safe token (e.g. for an invitee or a calendar feed) and its hash to be stored
+func newSecretToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, hashSecretToken(token), nil
+}
+
+func hashSecretToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func isExclusionViolation(err error) bool {
+	var pgErr pgdriver.Error
+	return errors.As(err, &pgErr) && pgErr.Field('C') == pgExclusionViolation
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/niharika88/calendly-api/internal/db/models"
+	"github.com/niharika88/calendly-api/internal/db/repo"
+	"github.com/niharika88/calendly-api/pkg/api"
+	"github.com/niharika88/calendly-api/pkg/icalendar"
+)
+
+type BusyService interface {
+	Import(ctx context.Context, user *models.User, r io.Reader, horizonDays int) (*api.BusyImportResponse, error)
+	GetAll(ctx context.Context, user *models.User, fromDate, toDate time.Time) ([]*models.BusyBlock, error)
+}
+
+type busyService struct {
+	busyBlockRepo repo.BusyBlockRepo
+}
+
+func NewBusyService(busyBlockRepo repo.BusyBlockRepo) BusyService {
+	return &busyService{
+		busyBlockRepo: busyBlockRepo,
+	}
+}
+
+// Import stores the occurrences of the VEVENTs of the iCalendar file from today to horizonDays days ahead as busy blocks of the user.
+// The blocks an earlier import made for the same UIDs are replaced, so a cancelled or moved event frees its old time
+func (bs *busyService) Import(ctx context.Context, user *models.User, r io.Reader, horizonDays int) (*api.BusyImportResponse, error) {
+	cal, err := ical.NewDecoder(r).Decode()
+	if err != nil {
+		return nil, api.BadRequestErr("invalid iCalendar file", err)
+	}
+
+	loc := user.Location()
+	today := calendarDate(time.Now().In(loc))
+	rangeStart, rangeEnd := dateRange(today, today.AddDate(0, 0, horizonDays), loc)
+	events, warnings := icalendar.Busy(cal, loc, rangeStart, rangeEnd)
+
+	uids := make([]string, 0, len(events))
+	var busyBlocks []*models.BusyBlock
+	for _, event := range events {
+		uids = append(uids, event.UID)
+		for _, p := range event.Periods {
+			busyBlocks = append(busyBlocks, &models.BusyBlock{
+				UserID:    user.ID,
+				Source:    models.BusySourceImport,
+				UID:       event.UID,
+				Summary:   event.Summary,
+				StartTime: p.Start.UTC(),
+				EndTime:   p.End.UTC(),
+			})
+		}
+	}
+	if err := bs.busyBlockRepo.ReplaceByUIDs(ctx, user.ID, models.BusySourceImport, uids, busyBlocks); err != nil {
+		return nil, api.ServerErr(err)
+	}
+	return &api.BusyImportResponse{Events: len(events), Blocks: len(busyBlocks), Warnings: warnings}, nil
+}
+
+// GetAll returns the busy blocks of the user overlapping the dates fromDate to toDate (both inclusive) of their timezone
+func (bs *busyService) GetAll(ctx context.Context, user *models.User, fromDate, toDate time.Time) ([]*models.BusyBlock, error) {
+	rangeStart, rangeEnd := dateRange(fromDate, toDate, user.Location())
+	return bs.busyBlockRepo.GetAllByUser(ctx, user.ID, rangeStart, rangeEnd)
+}
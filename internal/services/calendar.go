@@ -0,0 +1,407 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/google/uuid"
+	"github.com/niharika88/calendly-api/internal/db/models"
+	"github.com/niharika88/calendly-api/internal/db/repo"
+	"github.com/niharika88/calendly-api/pkg/api"
+	"github.com/niharika88/calendly-api/pkg/caldav"
+	"github.com/niharika88/calendly-api/pkg/icalendar"
+)
+
+// calendars served over CalDAV, by their path segment
+const (
+	CalendarBookings     = "bookings"
+	CalendarAvailability = "availability"
+)
+
+type CalendarService interface {
+	RotateFeedToken(ctx context.Context, user *models.User, credential, adminToken string) (string, error)
+	Feed(ctx context.Context, user *models.User, token string, days int) ([]byte, error)
+	FreeBusy(ctx context.Context, user *models.User, fromDate, toDate time.Time) ([]byte, error)
+	FreeBusyRange(ctx context.Context, user *models.User, start, end time.Time) ([]byte, error)
+	FreeBusyReply(ctx context.Context, r io.Reader) ([]byte, error)
+	CheckFeedToken(user *models.User, token string) error
+	Calendars(user *models.User) []caldav.Calendar
+	CalendarObjects(ctx context.Context, user *models.User, calendar string, start, end time.Time, days int) ([]*caldav.Object, error)
+	CalendarObject(ctx context.Context, user *models.User, calendar, name string) (*caldav.Object, error)
+}
+
+type calendarService struct {
+	userRepo            repo.UserRepo
+	bookingRepo         repo.BookingRepo
+	availabilityService AvailabilityService
+}
+
+func NewCalendarService(userRepo repo.UserRepo, bookingRepo repo.BookingRepo, availabilityService AvailabilityService) CalendarService {
+	return &calendarService{
+		userRepo:            userRepo,
+		bookingRepo:         bookingRepo,
+		availabilityService: availabilityService,
+	}
+}
+
+// RotateFeedToken issues a new secret token for the user's iCalendar feed, the previous one stops working.
+// The credential proves the ownership of the feed: the current token, or the admin token (when configured) which is the only way
+// to issue the first token of a user, 401 otherwise
+func (cs *calendarService) RotateFeedToken(ctx context.Context, user *models.User, credential, adminToken string) (string, error) {
+	isAdmin := adminToken != "" && subtle.ConstantTimeCompare([]byte(credential), []byte(adminToken)) == 1
+	if !isAdmin && cs.CheckFeedToken(user, credential) != nil {
+		return "", api.CustomErr(http.StatusUnauthorized, api.ErrFeedTokenRequired, nil)
+	}
+	token, tokenHash, err := newSecretToken()
+	if err != nil {
+		return "", api.ServerErr(err)
+	}
+	user.CalendarFeedTokenHash = tokenHash
+	if err := cs.userRepo.Update(ctx, user); err != nil {
+		return "", api.ServerErr(err)
+	}
+	return token, nil
+}
+
+// Feed renders the free time of the user's default schedule and the bookings from today to `days` days ahead as an iCalendar object.
+// Times are in the user's timezone, a free window or a booking keeps its UID across refreshes so clients update it in place
+func (cs *calendarService) Feed(ctx context.Context, user *models.User, token string, days int) ([]byte, error) {
+	if err := cs.CheckFeedToken(user, token); err != nil {
+		return nil, err
+	}
+
+	loc := user.Location()
+	now := time.Now()
+	fromDate := calendarDate(now.In(loc))
+	toDate := fromDate.AddDate(0, 0, days)
+	rangeStart, rangeEnd := dateRange(fromDate, toDate, loc)
+
+	availability, err := cs.availabilityService.GetAvailability(ctx, user, nil, fromDate, toDate, loc)
+	if err != nil {
+		return nil, err
+	}
+	bookings, err := cs.bookingRepo.GetBookings(ctx, user.ID, rangeStart, rangeEnd, "", true)
+	if err != nil {
+		return nil, api.ServerErr(err)
+	}
+
+	cal := icalendar.NewCalendar()
+	icalendar.SetName(cal, fmt.Sprintf("%s (%s)", fullName(user), user.Username))
+	icalendar.AddTimezone(cal, loc, rangeStart, rangeEnd)
+
+	for _, iv := range toIntervals(availability, fromDate, toDate, loc) {
+		cal.Children = append(cal.Children, availabilityEvent(user, iv, loc, now).Component)
+	}
+	for _, b := range bookings {
+		cal.Children = append(cal.Children, bookingEvent(b, loc).Component)
+	}
+
+	res, err := icalendar.Encode(cal)
+	if err != nil {
+		return nil, api.ServerErr(err)
+	}
+	return res, nil
+}
+
+// FreeBusy renders the free and busy time of the user's default schedule over the dates fromDate to toDate (both inclusive)
+// of their timezone as a VFREEBUSY
+func (cs *calendarService) FreeBusy(ctx context.Context, user *models.User, fromDate, toDate time.Time) ([]byte, error) {
+	start, end := dateRange(fromDate, toDate, user.Location())
+	return cs.FreeBusyRange(ctx, user, start, end)
+}
+
+// FreeBusyRange renders the free and busy time of the user's default schedule within [start, end) as a VFREEBUSY
+func (cs *calendarService) FreeBusyRange(ctx context.Context, user *models.User, start, end time.Time) ([]byte, error) {
+	comp, err := cs.freeBusy(ctx, user, icalendar.UID("freebusy", fmt.Sprintf("%s-%d-%d", user.ID, start.Unix(), end.Unix())), start, end)
+	if err != nil {
+		return nil, err
+	}
+	if user.Email != "" {
+		icalendar.SetCalendarAddress(comp.Props, ical.PropOrganizer, user.Email)
+	}
+
+	cal := icalendar.NewCalendar()
+	cal.Children = append(cal.Children, comp)
+	res, err := icalendar.Encode(cal)
+	if err != nil {
+		return nil, api.ServerErr(err)
+	}
+	return res, nil
+}
+
+// FreeBusyReply answers an iTIP free/busy REQUEST (RFC 5546) with a REPLY holding a VFREEBUSY per attendee, attendees are
+// users found by the (case insensitive) email of their mailto: address. Unknown attendees are busy for the whole range,
+// the same as a user without availability, so the reply doesn't tell which emails are registered
+func (cs *calendarService) FreeBusyReply(ctx context.Context, r io.Reader) ([]byte, error) {
+	reqCal, err := ical.NewDecoder(r).Decode()
+	if err != nil {
+		return nil, api.BadRequestErr("invalid iCalendar object", err)
+	}
+	req, err := icalendar.ParseFreeBusyRequest(reqCal)
+	if err != nil {
+		return nil, api.BadRequestErr("invalid free/busy request: "+err.Error(), err)
+	}
+	if len(req.Attendees) > api.MaxFreeBusyAttendees {
+		return nil, api.BadRequestErr(fmt.Sprintf("too many attendees, at most %d are supported", api.MaxFreeBusyAttendees), nil)
+	}
+	if req.End.Sub(req.Start) > api.MaxDateRangeDays*24*time.Hour {
+		return nil, api.BadRequestErr(fmt.Sprintf("invalid date range, should span at most %d days", api.MaxDateRangeDays), nil)
+	}
+	if req.UID == "" {
+		req.UID = icalendar.UID("freebusy", fmt.Sprintf("%d-%d", req.Start.Unix(), req.End.Unix()))
+	}
+
+	cal := icalendar.NewCalendar()
+	cal.Props.SetText(ical.PropMethod, icalendar.MethodReply)
+	for _, attendee := range req.Attendees {
+		email := attendee
+		if scheme, address, ok := strings.Cut(attendee, ":"); ok && strings.EqualFold(scheme, "mailto") {
+			email = address
+		}
+		var comp *ical.Component
+		user, err := cs.userRepo.FindByEmail(ctx, email)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			busy := []icalendar.Period{{Start: req.Start, End: req.End}}
+			comp = icalendar.NewFreeBusy(req.UID, req.Start, req.End, time.Now(), nil, busy)
+		case err != nil:
+			return nil, api.ServerErr(err)
+		default:
+			if comp, err = cs.freeBusy(ctx, user, req.UID, req.Start, req.End); err != nil {
+				return nil, err
+			}
+		}
+		icalendar.SetCalendarAddress(comp.Props, ical.PropAttendee, attendee)
+		if req.Organizer != "" {
+			icalendar.SetCalendarAddress(comp.Props, ical.PropOrganizer, req.Organizer)
+		}
+		cal.Children = append(cal.Children, comp)
+	}
+
+	res, err := icalendar.Encode(cal)
+	if err != nil {
+		return nil, api.ServerErr(err)
+	}
+	return res, nil
+}
+
+// CheckFeedToken checks the token is the user's current calendar feed token, 401 otherwise
+func (cs *calendarService) CheckFeedToken(user *models.User, token string) error {
+	if user.CalendarFeedTokenHash == "" ||
+		subtle.ConstantTimeCompare([]byte(hashSecretToken(token)), []byte(user.CalendarFeedTokenHash)) != 1 {
+		return api.CustomErr(http.StatusUnauthorized, api.ErrInvalidFeedToken, nil)
+	}
+	return nil
+}
+
+// Calendars returns the calendars of the user served over CalDAV
+func (cs *calendarService) Calendars(user *models.User) []caldav.Calendar {
+	return []caldav.Calendar{
+		{
+			Name:        CalendarBookings,
+			DisplayName: fmt.Sprintf("Bookings of %s", fullName(user)),
+			Description: "Meetings booked with " + user.Username,
+		},
+		{
+			Name:        CalendarAvailability,
+			DisplayName: fmt.Sprintf("Availability of %s", fullName(user)),
+			Description: "Free time of the default schedule of " + user.Username + ", one object per date",
+		},
+	}
+}
+
+// CalendarObjects returns the objects of the user's calendar overlapping [start, end): a booking per object or the free time
+// of a date per object (dates in the user's timezone). Zero bounds list the objects from today for `days` days like the feed,
+// a single open bound spans MaxDateRangeDays, as does any wider range
+func (cs *calendarService) CalendarObjects(ctx context.Context, user *models.User, calendar string, start, end time.Time, days int) ([]*caldav.Object, error) {
+	loc := user.Location()
+	switch {
+	case start.IsZero() && end.IsZero():
+		today := calendarDate(time.Now().In(loc))
+		start, end = dateRange(today, today.AddDate(0, 0, days), loc)
+	case start.IsZero():
+		start = end.AddDate(0, 0, -api.MaxDateRangeDays)
+	case end.IsZero() || end.Sub(start) > api.MaxDateRangeDays*24*time.Hour:
+		end = start.AddDate(0, 0, api.MaxDateRangeDays)
+	}
+
+	switch calendar {
+	case CalendarBookings:
+		bookings, err := cs.bookingRepo.GetBookings(ctx, user.ID, start, end, "", true)
+		if err != nil {
+			return nil, api.ServerErr(err)
+		}
+		objects := make([]*caldav.Object, 0, len(bookings))
+		for _, b := range bookings {
+			object, err := bookingObject(b, loc)
+			if err != nil {
+				return nil, err
+			}
+			objects = append(objects, object)
+		}
+		return objects, nil
+	case CalendarAvailability:
+		return cs.availabilityObjects(ctx, user, calendarDate(start.In(loc)), calendarDate(end.Add(-time.Nanosecond).In(loc)))
+	default:
+		return nil, api.NotFoundErr(api.ErrCalendarNotFound, nil)
+	}
+}
+
+// CalendarObject returns the object of the user's calendar by name, 404 if it doesn't exist
+func (cs *calendarService) CalendarObject(ctx context.Context, user *models.User, calendar, name string) (*caldav.Object, error) {
+	id, ok := strings.CutSuffix(name, ".ics")
+	if !ok {
+		return nil, api.NotFoundErr(api.ErrCalendarObjectNotFound, nil)
+	}
+	switch calendar {
+	case CalendarBookings:
+		bookingID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, api.NotFoundErr(api.ErrCalendarObjectNotFound, err)
+		}
+		b, err := cs.bookingRepo.FindByID(ctx, bookingID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, api.NotFoundErr(api.ErrCalendarObjectNotFound, err)
+			}
+			return nil, api.ServerErr(err)
+		}
+		if b.UserID != user.ID {
+			return nil, api.NotFoundErr(api.ErrCalendarObjectNotFound, nil)
+		}
+		return bookingObject(b, user.Location())
+	case CalendarAvailability:
+		date, err := time.Parse("2006-01-02", id)
+		if err != nil {
+			return nil, api.NotFoundErr(api.ErrCalendarObjectNotFound, err)
+		}
+		objects, err := cs.availabilityObjects(ctx, user, date, date)
+		if err != nil {
+			return nil, err
+		}
+		if len(objects) == 0 {
+			return nil, api.NotFoundErr(api.ErrCalendarObjectNotFound, nil)
+		}
+		return objects[0], nil
+	default:
+		return nil, api.NotFoundErr(api.ErrCalendarNotFound, nil)
+	}
+}
+
+// availabilityObjects returns an object per date from fromDate to toDate holding the free time of the date, dates without any are left out.
+// Free time running past midnight is split between the two dates so that a date's object doesn't depend on the listed range
+func (cs *calendarService) availabilityObjects(ctx context.Context, user *models.User, fromDate, toDate time.Time) ([]*caldav.Object, error) {
+	loc := user.Location()
+	// slots of the previous date may run into fromDate
+	availability, err := cs.availabilityService.GetAvailability(ctx, user, nil, fromDate.AddDate(0, 0, -1), toDate, loc)
+	if err != nil {
+		return nil, err
+	}
+	intervals := toIntervals(availability, fromDate.AddDate(0, 0, -1), toDate, loc)
+
+	var objects []*caldav.Object
+	for date := fromDate; !date.After(toDate); date = date.AddDate(0, 0, 1) {
+		dayStart, dayEnd := dateRange(date, date, loc)
+		free := clipIntervals(intervals, dayStart, dayEnd)
+		if len(free) == 0 {
+			continue
+		}
+		cal := icalendar.NewCalendar()
+		icalendar.AddTimezone(cal, loc, dayStart, dayEnd)
+		for _, iv := range free {
+			// a stable DTSTAMP keeps the data, hence the ETag, of an unchanged date
+			cal.Children = append(cal.Children, availabilityEvent(user, iv, loc, dayStart).Component)
+		}
+		data, err := icalendar.Encode(cal)
+		if err != nil {
+			return nil, api.ServerErr(err)
+		}
+		objects = append(objects, &caldav.Object{Name: date.Format("2006-01-02") + ".ics", Data: data})
+	}
+	return objects, nil
+}
+
+// freeBusy builds the VFREEBUSY of the user within [start, end) from their availability, everything that's not free is busy
+func (cs *calendarService) freeBusy(ctx context.Context, user *models.User, uid string, start, end time.Time) (*ical.Component, error) {
+	fromDate, toDate := calendarDate(start.UTC()), calendarDate(end.Add(-time.Nanosecond).UTC())
+	availability, err := cs.availabilityService.GetAvailability(ctx, user, nil, fromDate, toDate, time.UTC)
+	if err != nil {
+		return nil, err
+	}
+	free := clipIntervals(toIntervals(availability, fromDate, toDate, time.UTC), start, end)
+	busy := subtractIntervals([]interval{{start: start, end: end}}, free)
+	return icalendar.NewFreeBusy(uid, start, end, time.Now(), toPeriods(free), toPeriods(busy)), nil
+}
+
+func toPeriods(intervals []interval) []icalendar.Period {
+	periods := make([]icalendar.Period, 0, len(intervals))
+	for _, iv := range intervals {
+		periods = append(periods, icalendar.Period{Start: iv.start, End: iv.end})
+	}
+	return periods
+}
+
+// availabilityEvent renders the free time as a transparent "Available" VEVENT, its UID is given by the user and the start
+func availabilityEvent(user *models.User, iv interval, loc *time.Location, stamp time.Time) *ical.Event {
+	uid := icalendar.UID("availability", fmt.Sprintf("%s-%d", user.ID, iv.start.Unix()))
+	event := icalendar.NewEvent(uid, iv.start.In(loc), iv.end.In(loc), stamp)
+	event.Props.SetText(ical.PropSummary, "Available")
+	// free time must not show the user as busy in the subscriber's calendar
+	event.Props.SetText(ical.PropTransparency, "TRANSPARENT")
+	return event
+}
+
+// bookingObject renders the booking as a calendar object of its own
+func bookingObject(b *models.Booking, loc *time.Location) (*caldav.Object, error) {
+	cal := icalendar.NewCalendar()
+	icalendar.AddTimezone(cal, loc, b.StartTime, b.EndTime)
+	cal.Children = append(cal.Children, bookingEvent(b, loc).Component)
+	data, err := icalendar.Encode(cal)
+	if err != nil {
+		return nil, api.ServerErr(err)
+	}
+	return &caldav.Object{Name: b.ID.String() + ".ics", ModTime: b.UpdatedAt, Data: data}, nil
+}
+
+// bookingEvent renders the booking as a VEVENT, every reschedule or cancellation bumps its SEQUENCE
+func bookingEvent(b *models.Booking, loc *time.Location) *ical.Event {
+	stamp := b.CreatedAt
+	if b.UpdatedAt.After(stamp) {
+		stamp = b.UpdatedAt
+	}
+	event := icalendar.NewEvent(icalendar.UID("booking", b.ID.String()), b.StartTime.In(loc), b.EndTime.In(loc), stamp)
+	event.Props.SetText(ical.PropSummary, "Booking with "+b.InviteeName)
+	if b.Notes != "" {
+		event.Props.SetText(ical.PropDescription, b.Notes)
+	}
+	icalendar.SetInteger(event.Props, ical.PropSequence, len(b.Changes))
+	status := "CONFIRMED"
+	if b.Status == models.BookingStatusCancelled {
+		status = "CANCELLED"
+	}
+	event.Props.SetText(ical.PropStatus, status)
+	return event
+}
+
+// fullName returns the first and last name of the user, the username when both are empty
+func fullName(user *models.User) string {
+	name := user.FirstName
+	if user.LastName != "" {
+		if name != "" {
+			name += " "
+		}
+		name += user.LastName
+	}
+	if name == "" {
+		return user.Username
+	}
+	return name
+}
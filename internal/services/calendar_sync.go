@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/niharika88/calendly-api/internal/db/models"
+	"github.com/niharika88/calendly-api/internal/db/repo"
+	"github.com/niharika88/calendly-api/internal/providers"
+	"github.com/niharika88/calendly-api/pkg/api"
+)
+
+// maxSyncAttempts bounds the retries of a sync losing the race against another sync of the same connection
+const maxSyncAttempts = 3
+
+// fullSyncInterval is how often a connection pulls every event again instead of the changes only, incremental syncs only expand
+// the occurrences of changed events so the horizon of unchanged recurring events moves forward with the full syncs
+const fullSyncInterval = 24 * time.Hour
+
+type CalendarSyncService interface {
+	Connect(ctx context.Context, user *models.User, req *api.CreateCalendarConnectionRequest) (*models.CalendarConnection, error)
+	GetConnections(ctx context.Context, userID uuid.UUID) ([]*models.CalendarConnection, error)
+	DeleteConnection(ctx context.Context, userID, id uuid.UUID) error
+	Sync(ctx context.Context, user *models.User, id uuid.UUID) (*api.CalendarSyncResponse, error)
+	SyncAll(ctx context.Context)
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type calendarSyncService struct {
+	connectionRepo repo.CalendarConnectionRepo
+	userRepo       repo.UserRepo
+	providers      providers.Registry
+	horizonDays    int
+}
+
+func NewCalendarSyncService(
+	connectionRepo repo.CalendarConnectionRepo,
+	userRepo repo.UserRepo,
+	providers providers.Registry,
+	horizonDays int,
+) CalendarSyncService {
+	return &calendarSyncService{
+		connectionRepo: connectionRepo,
+		userRepo:       userRepo,
+		providers:      providers,
+		horizonDays:    horizonDays,
+	}
+}
+
+// Connect connects the user's calendar of the provider and syncs it right away, a failed first sync is kept in LastError
+// and retried by the periodic sync
+func (cs *calendarSyncService) Connect(ctx context.Context, user *models.User, req *api.CreateCalendarConnectionRequest) (*models.CalendarConnection, error) {
+	provider, ok := cs.providers[req.Provider]
+	if !ok {
+		return nil, api.BadRequestErr(fmt.Sprintf("unknown calendar provider, should be one of: %s", strings.Join(cs.providers.Names(), ", ")), nil)
+	}
+	connection := &models.CalendarConnection{
+		UserID:   user.ID,
+		Provider: req.Provider,
+		Name:     req.Name,
+		Location: req.Location,
+	}
+	if err := provider.Validate(ctx, connection); err != nil {
+		return nil, api.BadRequestErr("invalid calendar location: "+err.Error(), err)
+	}
+	if err := cs.connectionRepo.Insert(ctx, connection); err != nil {
+		return nil, api.ServerErr(err)
+	}
+	if _, err := cs.sync(ctx, user, connection); err != nil {
+		slog.WarnContext(ctx, "first calendar sync failed", "connection", connection.ID, "error", err)
+	}
+	return connection, nil
+}
+
+func (cs *calendarSyncService) GetConnections(ctx context.Context, userID uuid.UUID) ([]*models.CalendarConnection, error) {
+	return cs.connectionRepo.GetAllByUser(ctx, userID)
+}
+
+// DeleteConnection disconnects the calendar, its busy blocks are deleted along
+func (cs *calendarSyncService) DeleteConnection(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := cs.findConnection(ctx, userID, id); err != nil {
+		return err
+	}
+	return cs.connectionRepo.Delete(ctx, id)
+}
+
+// Sync pulls the changes of the user's connected calendar now
+func (cs *calendarSyncService) Sync(ctx context.Context, user *models.User, id uuid.UUID) (*api.CalendarSyncResponse, error) {
+	connection, err := cs.findConnection(ctx, user.ID, id)
+	if err != nil {
+		return nil, err
+	}
+	return cs.sync(ctx, user, connection)
+}
+
+// SyncAll syncs every connected calendar, a failed sync is logged and kept in the connection's LastError
+func (cs *calendarSyncService) SyncAll(ctx context.Context) {
+	connections, err := cs.connectionRepo.GetAll(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "unable to list calendar connections", "error", err)
+		return
+	}
+	users := make(map[uuid.UUID]*models.User)
+	for _, connection := range connections {
+		user, ok := users[connection.UserID]
+		if !ok {
+			if user, err = cs.userRepo.FindByID(ctx, connection.UserID, false); err != nil {
+				slog.ErrorContext(ctx, "unable to find the user of a calendar connection", "connection", connection.ID, "error", err)
+				continue
+			}
+			users[connection.UserID] = user
+		}
+		if _, err := cs.sync(ctx, user, connection); err != nil {
+			slog.WarnContext(ctx, "calendar sync failed", "connection", connection.ID, "error", err)
+		}
+	}
+}
+
+// Run syncs every connected calendar each interval until the context is done
+func (cs *calendarSyncService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cs.SyncAll(ctx)
+		}
+	}
+}
+
+// sync pulls the changes of the connection, a sync started from a sync token another sync (e.g. the periodic one and a manual one)
+// has moved on from is started again from the connection as stored
+func (cs *calendarSyncService) sync(ctx context.Context, user *models.User, connection *models.CalendarConnection) (*api.CalendarSyncResponse, error) {
+	for attempt := 1; ; attempt++ {
+		res, err := cs.syncOnce(ctx, user, connection)
+		if !errors.Is(err, repo.ErrConcurrentSync) {
+			return res, err
+		}
+		if attempt == maxSyncAttempts {
+			return nil, api.ConflictErr(api.ErrCalendarSyncConflict, err)
+		}
+		slog.InfoContext(ctx, "calendar synced concurrently, syncing again", "connection", connection.ID)
+		if connection, err = cs.findConnection(ctx, user.ID, connection.ID); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// syncOnce pulls the events changed since the connection's sync token (every event for a full sync) and replaces their busy blocks,
+// occurrences are expanded from today (of the user's timezone) for horizonDays. An expired sync token falls back to a full sync
+func (cs *calendarSyncService) syncOnce(ctx context.Context, user *models.User, connection *models.CalendarConnection) (*api.CalendarSyncResponse, error) {
+	provider, ok := cs.providers[connection.Provider]
+	if !ok {
+		return nil, cs.syncFailed(ctx, connection, fmt.Errorf("calendar provider %q is not enabled", connection.Provider))
+	}
+
+	now := time.Now()
+	loc := user.Location()
+	today := calendarDate(now.In(loc))
+	query := providers.BusyQuery{SyncToken: connection.SyncToken, Location: loc}
+	query.From, query.To = dateRange(today, today.AddDate(0, 0, cs.horizonDays), loc)
+	full := connection.SyncToken == "" || connection.FullSyncedAt == nil || now.Sub(*connection.FullSyncedAt) >= fullSyncInterval
+	if full {
+		query.SyncToken = ""
+	}
+
+	changes, err := provider.ListBusy(ctx, connection, query)
+	if errors.Is(err, providers.ErrSyncTokenExpired) {
+		full, query.SyncToken = true, ""
+		changes, err = provider.ListBusy(ctx, connection, query)
+	}
+	if err != nil {
+		return nil, cs.syncFailed(ctx, connection, err)
+	}
+
+	uids := make([]string, 0, len(changes.Events)+len(changes.Deleted))
+	var busyBlocks []*models.BusyBlock
+	for _, event := range changes.Events {
+		uids = append(uids, event.UID)
+		for _, p := range event.Periods {
+			busyBlocks = append(busyBlocks, &models.BusyBlock{
+				UserID:       user.ID,
+				ConnectionID: &connection.ID,
+				Source:       connection.Provider,
+				UID:          event.UID,
+				Summary:      event.Summary,
+				StartTime:    p.Start.UTC(),
+				EndTime:      p.End.UTC(),
+			})
+		}
+	}
+	uids = append(uids, changes.Deleted...)
+
+	synced := *connection
+	synced.SyncToken = changes.SyncToken
+	synced.LastSyncedAt = &now
+	if full {
+		synced.FullSyncedAt = &now
+	}
+	synced.LastError = ""
+	if err := cs.connectionRepo.ApplySync(ctx, &synced, connection.SyncToken, full, uids, busyBlocks); err != nil {
+		switch {
+		case errors.Is(err, repo.ErrConcurrentSync):
+			return nil, err
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, api.NotFoundErr(api.ErrCalendarConnectionNotFound, err)
+		}
+		return nil, api.ServerErr(err)
+	}
+	*connection = synced
+	for _, warning := range changes.Warnings {
+		slog.WarnContext(ctx, "calendar sync skipped an event", "connection", connection.ID, "warning", warning)
+	}
+	return &api.CalendarSyncResponse{
+		Full:     full,
+		Changed:  len(changes.Events),
+		Deleted:  len(changes.Deleted),
+		Blocks:   len(busyBlocks),
+		Warnings: changes.Warnings,
+	}, nil
+}
+
+// syncFailed keeps the provider's error in the connection's LastError, the sync token is kept for the next try
+func (cs *calendarSyncService) syncFailed(ctx context.Context, connection *models.CalendarConnection, err error) error {
+	connection.LastError = err.Error()
+	if updateErr := cs.connectionRepo.UpdateLastError(ctx, connection); updateErr != nil {
+		slog.ErrorContext(ctx, "unable to save the calendar sync error", "connection", connection.ID, "error", updateErr)
+	}
+	return api.CustomErr(http.StatusBadGateway, api.ErrCalendarSyncFailed, err)
+}
+
+// findConnection returns the user's connection, 404 if it doesn't exist
+func (cs *calendarSyncService) findConnection(ctx context.Context, userID, id uuid.UUID) (*models.CalendarConnection, error) {
+	connection, err := cs.connectionRepo.FindByID(ctx, userID, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, api.NotFoundErr(api.ErrCalendarConnectionNotFound, err)
+		}
+		return nil, api.ServerErr(err)
+	}
+	return connection, nil
+}
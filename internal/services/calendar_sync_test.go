@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/niharika88/calendly-api/internal/db/models"
+	"github.com/niharika88/calendly-api/internal/db/repo"
+	"github.com/niharika88/calendly-api/internal/providers"
+)
+
+// memoryConnectionRepo keeps one calendar connection and its busy blocks in memory, applying syncs like the postgres repo
+type memoryConnectionRepo struct {
+	repo.CalendarConnectionRepo
+	connection *models.CalendarConnection
+	blocks     []*models.BusyBlock
+}
+
+func (m *memoryConnectionRepo) FindByID(ctx context.Context, userID, id uuid.UUID) (*models.CalendarConnection, error) {
+	if m.connection == nil || m.connection.UserID != userID || m.connection.ID != id {
+		return nil, sql.ErrNoRows
+	}
+	connection := *m.connection
+	return &connection, nil
+}
+
+func (m *memoryConnectionRepo) UpdateLastError(ctx context.Context, connection *models.CalendarConnection) error {
+	m.connection.LastError = connection.LastError
+	return nil
+}
+
+func (m *memoryConnectionRepo) ApplySync(ctx context.Context, connection *models.CalendarConnection, previousSyncToken string, full bool, uids []string, busyBlocks []*models.BusyBlock) error {
+	if m.connection.SyncToken != previousSyncToken {
+		return repo.ErrConcurrentSync
+	}
+	m.blocks = slices.DeleteFunc(m.blocks, func(b *models.BusyBlock) bool {
+		return full || slices.Contains(uids, b.UID)
+	})
+	m.blocks = append(m.blocks, busyBlocks...)
+	stored := *connection
+	m.connection = &stored
+	return nil
+}
+
+// blockUIDs returns the UID of every busy block, sorted, one per occurrence
+func (m *memoryConnectionRepo) blockUIDs() []string {
+	uids := make([]string, 0, len(m.blocks))
+	for _, b := range m.blocks {
+		uids = append(uids, b.UID)
+	}
+	slices.Sort(uids)
+	return uids
+}
+
+type syncTest struct {
+	service    *calendarSyncService
+	repo       *memoryConnectionRepo
+	provider   *providers.FakeProvider
+	dir        string
+	user       *models.User
+	connection *models.CalendarConnection
+	start      time.Time // tomorrow at 09:00 UTC, within the sync horizon
+}
+
+func newSyncTest(t *testing.T) *syncTest {
+	t.Helper()
+	dir := t.TempDir()
+	provider, err := providers.NewFakeProvider(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &models.User{ID: uuid.New(), Timezone: "UTC"}
+	connection := &models.CalendarConnection{ID: uuid.New(), UserID: user.ID, Provider: providers.FakeProviderName, Location: "work.json"}
+	memoryRepo := &memoryConnectionRepo{connection: connection}
+	tomorrow := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	return &syncTest{
+		service:    NewCalendarSyncService(memoryRepo, nil, providers.NewRegistry(provider), 30).(*calendarSyncService),
+		repo:       memoryRepo,
+		provider:   provider,
+		dir:        dir,
+		user:       user,
+		connection: connection,
+		start:      tomorrow.Add(9 * time.Hour),
+	}
+}
+
+func (st *syncTest) createEvent(t *testing.T, uid string, start time.Time) {
+	t.Helper()
+	event := providers.Event{UID: uid, Start: start, End: start.Add(time.Hour)}
+	if err := st.provider.CreateEvent(context.Background(), st.connection, event); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (st *syncTest) sync(t *testing.T) (bool, int, int) {
+	t.Helper()
+	connection, err := st.repo.FindByID(context.Background(), st.user.ID, st.connection.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := st.service.sync(context.Background(), st.user, connection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.Full, res.Changed, res.Deleted
+}
+
+func TestCalendarSyncFull(t *testing.T) {
+	st := newSyncTest(t)
+	st.createEvent(t, "a", st.start)
+	st.createEvent(t, "b", st.start.Add(2*time.Hour))
+
+	full, changed, deleted := st.sync(t)
+	if !full || changed != 2 || deleted != 0 {
+		t.Errorf("first sync: full=%v changed=%d deleted=%d, want a full sync of 2 events", full, changed, deleted)
+	}
+	if got := st.repo.blockUIDs(); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("busy blocks = %v, want [a b]", got)
+	}
+	if st.repo.connection.SyncToken != "2" || st.repo.connection.FullSyncedAt == nil || st.repo.connection.LastSyncedAt == nil {
+		t.Errorf("connection not saved after the sync: %+v", st.repo.connection)
+	}
+	for _, b := range st.repo.blocks {
+		if b.UserID != st.user.ID || b.ConnectionID == nil || *b.ConnectionID != st.connection.ID || b.Source != providers.FakeProviderName {
+			t.Errorf("busy block not linked to the connection: %+v", b)
+		}
+	}
+}
+
+func TestCalendarSyncIncremental(t *testing.T) {
+	st := newSyncTest(t)
+	st.createEvent(t, "a", st.start)
+	st.createEvent(t, "b", st.start.Add(2*time.Hour))
+	st.createEvent(t, "c", st.start.Add(4*time.Hour))
+	st.sync(t)
+
+	st.createEvent(t, "a", st.start.Add(24*time.Hour))
+	st.createEvent(t, "d", st.start.Add(6*time.Hour))
+	if err := st.provider.DeleteEvent(context.Background(), st.connection, "b"); err != nil {
+		t.Fatal(err)
+	}
+
+	full, changed, deleted := st.sync(t)
+	if full || changed != 2 || deleted != 1 {
+		t.Errorf("incremental sync: full=%v changed=%d deleted=%d, want 2 changed and 1 deleted", full, changed, deleted)
+	}
+	if got := st.repo.blockUIDs(); !slices.Equal(got, []string{"a", "c", "d"}) {
+		t.Errorf("busy blocks = %v, want [a c d]", got)
+	}
+	for _, b := range st.repo.blocks {
+		if b.UID == "a" && !b.StartTime.Equal(st.start.Add(24*time.Hour)) {
+			t.Errorf("the moved event should have its new time, got %s", b.StartTime)
+		}
+	}
+
+	full, changed, deleted = st.sync(t)
+	if full || changed != 0 || deleted != 0 || len(st.repo.blocks) != 3 {
+		t.Errorf("sync without changes: full=%v changed=%d deleted=%d blocks=%d", full, changed, deleted, len(st.repo.blocks))
+	}
+}
+
+func TestCalendarSyncExpiredToken(t *testing.T) {
+	st := newSyncTest(t)
+	st.createEvent(t, "a", st.start)
+	st.createEvent(t, "b", st.start.Add(2*time.Hour))
+	st.sync(t)
+
+	// the provider purges its tombstones past the connection's sync token, b's deletion can only be seen by a full sync
+	if err := st.provider.DeleteEvent(context.Background(), st.connection, "b"); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(st.dir, st.connection.Location)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	doc["purged_version"] = doc["version"]
+	if data, err = json.Marshal(doc); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	full, changed, deleted := st.sync(t)
+	if !full || changed != 1 || deleted != 0 {
+		t.Errorf("sync with an expired token: full=%v changed=%d deleted=%d, want a full sync of 1 event", full, changed, deleted)
+	}
+	if got := st.repo.blockUIDs(); !slices.Equal(got, []string{"a"}) {
+		t.Errorf("busy blocks = %v, want [a]", got)
+	}
+	if st.repo.connection.SyncToken != "3" {
+		t.Errorf("sync token = %q, want 3", st.repo.connection.SyncToken)
+	}
+}
+
+func TestCalendarSyncConcurrent(t *testing.T) {
+	st := newSyncTest(t)
+	st.createEvent(t, "a", st.start)
+	stale, err := st.repo.FindByID(context.Background(), st.user.ID, st.connection.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st.sync(t)
+
+	// a sync started from the token the first one moved on from starts again from the stored connection
+	st.createEvent(t, "b", st.start.Add(2*time.Hour))
+	res, err := st.service.sync(context.Background(), st.user, stale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Full || res.Changed != 1 {
+		t.Errorf("retried sync: full=%v changed=%d, want 1 changed incrementally", res.Full, res.Changed)
+	}
+	if got := st.repo.blockUIDs(); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("busy blocks = %v, want [a b] without duplicates", got)
+	}
+}
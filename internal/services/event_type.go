@@ -0,0 +1,388 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/niharika88/calendly-api/internal/db/models"
+	"github.com/niharika88/calendly-api/internal/db/repo"
+	"github.com/niharika88/calendly-api/pkg/api"
+)
+
+type EventTypeService interface {
+	Create(ctx context.Context, userID uuid.UUID, req *api.CreateEventTypeRequest) (*models.EventType, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.EventType, error)
+	GetBySlug(ctx context.Context, userID uuid.UUID, slug string) (*models.EventType, error)
+	GetAll(ctx context.Context, userID uuid.UUID) ([]*models.EventType, error)
+	Update(ctx context.Context, userID uuid.UUID, slug string, req *api.UpdateEventTypeRequest) (*models.EventType, error)
+	Delete(ctx context.Context, userID uuid.UUID, slug string) error
+	GetSlots(ctx context.Context, user *models.User, slug string, fromDate, toDate time.Time, loc *time.Location) (*api.EventTypeSlots, error)
+	ValidateStart(ctx context.Context, host *models.User, eventType *models.EventType, start time.Time, ignoreBookingID uuid.UUID) error
+	CheckCaps(ctx context.Context, host *models.User, eventType *models.EventType, start time.Time, ignoreBookingID uuid.UUID) error
+}
+
+type eventTypeService struct {
+	eventTypeRepo       repo.EventTypeRepo
+	bookingRepo         repo.BookingRepo
+	scheduleRepo        repo.ScheduleRepo
+	availabilityService AvailabilityService
+}
+
+func NewEventTypeService(
+	eventTypeRepo repo.EventTypeRepo,
+	bookingRepo repo.BookingRepo,
+	scheduleRepo repo.ScheduleRepo,
+	availabilityService AvailabilityService,
+) EventTypeService {
+	return &eventTypeService{
+		eventTypeRepo:       eventTypeRepo,
+		bookingRepo:         bookingRepo,
+		scheduleRepo:        scheduleRepo,
+		availabilityService: availabilityService,
+	}
+}
+
+func (es *eventTypeService) Create(ctx context.Context, userID uuid.UUID, req *api.CreateEventTypeRequest) (*models.EventType, error) {
+	eventType := &models.EventType{
+		UserID:        userID,
+		Name:          req.Name,
+		Slug:          req.Slug,
+		Description:   req.Description,
+		Duration:      req.Duration,
+		SlotIncrement: req.SlotIncrement,
+		ScheduleID:    req.ScheduleID,
+
+		MinNotice:      req.MinNotice,
+		BookingHorizon: req.BookingHorizon,
+		BufferBefore:   req.BufferBefore,
+		BufferAfter:    req.BufferAfter,
+		DailyCap:       req.DailyCap,
+		WeeklyCap:      req.WeeklyCap,
+	}
+	if req.ScheduleID != nil {
+		// the schedule has to be one of the user's own
+		if _, err := es.scheduleRepo.FindByID(ctx, userID, *req.ScheduleID, false, ""); err != nil {
+			return nil, err
+		}
+	}
+	if err := es.eventTypeRepo.Insert(ctx, eventType); err != nil {
+		return nil, err
+	}
+	return eventType, nil
+}
+
+func (es *eventTypeService) GetByID(ctx context.Context, id uuid.UUID) (*models.EventType, error) {
+	return es.eventTypeRepo.FindByID(ctx, id)
+}
+
+func (es *eventTypeService) GetBySlug(ctx context.Context, userID uuid.UUID, slug string) (*models.EventType, error) {
+	return es.eventTypeRepo.FindBySlug(ctx, userID, slug)
+}
+
+func (es *eventTypeService) GetAll(ctx context.Context, userID uuid.UUID) ([]*models.EventType, error) {
+	return es.eventTypeRepo.GetAllByUser(ctx, userID)
+}
+
+func (es *eventTypeService) Update(ctx context.Context, userID uuid.UUID, slug string, req *api.UpdateEventTypeRequest) (*models.EventType, error) {
+	eventType, err := es.eventTypeRepo.FindBySlug(ctx, userID, slug)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		eventType.Name = *req.Name
+	}
+	if req.Slug != nil {
+		eventType.Slug = *req.Slug
+	}
+	if req.Description != nil {
+		eventType.Description = *req.Description
+	}
+	if req.Duration != nil {
+		eventType.Duration = *req.Duration
+	}
+	if req.SlotIncrement != nil {
+		eventType.SlotIncrement = *req.SlotIncrement
+	}
+	if req.ScheduleID != nil {
+		eventType.ScheduleID = nil
+		if *req.ScheduleID != uuid.Nil {
+			if _, err := es.scheduleRepo.FindByID(ctx, userID, *req.ScheduleID, false, ""); err != nil {
+				return nil, err
+			}
+			eventType.ScheduleID = req.ScheduleID
+		}
+	}
+	if req.MinNotice != nil {
+		eventType.MinNotice = *req.MinNotice
+	}
+	if req.BookingHorizon != nil {
+		eventType.BookingHorizon = *req.BookingHorizon
+	}
+	if req.BufferBefore != nil {
+		eventType.BufferBefore = *req.BufferBefore
+	}
+	if req.BufferAfter != nil {
+		eventType.BufferAfter = *req.BufferAfter
+	}
+	if req.DailyCap != nil {
+		eventType.DailyCap = *req.DailyCap
+	}
+	if req.WeeklyCap != nil {
+		eventType.WeeklyCap = *req.WeeklyCap
+	}
+	if err := es.eventTypeRepo.Update(ctx, eventType); err != nil {
+		return nil, err
+	}
+	return eventType, nil
+}
+
+func (es *eventTypeService) Delete(ctx context.Context, userID uuid.UUID, slug string) error {
+	eventType, err := es.eventTypeRepo.FindBySlug(ctx, userID, slug)
+	if err != nil {
+		return err
+	}
+	return es.eventTypeRepo.Delete(ctx, eventType.ID)
+}
+
+// GetSlots chops the host's availability into start times of the event's duration, every `SlotIncrement` minutes,
+// leaving out the ones breaking the event's rules (min notice, booking horizon, buffers around other bookings) and
+// the days/weeks where the host's or the event's booking caps are reached. Start times are grouped by their date in loc
+func (es *eventTypeService) GetSlots(ctx context.Context, user *models.User, slug string, fromDate, toDate time.Time, loc *time.Location) (*api.EventTypeSlots, error) {
+	eventType, err := es.eventTypeRepo.FindBySlug(ctx, user.ID, slug)
+	if err != nil {
+		return nil, err
+	}
+	availability, err := es.availabilityService.GetAvailability(ctx, user, eventType.ScheduleID, fromDate, toDate, loc)
+	if err != nil {
+		return nil, err
+	}
+	rangeStart, rangeEnd := dateRange(fromDate, toDate, loc)
+	bookings, err := es.bookingsAround(ctx, user.ID, eventType, rangeStart, rangeEnd)
+	if err != nil {
+		return nil, err
+	}
+	caps, err := es.bookingCaps(ctx, user, eventType, rangeStart, rangeEnd, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+
+	eventSlots := api.EventTypeSlots{
+		EventType: eventType.Slug,
+		Duration:  eventType.Duration,
+		Slots:     make(map[string][]time.Time),
+	}
+	earliest, latest := bookingWindow(eventType, time.Now())
+	for _, free := range toIntervals(availability, fromDate, toDate, loc) {
+		for _, start := range startTimes(free, eventType, user.Location()) {
+			if start.Before(earliest) || (!latest.IsZero() && start.After(latest)) {
+				continue
+			}
+			if violatesBuffers(eventType, start, bookings, uuid.Nil) || caps.reached(start) {
+				continue
+			}
+			start = start.In(loc)
+			dateStr := start.Format("2006-01-02")
+			eventSlots.Slots[dateStr] = append(eventSlots.Slots[dateStr], start)
+		}
+	}
+	return &eventSlots, nil
+}
+
+// ValidateStart enforces the same rules GetSlots applies on a meeting of the event type starting at start,
+// so that a stale client can't book a slot that isn't offered anymore
+func (es *eventTypeService) ValidateStart(ctx context.Context, host *models.User, eventType *models.EventType, start time.Time, ignoreBookingID uuid.UUID) error {
+	earliest, latest := bookingWindow(eventType, time.Now())
+	if start.Before(earliest) {
+		return api.BadRequestErr(fmt.Sprintf("event type needs at least %d minutes notice", eventType.MinNotice), nil)
+	}
+	if !latest.IsZero() && start.After(latest) {
+		return api.BadRequestErr(fmt.Sprintf("event type can only be booked up to %d days ahead", eventType.BookingHorizon), nil)
+	}
+	if !onIncrement(start, eventType, host.Location()) {
+		return api.BadRequestErr(fmt.Sprintf("event type meetings start every %d minutes from midnight", eventType.SlotIncrement), nil)
+	}
+
+	end := start.Add(time.Duration(eventType.Duration) * time.Minute)
+	available, err := es.availabilityService.IsAvailable(ctx, host, eventType.ScheduleID, start, end, ignoreBookingID)
+	if err != nil {
+		return api.ServerErr(err)
+	}
+	if !available {
+		return api.ConflictErr(api.ErrSlotUnavailable, nil)
+	}
+
+	bookings, err := es.bookingsAround(ctx, host.ID, eventType, start, end)
+	if err != nil {
+		return api.ServerErr(err)
+	}
+	if violatesBuffers(eventType, start, bookings, ignoreBookingID) {
+		return api.ConflictErr(api.ErrSlotUnavailable, nil)
+	}
+	return es.CheckCaps(ctx, host, eventType, start, ignoreBookingID)
+}
+
+// CheckCaps makes sure a meeting starting at start doesn't go over the host's daily/weekly booking caps, nor the
+// event type's ones when eventType is given. The count is only reliable under the host's bookings lock (see
+// BookingRepo.InsertChecked), bookings at different times don't conflict otherwise
+func (es *eventTypeService) CheckCaps(ctx context.Context, host *models.User, eventType *models.EventType, start time.Time, ignoreBookingID uuid.UUID) error {
+	caps, err := es.bookingCaps(ctx, host, eventType, start, start, ignoreBookingID)
+	if err != nil {
+		return api.ServerErr(err)
+	}
+	if caps.reached(start) {
+		return api.ConflictErr(api.ErrBookingCapReached, nil)
+	}
+	return nil
+}
+
+// bookingCaps counts the host's confirmed bookings in the weeks (in the host's timezone) of [rangeStart, rangeEnd],
+// it's nil when neither the host nor the event type has caps
+func (es *eventTypeService) bookingCaps(ctx context.Context, host *models.User, eventType *models.EventType, rangeStart, rangeEnd time.Time, ignoreBookingID uuid.UUID) (*capCounter, error) {
+	hasCaps := host.DailyBookingCap > 0 || host.WeeklyBookingCap > 0
+	if eventType != nil {
+		hasCaps = hasCaps || eventType.DailyCap > 0 || eventType.WeeklyCap > 0
+	}
+	if !hasCaps {
+		return nil, nil
+	}
+
+	loc := host.Location()
+	from := weekStart(rangeStart, loc)
+	to := weekStart(rangeEnd, loc).AddDate(0, 0, 7)
+	bookings, err := es.bookingRepo.GetBookings(ctx, host.ID, from, to, models.BookingStatusConfirmed, false)
+	if err != nil {
+		return nil, err
+	}
+
+	caps := &capCounter{
+		host:      host,
+		eventType: eventType,
+		loc:       loc,
+		hostDay:   make(map[string]int),
+		hostWeek:  make(map[string]int),
+		eventDay:  make(map[string]int),
+		eventWeek: make(map[string]int),
+	}
+	for _, b := range bookings {
+		if b.ID == ignoreBookingID {
+			continue
+		}
+		day, week := capKeys(b.StartTime, loc)
+		caps.hostDay[day]++
+		caps.hostWeek[week]++
+		if eventType != nil && b.EventTypeID != nil && *b.EventTypeID == eventType.ID {
+			caps.eventDay[day]++
+			caps.eventWeek[week]++
+		}
+	}
+	return caps, nil
+}
+
+// capCounter holds the number of confirmed bookings per day and per week, keyed by the date (of the week's monday),
+// for the host and for the event type. Bookings count on the day they start
+type capCounter struct {
+	host      *models.User
+	eventType *models.EventType
+	loc       *time.Location
+
+	hostDay, hostWeek   map[string]int
+	eventDay, eventWeek map[string]int
+}
+
+// reached tells if one more booking starting at start would go over any of the caps
+func (c *capCounter) reached(start time.Time) bool {
+	if c == nil {
+		return false
+	}
+	day, week := capKeys(start, c.loc)
+	if capReached(c.host.DailyBookingCap, c.hostDay[day]) || capReached(c.host.WeeklyBookingCap, c.hostWeek[week]) {
+		return true
+	}
+	if c.eventType == nil {
+		return false
+	}
+	return capReached(c.eventType.DailyCap, c.eventDay[day]) || capReached(c.eventType.WeeklyCap, c.eventWeek[week])
+}
+
+func capReached(limit, count int) bool {
+	return limit > 0 && count >= limit
+}
+
+// capKeys returns the date t falls on in loc and the date of that week's monday
+func capKeys(t time.Time, loc *time.Location) (string, string) {
+	return t.In(loc).Format("2006-01-02"), weekStart(t, loc).Format("2006-01-02")
+}
+
+// weekStart returns the midnight of the monday starting the week t falls in, in loc
+func weekStart(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	y, m, d := t.Date()
+	return time.Date(y, m, d-(int(t.Weekday())+6)%7, 0, 0, 0, 0, loc)
+}
+
+// bookingsAround returns the confirmed bookings that can clash with meetings (and their buffers) starting within [rangeStart, rangeEnd)
+func (es *eventTypeService) bookingsAround(ctx context.Context, userID uuid.UUID, eventType *models.EventType, rangeStart, rangeEnd time.Time) ([]*models.Booking, error) {
+	from := rangeStart.Add(-time.Duration(eventType.BufferBefore) * time.Minute)
+	to := rangeEnd.Add(time.Duration(eventType.Duration+eventType.BufferAfter) * time.Minute)
+	return es.bookingRepo.GetBookings(ctx, userID, from, to, models.BookingStatusConfirmed, false)
+}
+
+// bookingWindow returns the earliest and the latest start time the event type can be booked at right now,
+// latest is zero when the event type has no booking horizon
+func bookingWindow(eventType *models.EventType, now time.Time) (time.Time, time.Time) {
+	earliest := now.Add(time.Duration(eventType.MinNotice) * time.Minute)
+	if eventType.BookingHorizon == 0 {
+		return earliest, time.Time{}
+	}
+	return earliest, now.AddDate(0, 0, eventType.BookingHorizon)
+}
+
+// violatesBuffers checks if a meeting starting at start, along with the event's buffers before and after it,
+// overlaps any of the bookings. Only the buffers of the event type being booked are taken into account
+func violatesBuffers(eventType *models.EventType, start time.Time, bookings []*models.Booking, ignoreBookingID uuid.UUID) bool {
+	if eventType.BufferBefore == 0 && eventType.BufferAfter == 0 {
+		return false
+	}
+	from := start.Add(-time.Duration(eventType.BufferBefore) * time.Minute)
+	to := start.Add(time.Duration(eventType.Duration+eventType.BufferAfter) * time.Minute)
+	for _, b := range bookings {
+		if b.ID != ignoreBookingID && b.StartTime.Before(to) && b.EndTime.After(from) {
+			return true
+		}
+	}
+	return false
+}
+
+// startTimes returns the start times inside the free interval, aligned to the event's increment since midnight (in the host's
+// timezone) so that e.g. a 15 min increment always gives :00, :15, :30 and :45 regardless of when the interval starts.
+// The grid starts over at every midnight, an overnight interval gives the same times after midnight as one starting then
+func startTimes(free interval, eventType *models.EventType, hostLoc *time.Location) []time.Time {
+	duration := time.Duration(eventType.Duration) * time.Minute
+	increment := time.Duration(eventType.SlotIncrement) * time.Minute
+
+	y, m, d := free.start.In(hostLoc).Date()
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, hostLoc)
+	start := midnight.Add((free.start.Sub(midnight) + increment - 1) / increment * increment)
+
+	var starts []time.Time
+	for {
+		if next := time.Date(y, m, d+1, 0, 0, 0, 0, hostLoc); !start.Before(next) {
+			y, m, d = next.Date()
+			start = next
+		}
+		if start.Add(duration).After(free.end) {
+			return starts
+		}
+		starts = append(starts, start)
+		start = start.Add(increment)
+	}
+}
+
+// onIncrement tells if start is on the grid startTimes offers, every SlotIncrement from the host's midnight of the date
+func onIncrement(start time.Time, eventType *models.EventType, hostLoc *time.Location) bool {
+	y, m, d := start.In(hostLoc).Date()
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, hostLoc)
+	return start.Sub(midnight)%(time.Duration(eventType.SlotIncrement)*time.Minute) == 0
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/niharika88/calendly-api/internal/db/models"
+	"github.com/niharika88/calendly-api/internal/mailer"
+	"github.com/niharika88/calendly-api/pkg/icalendar"
+)
+
+// invitationTimeout bounds the delivery of an invitation, it runs after the request is answered
+const invitationTimeout = 30 * time.Second
+
+type InvitationKind string
+
+const (
+	InvitationCreated     InvitationKind = "created"
+	InvitationRescheduled InvitationKind = "rescheduled"
+	InvitationCancelled   InvitationKind = "cancelled"
+)
+
+type InvitationService interface {
+	Send(ctx context.Context, host *models.User, booking *models.Booking, kind InvitationKind)
+}
+
+type invitationService struct {
+	mailer    mailer.Mailer
+	organizer string
+}
+
+// NewInvitationService returns the service emailing the invitations of the bookings, organizer (e.g. the sender of the emails)
+// is the ORGANIZER of the meetings whose host has no email
+func NewInvitationService(mailer mailer.Mailer, organizer string) InvitationService {
+	// only the address of a "Name <address>" is used
+	if address, err := mail.ParseAddress(organizer); err == nil {
+		organizer = address.Address
+	}
+	return &invitationService{
+		mailer:    mailer,
+		organizer: organizer,
+	}
+}
+
+// invitationData is what the booking templates are rendered with
+type invitationData struct {
+	Kind          InvitationKind
+	RecipientName string
+	HostName      string
+	InviteeName   string
+	When          string
+	PreviousWhen  string // set when rescheduled
+	Reason        string // of the reschedule or the cancellation
+	Notes         string
+}
+
+// Send emails the host and the invitee an iTIP REQUEST of the booking (CANCEL once cancelled) so that it lands in their
+// calendars, rescheduling updates the same event. Emails are sent in the background, a failure is only logged
+func (is *invitationService) Send(ctx context.Context, host *models.User, booking *models.Booking, kind InvitationKind) {
+	msgs, err := is.messages(host, booking, kind)
+	if err != nil {
+		slog.ErrorContext(ctx, "unable to render the booking invitation", "booking", booking.ID, "kind", kind, "error", err)
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	for _, msg := range msgs {
+		go func() {
+			ctx, cancel := context.WithTimeout(ctx, invitationTimeout)
+			defer cancel()
+			if err := is.mailer.Send(ctx, msg); err != nil {
+				slog.ErrorContext(ctx, "unable to send the booking invitation", "booking", booking.ID, "kind", kind, "to", msg.To[0].Address, "error", err)
+			}
+		}()
+	}
+}
+
+// messages renders a message for the host (when they have an email) and one for the invitee, times are in the host's timezone
+func (is *invitationService) messages(host *models.User, booking *models.Booking, kind InvitationKind) ([]*mailer.Message, error) {
+	loc := host.Location()
+	method := icalendar.MethodRequest
+	if kind == InvitationCancelled {
+		method = icalendar.MethodCancel
+	}
+	calendar, err := is.calendar(host, booking, method)
+	if err != nil {
+		return nil, err
+	}
+
+	data := invitationData{
+		Kind:        kind,
+		HostName:    fullName(host),
+		InviteeName: booking.InviteeName,
+		When:        meetingTime(booking.StartTime, booking.EndTime, loc),
+		Notes:       booking.Notes,
+	}
+	if kind != InvitationCreated && len(booking.Changes) > 0 {
+		change := booking.Changes[len(booking.Changes)-1]
+		data.Reason = change.Reason
+		if kind == InvitationRescheduled {
+			data.PreviousWhen = meetingTime(change.PreviousStartTime, change.PreviousEndTime, loc)
+		}
+	}
+	subject := fmt.Sprintf("%s and %s @ %s", data.HostName, data.InviteeName, data.When)
+	switch kind {
+	case InvitationRescheduled:
+		subject = "Updated invitation: " + subject
+	case InvitationCancelled:
+		subject = "Cancelled: " + subject
+	default:
+		subject = "Invitation: " + subject
+	}
+
+	recipients := []mail.Address{{Name: booking.InviteeName, Address: booking.InviteeEmail}}
+	if host.Email != "" {
+		recipients = append(recipients, mail.Address{Name: data.HostName, Address: host.Email})
+	}
+	msgs := make([]*mailer.Message, 0, len(recipients))
+	for _, recipient := range recipients {
+		data.RecipientName = recipient.Name
+		text, html, err := mailer.Render("booking", data)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, &mailer.Message{
+			To:       []mail.Address{recipient},
+			Subject:  subject,
+			Text:     text,
+			HTML:     html,
+			Calendar: calendar,
+			Method:   method,
+		})
+	}
+	return msgs, nil
+}
+
+// calendar renders the iTIP object of the booking, the host organizes the meeting the invitee has accepted by booking it
+func (is *invitationService) calendar(host *models.User, booking *models.Booking, method string) ([]byte, error) {
+	loc := host.Location()
+	cal := icalendar.NewCalendar()
+	cal.Props.SetText(ical.PropMethod, method)
+	icalendar.AddTimezone(cal, loc, booking.StartTime, booking.EndTime)
+
+	event := bookingEvent(booking, loc)
+	event.Props.SetText(ical.PropSummary, fmt.Sprintf("%s and %s", fullName(host), booking.InviteeName))
+	organizer := host.Email
+	if organizer == "" {
+		organizer = is.organizer
+	}
+	icalendar.SetOrganizer(event.Props, organizer, fullName(host))
+	icalendar.AddAttendee(event.Props, booking.InviteeEmail, booking.InviteeName)
+	cal.Children = append(cal.Children, event.Component)
+	return icalendar.Encode(cal)
+}
+
+// meetingTime formats the meeting time for people, e.g. "Monday, 16 December 2024 10:00 - 10:30 (Europe/Berlin)"
+func meetingTime(start, end time.Time, loc *time.Location) string {
+	start, end = start.In(loc), end.In(loc)
+	endFormat := "15:04"
+	if calendarDate(start) != calendarDate(end) {
+		endFormat = "Monday, 2 January 2006 15:04"
+	}
+	return fmt.Sprintf("%s - %s (%s)", start.Format("Monday, 2 January 2006 15:04"), end.Format(endFormat), loc)
+}
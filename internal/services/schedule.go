@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/niharika88/calendly-api/internal/db/models"
+	"github.com/niharika88/calendly-api/internal/db/repo"
+	"github.com/niharika88/calendly-api/pkg/api"
+)
+
+// defaultScheduleName is the name of the schedule created for users adding availability without having any schedule
+const defaultScheduleName = "Working hours"
+
+type ScheduleService interface {
+	Create(ctx context.Context, userID uuid.UUID, req *api.CreateScheduleRequest) (*models.Schedule, error)
+	GetAll(ctx context.Context, userID uuid.UUID) ([]*models.Schedule, error)
+	GetByID(ctx context.Context, userID, id uuid.UUID, today time.Time) (*models.Schedule, error)
+	Update(ctx context.Context, userID, id uuid.UUID, req *api.UpdateScheduleRequest) (*models.Schedule, error)
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+}
+
+type scheduleService struct {
+	scheduleRepo repo.ScheduleRepo
+}
+
+func NewScheduleService(scheduleRepo repo.ScheduleRepo) ScheduleService {
+	return &scheduleService{
+		scheduleRepo: scheduleRepo,
+	}
+}
+
+// Create adds a schedule to the user, the user's first schedule is always made the default one
+func (ss *scheduleService) Create(ctx context.Context, userID uuid.UUID, req *api.CreateScheduleRequest) (*models.Schedule, error) {
+	schedule := &models.Schedule{
+		UserID:    userID,
+		Name:      req.Name,
+		IsDefault: req.IsDefault,
+	}
+	if !schedule.IsDefault {
+		_, err := ss.scheduleRepo.FindDefault(ctx, userID)
+		if errors.Is(err, sql.ErrNoRows) {
+			schedule.IsDefault = true
+		} else if err != nil {
+			return nil, api.ServerErr(err)
+		}
+	}
+	if err := ss.scheduleRepo.Insert(ctx, schedule); err != nil {
+		return nil, api.ServerErr(err)
+	}
+	return schedule, nil
+}
+
+func (ss *scheduleService) GetAll(ctx context.Context, userID uuid.UUID) ([]*models.Schedule, error) {
+	schedules, err := ss.scheduleRepo.GetAllByUser(ctx, userID)
+	if err != nil {
+		return nil, api.ServerErr(err)
+	}
+	return schedules, nil
+}
+
+// GetByID returns the schedule along with its day availabilities (from the weekly hours in effect today, the user's date),
+// date overrides and recurring rules
+func (ss *scheduleService) GetByID(ctx context.Context, userID, id uuid.UUID, today time.Time) (*models.Schedule, error) {
+	return ss.find(ctx, userID, id, true, today.Format("2006-01-02"))
+}
+
+func (ss *scheduleService) Update(ctx context.Context, userID, id uuid.UUID, req *api.UpdateScheduleRequest) (*models.Schedule, error) {
+	schedule, err := ss.find(ctx, userID, id, false, "")
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		schedule.Name = *req.Name
+	}
+	if req.IsDefault != nil {
+		schedule.IsDefault = *req.IsDefault
+	}
+	if err := ss.scheduleRepo.Update(ctx, schedule); err != nil {
+		return nil, api.ServerErr(err)
+	}
+	return schedule, nil
+}
+
+// Delete removes the schedule along with its availability, event types using it fall back to the default schedule.
+// The default schedule itself can't be deleted
+func (ss *scheduleService) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	schedule, err := ss.find(ctx, userID, id, false, "")
+	if err != nil {
+		return err
+	}
+	if schedule.IsDefault {
+		return api.BadRequestErr("the default schedule can't be deleted, make another schedule the default first", nil)
+	}
+	if err := ss.scheduleRepo.Delete(ctx, schedule.ID); err != nil {
+		return api.ServerErr(err)
+	}
+	return nil
+}
+
+func (ss *scheduleService) find(ctx context.Context, userID, id uuid.UUID, association bool, fromDate string) (*models.Schedule, error) {
+	schedule, err := ss.scheduleRepo.FindByID(ctx, userID, id, association, fromDate)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, api.NotFoundErr(api.ErrScheduleNotFound, err)
+		}
+		return nil, api.ServerErr(err)
+	}
+	return schedule, nil
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/niharika88/calendly-api/internal/db/models"
+	"github.com/niharika88/calendly-api/internal/db/repo"
+	"github.com/niharika88/calendly-api/pkg/api"
+)
+
+type TimeOffService interface {
+	Create(ctx context.Context, userID uuid.UUID, req *api.CreateTimeOffRequest) (*models.TimeOff, error)
+	GetAll(ctx context.Context, userID uuid.UUID) ([]*models.TimeOff, error)
+	GetByID(ctx context.Context, userID, id uuid.UUID) (*models.TimeOff, error)
+	Update(ctx context.Context, userID, id uuid.UUID, req *api.UpdateTimeOffRequest) (*models.TimeOff, error)
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+}
+
+type timeOffService struct {
+	timeOffRepo repo.TimeOffRepo
+}
+
+func NewTimeOffService(timeOffRepo repo.TimeOffRepo) TimeOffService {
+	return &timeOffService{
+		timeOffRepo: timeOffRepo,
+	}
+}
+
+func (ts *timeOffService) Create(ctx context.Context, userID uuid.UUID, req *api.CreateTimeOffRequest) (*models.TimeOff, error) {
+	timeOff := &models.TimeOff{
+		UserID:      userID,
+		StartDate:   req.StartDate, // calendar dates in the user's timezone
+		EndDate:     req.EndDate,
+		StartMinute: *req.StartMinute,
+		EndMinute:   *req.EndMinute,
+		Reason:      req.Reason,
+	}
+	if err := ts.timeOffRepo.Insert(ctx, timeOff); err != nil {
+		return nil, api.ServerErr(err)
+	}
+	return timeOff, nil
+}
+
+func (ts *timeOffService) GetAll(ctx context.Context, userID uuid.UUID) ([]*models.TimeOff, error) {
+	timeOffs, err := ts.timeOffRepo.GetAllByUser(ctx, userID, "", "")
+	if err != nil {
+		return nil, api.ServerErr(err)
+	}
+	return timeOffs, nil
+}
+
+func (ts *timeOffService) GetByID(ctx context.Context, userID, id uuid.UUID) (*models.TimeOff, error) {
+	return ts.find(ctx, userID, id)
+}
+
+// Update changes only the provided fields, the resulting range has to be valid as a whole
+func (ts *timeOffService) Update(ctx context.Context, userID, id uuid.UUID, req *api.UpdateTimeOffRequest) (*models.TimeOff, error) {
+	timeOff, err := ts.find(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.StartDate != nil {
+		timeOff.StartDate = *req.StartDate
+	}
+	if req.EndDate != nil {
+		timeOff.EndDate = *req.EndDate
+	}
+	if req.StartMinute != nil {
+		timeOff.StartMinute = *req.StartMinute
+	}
+	if req.EndMinute != nil {
+		timeOff.EndMinute = *req.EndMinute
+	}
+	if req.Reason != nil {
+		timeOff.Reason = *req.Reason
+	}
+	if err := api.ValidateTimeOffRange(timeOff.StartDate, timeOff.EndDate, timeOff.StartMinute, timeOff.EndMinute); err != nil {
+		return nil, err
+	}
+	if err := ts.timeOffRepo.Update(ctx, timeOff); err != nil {
+		return nil, api.ServerErr(err)
+	}
+	return timeOff, nil
+}
+
+func (ts *timeOffService) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	timeOff, err := ts.find(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if err := ts.timeOffRepo.Delete(ctx, timeOff.ID); err != nil {
+		return api.ServerErr(err)
+	}
+	return nil
+}
+
+func (ts *timeOffService) find(ctx context.Context, userID, id uuid.UUID) (*models.TimeOff, error) {
+	timeOff, err := ts.timeOffRepo.FindByID(ctx, userID, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, api.NotFoundErr(api.ErrTimeOffNotFound, err)
+		}
+		return nil, api.ServerErr(err)
+	}
+	return timeOff, nil
+}
@@ -69,6 +69,15 @@ func (s *userService) Update(ctx context.Context, id uuid.UUID, req api.UpdateUs
 	if req.Timezone != nil {
 		user.Timezone = *req.Timezone
 	}
+	if req.HolidayRegion != nil {
+		user.HolidayRegion = *req.HolidayRegion
+	}
+	if req.DailyBookingCap != nil {
+		user.DailyBookingCap = *req.DailyBookingCap
+	}
+	if req.WeeklyBookingCap != nil {
+		user.WeeklyBookingCap = *req.WeeklyBookingCap
+	}
 	if err := s.userRepo.Update(ctx, user); err != nil {
 		return nil, err
 	}
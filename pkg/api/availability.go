@@ -2,20 +2,54 @@ package api
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/niharika88/calendly-api/internal/db/models"
+	"github.com/teambition/rrule-go"
+)
+
+const (
+	// MaxOverlapUsers is the max number of users a schedule overlap can be found for
+	MaxOverlapUsers = 10
+	// MaxNextCommonCount is the max number of windows /availability/next-common returns
+	MaxNextCommonCount = 20
+	// MaxDateRangeDays is the max number of dates a date availability range operation can span
+	MaxDateRangeDays = 366
+	// MaxCopyWeeks is the max number of weeks a week of availability can be copied onto
+	MaxCopyWeeks = 52
+)
+
+// CopyConflictPolicy tells what copying availability does with dates that already have date availabilities
+type CopyConflictPolicy string
+
+const (
+	CopyConflictSkip      CopyConflictPolicy = "skip"      // the date is left as it is
+	CopyConflictOverwrite CopyConflictPolicy = "overwrite" // its date availabilities (of every mode) are replaced
 )
 
 type CreateDayAvailabilityRequest struct {
-	Username     string                `json:"username" validate:"required"`
-	Availability []UserDayAvailability `json:"availability" validate:"required"`
+	Username      string                `json:"username" validate:"required"`
+	ScheduleID    *uuid.UUID            `json:"schedule_id"`                                   // defaults to the user's default schedule
+	EffectiveFrom *time.Time            `json:"effective_from" example:"2025-01-01T00:00:00Z"` // the weekly hours apply from this date on, today by default
+	Availability  []UserDayAvailability `json:"availability" validate:"required"`
 } // @name CreateDayAvailabilityRequest
 
+// UpsertDayAvailabilityRequest sets the slots of a single weekday, username and day are path params
+type UpsertDayAvailabilityRequest struct {
+	ScheduleID    *uuid.UUID    `json:"schedule_id"`                                   // defaults to the user's default schedule
+	EffectiveFrom *time.Time    `json:"effective_from" example:"2025-01-01T00:00:00Z"` // the slots apply from this date on, today by default
+	Slots         []models.Slot `json:"slots" validate:"required"`
+} // @name UpsertDayAvailabilityRequest
+
 type CreateDateAvailabilityRequest struct {
-	Username string        `json:"username" validate:"required"`
-	Date     time.Time     `json:"date" example:"2024-12-15T00:00:00Z" validate:"required"`
-	Slots    []models.Slot `json:"slots" validate:"required"`
+	Username   string              `json:"username" validate:"required"`
+	ScheduleID *uuid.UUID          `json:"schedule_id"` // defaults to the user's default schedule
+	Date       time.Time           `json:"date" example:"2024-12-15T00:00:00Z" validate:"required"`
+	Mode       models.OverrideMode `json:"mode" example:"block"` // replace (default), add or block
+	Slots      []models.Slot       `json:"slots" validate:"required"`
 } // @name CreateDateAvailabilityRequest
 
 func (r *CreateDayAvailabilityRequest) Validate() error {
@@ -33,23 +67,253 @@ func (r *CreateDayAvailabilityRequest) Validate() error {
 	return nil
 }
 
+func (r *UpsertDayAvailabilityRequest) Validate() error {
+	return validateSlots(r.Slots)
+}
+
 func (r *CreateDateAvailabilityRequest) Validate() error {
 	if r.Username == "" {
 		return BadRequestErr(ErrInvalidUsername, nil)
 	}
-	if r.Date.IsZero() || r.Date.Before(time.Now().UTC().Truncate(24*time.Hour)) {
-		return BadRequestErr("invalid date, should be today or in the future", nil)
+	if r.Date.IsZero() {
+		return BadRequestErr("invalid date", nil)
 	}
-	r.Date = r.Date.UTC().Truncate(24 * time.Hour) // input DATE is always assumed to be in UTC
+	if r.Mode == "" {
+		r.Mode = models.OverrideModeReplace
+	}
+	if !r.Mode.IsValid() {
+		return BadRequestErr("invalid mode, should be replace, add or block", nil)
+	}
+	// input DATE is a calendar date in the user's timezone, keep it as written
+	r.Date = time.Date(r.Date.Year(), r.Date.Month(), r.Date.Day(), 0, 0, 0, 0, time.UTC)
 	if err := validateSlots(r.Slots); err != nil {
 		return err
 	}
 	return nil
 }
 
+// EffectiveDate returns the date (from which weekly hours apply or stop to apply) as a calendar date, today in the user's timezone when nil.
+// It can't be in the past since the weekly hours that applied already are kept as history
+func EffectiveDate(date *time.Time, loc *time.Location) (time.Time, error) {
+	y, m, d := time.Now().In(loc).Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	if date == nil {
+		return today, nil
+	}
+	effective := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	if effective.Before(today) {
+		return time.Time{}, BadRequestErr("invalid effective date, should be today or in the future", nil)
+	}
+	return effective, nil
+}
+
+// ValidateDate checks that the date is today or in the future, in the user's timezone
+func (r *CreateDateAvailabilityRequest) ValidateDate(loc *time.Location) error {
+	y, m, d := time.Now().In(loc).Date()
+	if r.Date.Before(time.Date(y, m, d, 0, 0, 0, 0, time.UTC)) {
+		return BadRequestErr("invalid date, should be today or in the future", nil)
+	}
+	return nil
+}
+
+// CreateDateRangeAvailabilityRequest sets the same date availability on every date from StartDate to EndDate (both inclusive),
+// only on the given days of the week when Days is not empty
+type CreateDateRangeAvailabilityRequest struct {
+	Username   string              `json:"username" validate:"required"`
+	ScheduleID *uuid.UUID          `json:"schedule_id"` // defaults to the user's default schedule
+	StartDate  time.Time           `json:"start_date" example:"2024-12-23T00:00:00Z" validate:"required"`
+	EndDate    time.Time           `json:"end_date" example:"2024-12-31T00:00:00Z" validate:"required"`
+	Days       []models.Day        `json:"days" example:"monday,tuesday"` // all days by default
+	Mode       models.OverrideMode `json:"mode" example:"replace"`        // replace (default), add or block
+	Slots      []models.Slot       `json:"slots" validate:"required"`
+} // @name CreateDateRangeAvailabilityRequest
+
+func (r *CreateDateRangeAvailabilityRequest) Validate() error {
+	if r.Username == "" {
+		return BadRequestErr(ErrInvalidUsername, nil)
+	}
+	if r.StartDate.IsZero() || r.EndDate.IsZero() {
+		return BadRequestErr("invalid start or end date", nil)
+	}
+	var err error
+	if r.StartDate, r.EndDate, err = ValidateDateRange(r.StartDate, r.EndDate); err != nil {
+		return err
+	}
+	for _, day := range r.Days {
+		if !day.IsValid() {
+			return BadRequestErr("invalid day", nil)
+		}
+	}
+	if r.Mode == "" {
+		r.Mode = models.OverrideModeReplace
+	}
+	if !r.Mode.IsValid() {
+		return BadRequestErr("invalid mode, should be replace, add or block", nil)
+	}
+	return validateSlots(r.Slots)
+}
+
+// ValidateDate checks that the range starts today or in the future, in the user's timezone
+func (r *CreateDateRangeAvailabilityRequest) ValidateDate(loc *time.Location) error {
+	y, m, d := time.Now().In(loc).Date()
+	if r.StartDate.Before(time.Date(y, m, d, 0, 0, 0, 0, time.UTC)) {
+		return BadRequestErr("invalid start date, should be today or in the future", nil)
+	}
+	return nil
+}
+
+// Dates returns the dates of the range on the requested days of the week
+func (r *CreateDateRangeAvailabilityRequest) Dates() []time.Time {
+	var dates []time.Time
+	for date := r.StartDate; !date.After(r.EndDate); date = date.AddDate(0, 0, 1) {
+		if len(r.Days) == 0 || slices.Contains(r.Days, models.Day(strings.ToLower(date.Weekday().String()))) {
+			dates = append(dates, date)
+		}
+	}
+	return dates
+}
+
+type DeleteDateRangeAvailabilityRequest struct {
+	Username   string              `json:"username" validate:"required"`
+	ScheduleID *uuid.UUID          `json:"schedule_id"` // defaults to the user's default schedule
+	StartDate  time.Time           `json:"start_date" example:"2024-12-23T00:00:00Z" validate:"required"`
+	EndDate    time.Time           `json:"end_date" example:"2024-12-31T00:00:00Z" validate:"required"`
+	Mode       models.OverrideMode `json:"mode"` // only date availabilities of this mode are deleted, all modes by default
+} // @name DeleteDateRangeAvailabilityRequest
+
+func (r *DeleteDateRangeAvailabilityRequest) Validate() error {
+	if r.Username == "" {
+		return BadRequestErr(ErrInvalidUsername, nil)
+	}
+	if r.StartDate.IsZero() || r.EndDate.IsZero() {
+		return BadRequestErr("invalid start or end date", nil)
+	}
+	var err error
+	if r.StartDate, r.EndDate, err = ValidateDateRange(r.StartDate, r.EndDate); err != nil {
+		return err
+	}
+	if r.Mode != "" && !r.Mode.IsValid() {
+		return BadRequestErr("invalid mode, should be replace, add or block", nil)
+	}
+	return nil
+}
+
+// ValidateDateRange keeps the dates as calendar dates and checks the range spans at most MaxDateRangeDays
+func ValidateDateRange(startDate, endDate time.Time) (time.Time, time.Time, error) {
+	startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, time.UTC)
+	endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 0, 0, 0, 0, time.UTC)
+	if startDate.After(endDate) {
+		return startDate, endDate, BadRequestErr("start date must be before end date", nil)
+	}
+	if endDate.Sub(startDate) >= MaxDateRangeDays*24*time.Hour {
+		return startDate, endDate, BadRequestErr(fmt.Sprintf("invalid date range, should span at most %d days", MaxDateRangeDays), nil)
+	}
+	return startDate, endDate, nil
+}
+
+// CopyAvailabilityRequest copies the availability of the week (monday to sunday) of SourceWeek onto the next Weeks weeks, username is a path param
+type CopyAvailabilityRequest struct {
+	ScheduleID *uuid.UUID         `json:"schedule_id"`                                                    // defaults to the user's default schedule
+	SourceWeek time.Time          `json:"source_week" example:"2024-12-16T00:00:00Z" validate:"required"` // any date of the week
+	Weeks      int                `json:"weeks" example:"4" validate:"required"`                          // number of weeks after the source week
+	Conflict   CopyConflictPolicy `json:"conflict" example:"skip"`                                        // skip (default) or overwrite
+} // @name CopyAvailabilityRequest
+
+func (r *CopyAvailabilityRequest) Validate() error {
+	if r.SourceWeek.IsZero() {
+		return BadRequestErr("invalid source week", nil)
+	}
+	// the week is made of calendar dates in the user's timezone, keep the date as written
+	r.SourceWeek = time.Date(r.SourceWeek.Year(), r.SourceWeek.Month(), r.SourceWeek.Day(), 0, 0, 0, 0, time.UTC)
+	if r.Weeks < 1 || r.Weeks > MaxCopyWeeks {
+		return BadRequestErr(fmt.Sprintf("invalid weeks, should be in range 1-%d", MaxCopyWeeks), nil)
+	}
+	if r.Conflict == "" {
+		r.Conflict = CopyConflictSkip
+	}
+	if r.Conflict != CopyConflictSkip && r.Conflict != CopyConflictOverwrite {
+		return BadRequestErr("invalid conflict policy, should be skip or overwrite", nil)
+	}
+	return nil
+}
+
+// CopyAvailabilityResponse lists the date availabilities created by the copy and the target dates that were left as they are
+type CopyAvailabilityResponse struct {
+	Copied  []*models.DateAvailability `json:"copied"`
+	Skipped []time.Time                `json:"skipped" example:"2024-12-24T00:00:00Z"` // dates with date availabilities already (skip policy), in the past or public holidays (target or source date)
+} // @name CopyAvailabilityResponse
+
+type CreateRuleAvailabilityRequest struct {
+	Username   string        `json:"username" validate:"required"`
+	ScheduleID *uuid.UUID    `json:"schedule_id"` // defaults to the user's default schedule
+	RRule      string        `json:"rrule" example:"FREQ=WEEKLY;INTERVAL=2;BYDAY=FR" validate:"required"`
+	StartDate  time.Time     `json:"start_date" example:"2024-12-20T00:00:00Z" validate:"required"` // DTSTART
+	ExDates    []time.Time   `json:"exdates" example:"2025-01-17T00:00:00Z"`                        // EXDATEs
+	Slots      []models.Slot `json:"slots" validate:"required"`
+} // @name CreateRuleAvailabilityRequest
+
+func (r *CreateRuleAvailabilityRequest) Validate() error {
+	if r.Username == "" {
+		return BadRequestErr(ErrInvalidUsername, nil)
+	}
+	if r.StartDate.IsZero() {
+		return BadRequestErr("invalid start date", nil)
+	}
+	// dates are calendar dates in the user's timezone, keep them as written
+	r.StartDate = time.Date(r.StartDate.Year(), r.StartDate.Month(), r.StartDate.Day(), 0, 0, 0, 0, time.UTC)
+	for i, exDate := range r.ExDates {
+		r.ExDates[i] = time.Date(exDate.Year(), exDate.Month(), exDate.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	if r.ExDates == nil {
+		r.ExDates = []time.Time{}
+	}
+	if _, err := ParseRRule(r.RRule, r.StartDate); err != nil {
+		return err
+	}
+	return validateSlots(r.Slots)
+}
+
+type DeleteRuleAvailabilityRequest struct {
+	Username string    `json:"username" validate:"required"`
+	ID       uuid.UUID `json:"id" validate:"required"` // ID of the rule availability
+} // @name DeleteRuleAvailabilityRequest
+
+func (r *DeleteRuleAvailabilityRequest) Validate() error {
+	if r.Username == "" {
+		return BadRequestErr(ErrInvalidUsername, nil)
+	}
+	if r.ID == uuid.Nil {
+		return BadRequestErr(ErrParsingUUID, nil)
+	}
+	return nil
+}
+
+// ParseRRule parses an iCalendar RRULE (e.g. "FREQ=MONTHLY;BYDAY=1MO", the "RRULE:" prefix is optional) producing dates
+// from startDate on. Dates are midnight UTC like everywhere else, so only rules repeating daily or less often are allowed
+func ParseRRule(rule string, startDate time.Time) (*rrule.RRule, error) {
+	opt, err := rrule.StrToROption(strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:"))
+	if err != nil {
+		return nil, BadRequestErr(fmt.Sprintf("invalid rrule, %s", err), nil)
+	}
+	if !opt.Dtstart.IsZero() {
+		return nil, BadRequestErr("invalid rrule, DTSTART should be given as start_date", nil)
+	}
+	if opt.Freq > rrule.DAILY || len(opt.Byhour) > 0 || len(opt.Byminute) > 0 || len(opt.Bysecond) > 0 {
+		return nil, BadRequestErr("invalid rrule, should repeat on dates (FREQ of DAILY or less often, no BYHOUR/BYMINUTE/BYSECOND)", nil)
+	}
+	opt.Dtstart = startDate
+	r, err := rrule.NewRRule(*opt)
+	if err != nil {
+		return nil, BadRequestErr(fmt.Sprintf("invalid rrule, %s", err), nil)
+	}
+	return r, nil
+}
+
 type DeleteUserAvailabilityRequest struct {
-	Username string     `json:"username" validate:"required"`
-	Date     *time.Time `json:"date" example:"2024-12-15T00:00:00Z"`
+	Username   string              `json:"username" validate:"required"`
+	ScheduleID *uuid.UUID          `json:"schedule_id"`                         // defaults to the user's default schedule
+	Date       *time.Time          `json:"date" example:"2024-12-15T00:00:00Z"` // for day availability the date the weekly hours stop to apply, today by default
+	Mode       models.OverrideMode `json:"mode"`                                // only date availabilities of this mode are deleted, all modes by default
 } // @name DeleteUserAvailabilityRequest
 
 func (r *DeleteUserAvailabilityRequest) Validate() error {
@@ -59,21 +323,74 @@ func (r *DeleteUserAvailabilityRequest) Validate() error {
 	if r.Date != nil && r.Date.IsZero() {
 		return BadRequestErr("invalid date", nil)
 	}
+	if r.Mode != "" && !r.Mode.IsValid() {
+		return BadRequestErr("invalid mode, should be replace, add or block", nil)
+	}
 	return nil
 }
 
+// WeeklyHoursVersion is a version of a schedule's weekly hours, it applies from EffectiveFrom until EffectiveTo (excluded, nil when open ended)
+type WeeklyHoursVersion struct {
+	EffectiveFrom time.Time             `json:"effective_from" example:"2025-01-01T00:00:00Z"`
+	EffectiveTo   *time.Time            `json:"effective_to" example:"2025-02-01T00:00:00Z"`
+	Availability  []UserDayAvailability `json:"availability"`
+} // @name WeeklyHoursVersion
+
+// DayAvailabilityResponse is the stored day availability, warnings describe how the given slots were normalized
+type DayAvailabilityResponse struct {
+	Availability []*models.DayAvailability `json:"availability"`
+	Warnings     []string                  `json:"warnings,omitempty"`
+} // @name DayAvailabilityResponse
+
+type DateAvailabilityResponse struct {
+	models.DateAvailability
+	Warnings []string `json:"warnings,omitempty"`
+} // @name DateAvailabilityResponse
+
+type DateRangeAvailabilityResponse struct {
+	Availability []*models.DateAvailability `json:"availability"`
+	Warnings     []string                   `json:"warnings,omitempty"`
+} // @name DateRangeAvailabilityResponse
+
+type RuleAvailabilityResponse struct {
+	models.RuleAvailability
+	Warnings []string `json:"warnings,omitempty"`
+} // @name RuleAvailabilityResponse
+
 type UserDateAvailability struct {
 	Availability map[string][]models.Slot `json:"availability" validate:"required"`
 } // @name UserDateAvailability
 
+// OverlapSlot is a window of the schedule overlap along with the users that are free during it
+type OverlapSlot struct {
+	models.Slot
+	Users []string `json:"users"` // usernames
+} // @name OverlapSlot
+
+type ScheduleOverlap struct {
+	Availability map[string][]OverlapSlot `json:"availability" validate:"required"`
+} // @name ScheduleOverlap
+
+// TimeWindow is an absolute range of time
+type TimeWindow struct {
+	Start time.Time `json:"start" example:"2024-12-15T10:00:00Z"`
+	End   time.Time `json:"end" example:"2024-12-15T11:00:00Z"`
+} // @name TimeWindow
+
+type NextCommonSlots struct {
+	Duration int          `json:"duration" example:"30"` // minutes
+	Windows  []TimeWindow `json:"windows"`               // earliest windows where everyone is free for at least duration
+} // @name NextCommonSlots
+
 type UserDayAvailability struct {
 	Day   models.Day    `json:"day" validate:"required"`
 	Slots []models.Slot `json:"slots" validate:"required"`
 } // @name UserDayAvailability
 
+// isValid checks the slot is within a day, an end past 1440 continues on the next day (overnight slot, e.g. 1320-1560 is 22:00-02:00)
 func isValid(s models.Slot) error {
-	if s.Start < 0 || s.End <= 0 || s.Start >= 1440 || s.End > 1440 || s.Start >= s.End {
-		return BadRequestErr(fmt.Sprintf("invalid slot: start (%d) and end (%d) must be in range 0-1440 and start < end", s.Start, s.End), nil)
+	if s.Start < 0 || s.Start >= 1440 || s.Start >= s.End || s.End-s.Start > 1440 {
+		return BadRequestErr(fmt.Sprintf("invalid slot: start (%d) must be in range 0-1439 and end (%d) after start, at most 1440 minutes later", s.Start, s.End), nil)
 	}
 	return nil
 }
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"time"
+)
+
+type CreateBookingRequest struct {
+	Username     string    `json:"username" validate:"required"` // host of the booking
+	InviteeName  string    `json:"invitee_name" validate:"required"`
+	InviteeEmail string    `json:"invitee_email" validate:"required,email"`
+	EventType    string    `json:"event_type" example:"intro-30"` // slug of the host's event type, its rules apply and end time is derived from its duration
+	StartTime    time.Time `json:"start_time" example:"2024-12-15T10:00:00Z" validate:"required"`
+	EndTime      time.Time `json:"end_time" example:"2024-12-15T10:30:00Z"` // required without an event type
+	Notes        string    `json:"notes"`
+} // @name CreateBookingRequest
+
+func (r *CreateBookingRequest) Validate() error {
+	if r.Username == "" {
+		return BadRequestErr(ErrInvalidUsername, nil)
+	}
+	if r.StartTime.IsZero() {
+		return BadRequestErr("invalid booking time", nil)
+	}
+	if r.EventType != "" {
+		if err := validateBookingTime(r.StartTime); err != nil {
+			return err
+		}
+		r.StartTime = r.StartTime.UTC()
+		return nil
+	}
+	if r.EndTime.IsZero() || !r.StartTime.Before(r.EndTime) {
+		return BadRequestErr("invalid booking time, start time must be before end time", nil)
+	}
+	if err := validateBookingTime(r.StartTime, r.EndTime); err != nil {
+		return err
+	}
+	r.StartTime = r.StartTime.UTC()
+	r.EndTime = r.EndTime.UTC()
+	return nil
+}
+
+type CancelBookingRequest struct {
+	Reason string `json:"reason" example:"something came up"`
+} // @name CancelBookingRequest
+
+type RescheduleBookingRequest struct {
+	StartTime time.Time `json:"start_time" example:"2024-12-16T10:00:00Z" validate:"required"` // booking keeps its duration
+	Reason    string    `json:"reason" example:"conflicting meeting"`
+} // @name RescheduleBookingRequest
+
+func (r *RescheduleBookingRequest) Validate() error {
+	if r.StartTime.IsZero() {
+		return BadRequestErr("invalid booking time", nil)
+	}
+	if err := validateBookingTime(r.StartTime); err != nil {
+		return err
+	}
+	r.StartTime = r.StartTime.UTC()
+	return nil
+}
+
+func validateBookingTime(times ...time.Time) error {
+	for _, t := range times {
+		// availability is stored in minutes, so bookings can't be more precise than that
+		if !t.Equal(t.Truncate(time.Minute)) {
+			return BadRequestErr("invalid booking time, seconds are not supported", nil)
+		}
+		if t.Before(time.Now().UTC()) {
+			return BadRequestErr("invalid booking time, should be in the future", nil)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package api
+
+const (
+	// MaxCalendarFileSize is the largest .ics file that can be imported (or iCalendar object sent)
+	MaxCalendarFileSize = 5 << 20
+	// MaxFreeBusyAttendees is the max number of attendees of a free/busy request
+	MaxFreeBusyAttendees = 20
+)
+
+// BusyImportResponse tells how many events (UIDs) of the file were imported and how many busy blocks their occurrences make,
+// events that couldn't be interpreted are listed in the warnings
+type BusyImportResponse struct {
+	Events   int      `json:"events" example:"12"`
+	Blocks   int      `json:"blocks" example:"87"`
+	Warnings []string `json:"warnings,omitempty"`
+} // @name BusyImportResponse
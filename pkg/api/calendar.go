@@ -0,0 +1,31 @@
+package api
+
+import "strings"
+
+// CreateCalendarConnectionRequest connects an external calendar of the provider, username is a path param
+type CreateCalendarConnectionRequest struct {
+	Provider string `json:"provider" example:"fake" validate:"required"`
+	Name     string `json:"name" example:"Work calendar"`
+	Location string `json:"location" example:"work.json" validate:"required"` // provider specific, a file name or an http(s) url under FAKE_CALENDAR_BASE_URL for the fake provider
+} // @name CreateCalendarConnectionRequest
+
+func (r *CreateCalendarConnectionRequest) Validate() error {
+	r.Location = strings.TrimSpace(r.Location)
+	if r.Location == "" {
+		return BadRequestErr("location is required", nil)
+	}
+	if r.Name == "" {
+		r.Name = r.Provider
+	}
+	return nil
+}
+
+// CalendarSyncResponse tells what a sync of a connected calendar changed: the events changed and deleted since the last sync
+// (every event for a Full sync) and the busy blocks of the changed ones
+type CalendarSyncResponse struct {
+	Full     bool     `json:"full"`
+	Changed  int      `json:"changed" example:"3"`
+	Deleted  int      `json:"deleted" example:"1"`
+	Blocks   int      `json:"blocks" example:"14"`
+	Warnings []string `json:"warnings,omitempty"`
+} // @name CalendarSyncResponse
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultSlotIncrement is used when an event type doesn't specify how far apart start times are
+const DefaultSlotIncrement = 15
+
+var slugRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+type CreateEventTypeRequest struct {
+	Name          string     `json:"name" example:"30-min intro" validate:"required"`
+	Slug          string     `json:"slug" example:"intro-30" validate:"required"`
+	Description   string     `json:"description"`
+	Duration      int        `json:"duration" example:"30" validate:"required"` // minutes
+	SlotIncrement int        `json:"slot_increment" example:"15"`               // minutes, defaults to 15
+	ScheduleID    *uuid.UUID `json:"schedule_id"`                               // schedule the slots come from, defaults to the user's default schedule
+
+	MinNotice      int `json:"min_notice" example:"240"`     // minutes, nothing can be booked sooner than this from now
+	BookingHorizon int `json:"booking_horizon" example:"60"` // days, nothing can be booked further than this from now, 0 means no limit
+	BufferBefore   int `json:"buffer_before" example:"15"`   // minutes that have to be free before the meeting
+	BufferAfter    int `json:"buffer_after" example:"15"`    // minutes that have to be free after the meeting
+	DailyCap       int `json:"daily_cap" example:"4"`        // max bookings of this event type per day, 0 means no cap
+	WeeklyCap      int `json:"weekly_cap" example:"12"`      // max bookings of this event type per week, 0 means no cap
+} // @name CreateEventTypeRequest
+
+func (r *CreateEventTypeRequest) Validate() error {
+	if err := validateSlug(r.Slug); err != nil {
+		return err
+	}
+	if r.SlotIncrement == 0 {
+		r.SlotIncrement = DefaultSlotIncrement
+	}
+	if err := validateEventDuration(r.Duration, r.SlotIncrement); err != nil {
+		return err
+	}
+	if err := validateEventRules(&r.MinNotice, &r.BookingHorizon, &r.BufferBefore, &r.BufferAfter); err != nil {
+		return err
+	}
+	return ValidateBookingCaps(&r.DailyCap, &r.WeeklyCap)
+}
+
+type UpdateEventTypeRequest struct {
+	Name          *string    `json:"name"`
+	Slug          *string    `json:"slug"`
+	Description   *string    `json:"description"`
+	Duration      *int       `json:"duration"`
+	SlotIncrement *int       `json:"slot_increment"`
+	ScheduleID    *uuid.UUID `json:"schedule_id"` // nil UUID switches back to the user's default schedule
+
+	MinNotice      *int `json:"min_notice"`
+	BookingHorizon *int `json:"booking_horizon"`
+	BufferBefore   *int `json:"buffer_before"`
+	BufferAfter    *int `json:"buffer_after"`
+	DailyCap       *int `json:"daily_cap"`
+	WeeklyCap      *int `json:"weekly_cap"`
+} // @name UpdateEventTypeRequest
+
+func (r *UpdateEventTypeRequest) Validate() error {
+	if r.Name != nil && *r.Name == "" {
+		return BadRequestErr("name should not be empty", nil)
+	}
+	if r.Slug != nil {
+		if err := validateSlug(*r.Slug); err != nil {
+			return err
+		}
+	}
+	if r.Duration != nil && (*r.Duration <= 0 || *r.Duration > 1440) {
+		return BadRequestErr("invalid duration, should be in range 1-1440 minutes", nil)
+	}
+	if r.SlotIncrement != nil && (*r.SlotIncrement <= 0 || *r.SlotIncrement > 1440) {
+		return BadRequestErr("invalid slot increment, should be in range 1-1440 minutes", nil)
+	}
+	if err := validateEventRules(r.MinNotice, r.BookingHorizon, r.BufferBefore, r.BufferAfter); err != nil {
+		return err
+	}
+	return ValidateBookingCaps(r.DailyCap, r.WeeklyCap)
+}
+
+type EventTypeSlots struct {
+	EventType string                 `json:"event_type" example:"intro-30"` // slug of the event type
+	Duration  int                    `json:"duration" example:"30"`
+	Slots     map[string][]time.Time `json:"slots"` // bookable start times grouped by date
+} // @name EventTypeSlots
+
+func validateSlug(slug string) error {
+	if !slugRegex.MatchString(slug) {
+		return BadRequestErr("invalid slug, only lowercase letters, digits and dashes are allowed", nil)
+	}
+	return nil
+}
+
+func validateEventDuration(duration, increment int) error {
+	if duration <= 0 || duration > 1440 {
+		return BadRequestErr("invalid duration, should be in range 1-1440 minutes", nil)
+	}
+	if increment <= 0 || increment > 1440 {
+		return BadRequestErr("invalid slot increment, should be in range 1-1440 minutes", nil)
+	}
+	return nil
+}
+
+// validateEventRules checks the booking rules of an event type, nil rules are not being changed
+func validateEventRules(minNotice, bookingHorizon, bufferBefore, bufferAfter *int) error {
+	if minNotice != nil && (*minNotice < 0 || *minNotice > 365*1440) {
+		return BadRequestErr("invalid min notice, should be in range 0-525600 minutes", nil)
+	}
+	if bookingHorizon != nil && (*bookingHorizon < 0 || *bookingHorizon > 3650) {
+		return BadRequestErr("invalid booking horizon, should be in range 0-3650 days", nil)
+	}
+	for _, buffer := range []*int{bufferBefore, bufferAfter} {
+		if buffer != nil && (*buffer < 0 || *buffer > 1440) {
+			return BadRequestErr("invalid buffer, should be in range 0-1440 minutes", nil)
+		}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
@@ -12,7 +13,24 @@ const (
 	ErrNotFound            string = "record not found"
 	ErrUserNotFound        string = "user not found"
 	ErrInvalidUsername     string = "invalid username"
+	ErrInvalidTimezone     string = "invalid timezone, should be an IANA timezone like Europe/Berlin"
+	ErrSlotUnavailable     string = "requested time is not available"
+	ErrBookingNotFound     string = "booking not found"
+	ErrBookingCancelled    string = "booking is already cancelled"
+	ErrEventTypeNotFound   string = "event type not found"
+	ErrScheduleNotFound    string = "schedule not found"
+	ErrTimeOffNotFound     string = "time off not found"
+	ErrBookingCapReached   string = "no more bookings can be made on this day or week"
+	ErrInvalidFeedToken    string = "invalid calendar feed token"
+	ErrFeedTokenRequired   string = "the current calendar feed token (or the admin token) is required"
 	InternalServerErr      string = "Somewhere something went wrong but don't worry, we are on it."
+
+	ErrCalendarConnectionNotFound string = "calendar connection not found"
+	ErrCalendarSyncFailed         string = "unable to sync the calendar, please try again later"
+	ErrCalendarSyncConflict       string = "the calendar is being synced, please try again later"
+	ErrCalendarNotFound           string = "calendar not found"
+	ErrCalendarObjectNotFound     string = "calendar object not found"
+	ErrCalDAVForbidden            string = "only your own calendars can be read"
 )
 
 type Response struct {
@@ -38,6 +56,16 @@ func NotFoundErr(msg string, err error) *echo.HTTPError {
 	return CustomErr(http.StatusNotFound, msg, err)
 }
 
+func ConflictErr(msg string, err error) *echo.HTTPError {
+	return CustomErr(http.StatusConflict, msg, err)
+}
+
 func ServerErr(err error) *echo.HTTPError {
 	return CustomErr(http.StatusInternalServerError, InternalServerErr, err)
 }
+
+// IsHTTPError tells if err is one of the errors above (or wraps one), i.e. it can be returned as is
+func IsHTTPError(err error) bool {
+	var httpErr *echo.HTTPError
+	return errors.As(err, &httpErr)
+}
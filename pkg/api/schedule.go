@@ -0,0 +1,38 @@
+package api
+
+import "strings"
+
+type CreateScheduleRequest struct {
+	Name      string `json:"name" example:"Evening support" validate:"required"`
+	IsDefault bool   `json:"is_default"` // the user's first schedule is always the default one
+} // @name CreateScheduleRequest
+
+func (r *CreateScheduleRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	return validateScheduleName(r.Name)
+}
+
+type UpdateScheduleRequest struct {
+	Name      *string `json:"name"`
+	IsDefault *bool   `json:"is_default"` // only true is allowed, make another schedule the default to unset it
+} // @name UpdateScheduleRequest
+
+func (r *UpdateScheduleRequest) Validate() error {
+	if r.Name != nil {
+		*r.Name = strings.TrimSpace(*r.Name)
+		if err := validateScheduleName(*r.Name); err != nil {
+			return err
+		}
+	}
+	if r.IsDefault != nil && !*r.IsDefault {
+		return BadRequestErr("a user always has a default schedule, make another schedule the default instead", nil)
+	}
+	return nil
+}
+
+func validateScheduleName(name string) error {
+	if name == "" || len(name) > 255 {
+		return BadRequestErr("invalid name, should be 1-255 characters", nil)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package api
+
+import "time"
+
+type CreateTimeOffRequest struct {
+	StartDate   time.Time `json:"start_date" example:"2024-12-23T00:00:00Z" validate:"required"`
+	EndDate     time.Time `json:"end_date" example:"2025-01-03T00:00:00Z" validate:"required"`
+	StartMinute *int      `json:"start_minute" example:"720"` // time off starts at this minute of the start date, whole day (0) by default
+	EndMinute   *int      `json:"end_minute" example:"1440"`  // time off ends at this minute of the end date, whole day (1440) by default
+	Reason      string    `json:"reason" example:"Vacation"`
+} // @name CreateTimeOffRequest
+
+func (r *CreateTimeOffRequest) Validate() error {
+	if r.StartDate.IsZero() || r.EndDate.IsZero() {
+		return BadRequestErr("invalid date", nil)
+	}
+	// input dates are calendar dates in the user's timezone, keep them as written
+	r.StartDate = time.Date(r.StartDate.Year(), r.StartDate.Month(), r.StartDate.Day(), 0, 0, 0, 0, time.UTC)
+	r.EndDate = time.Date(r.EndDate.Year(), r.EndDate.Month(), r.EndDate.Day(), 0, 0, 0, 0, time.UTC)
+	if r.StartMinute == nil {
+		r.StartMinute = new(int)
+	}
+	if r.EndMinute == nil {
+		endMinute := 1440
+		r.EndMinute = &endMinute
+	}
+	return ValidateTimeOffRange(r.StartDate, r.EndDate, *r.StartMinute, *r.EndMinute)
+}
+
+type UpdateTimeOffRequest struct {
+	StartDate   *time.Time `json:"start_date" example:"2024-12-23T00:00:00Z"`
+	EndDate     *time.Time `json:"end_date" example:"2025-01-03T00:00:00Z"`
+	StartMinute *int       `json:"start_minute"`
+	EndMinute   *int       `json:"end_minute"`
+	Reason      *string    `json:"reason"`
+} // @name UpdateTimeOffRequest
+
+func (r *UpdateTimeOffRequest) Validate() error {
+	for _, date := range []*time.Time{r.StartDate, r.EndDate} {
+		if date == nil {
+			continue
+		}
+		if date.IsZero() {
+			return BadRequestErr("invalid date", nil)
+		}
+		*date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return nil
+}
+
+// ValidateTimeOffRange checks the time off ends after it starts, minutes are since midnight of their date
+func ValidateTimeOffRange(startDate, endDate time.Time, startMinute, endMinute int) error {
+	if startMinute < 0 || startMinute >= 1440 || endMinute <= 0 || endMinute > 1440 {
+		return BadRequestErr("invalid time off, start minute should be in range 0-1439 and end minute in range 1-1440", nil)
+	}
+	if endDate.Before(startDate) || (endDate.Equal(startDate) && endMinute <= startMinute) {
+		return BadRequestErr("invalid time off, should end after it starts", nil)
+	}
+	return nil
+}
@@ -1,8 +1,80 @@
 package api
 
+import (
+	"time"
+
+	"github.com/niharika88/calendly-api/pkg/holidays"
+)
+
 type UpdateUserRequest struct {
 	FirstName *string `json:"first_name"`
 	LastName  *string `json:"last_name"`
 	Email     *string `json:"email"`
 	Timezone  *string `json:"timezone"`
+
+	HolidayRegion *string `json:"holiday_region"`
+
+	DailyBookingCap  *int `json:"daily_booking_cap"`
+	WeeklyBookingCap *int `json:"weekly_booking_cap"`
 } // @name UpdateUserRequest
+
+func (r *UpdateUserRequest) Validate() error {
+	if r.Timezone != nil {
+		if err := ValidateTimezone(*r.Timezone); err != nil {
+			return err
+		}
+	}
+	if r.HolidayRegion != nil {
+		if err := ValidateHolidayRegion(*r.HolidayRegion); err != nil {
+			return err
+		}
+	}
+	return ValidateBookingCaps(r.DailyBookingCap, r.WeeklyBookingCap)
+}
+
+// ParseTimezone loads the IANA timezone (e.g. "Europe/Berlin"), empty timezone is UTC. "Local" is refused, it's the
+// timezone of wherever the server runs
+func ParseTimezone(tz string) (*time.Location, error) {
+	if tz == "Local" {
+		return nil, BadRequestErr(ErrInvalidTimezone, nil)
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, BadRequestErr(ErrInvalidTimezone, err)
+	}
+	return loc, nil
+}
+
+// ValidateTimezone checks the timezone of a user is an IANA timezone, unlike ParseTimezone it has to be given
+func ValidateTimezone(tz string) error {
+	if tz == "" {
+		return BadRequestErr(ErrInvalidTimezone, nil)
+	}
+	_, err := ParseTimezone(tz)
+	return err
+}
+
+// ValidateHolidayRegion checks that the region is part of the holiday dataset, empty region means no holidays
+func ValidateHolidayRegion(region string) error {
+	if region != "" && !holidays.IsValidRegion(region) {
+		return BadRequestErr("invalid holiday region, see /holidays/regions for the supported ones", nil)
+	}
+	return nil
+}
+
+// ValidateBookingCaps checks the daily and weekly booking caps of a host or an event type, nil caps are not being changed
+func ValidateBookingCaps(daily, weekly *int) error {
+	if daily != nil && *daily < 0 {
+		return BadRequestErr("invalid daily cap, should be 0 (no cap) or more", nil)
+	}
+	if weekly != nil && *weekly < 0 {
+		return BadRequestErr("invalid weekly cap, should be 0 (no cap) or more", nil)
+	}
+	return nil
+}
+
+// CalendarFeedTokenResponse is the new secret token of a user's iCalendar feed, it's only shown once
+type CalendarFeedTokenResponse struct {
+	Token string `json:"token" example:"q3v0bC1hcGktZmVlZC10b2tlbi1leGFtcGxl"`
+	URL   string `json:"url" example:"https://calendly.example/api/users/jdoe/calendar.ics?token=q3v0bC1hcGktZmVlZC10b2tlbi1leGFtcGxl"`
+} // @name CalendarFeedTokenResponse
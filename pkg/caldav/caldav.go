@@ -0,0 +1,380 @@
+// Package caldav implements the XML side of a read-only CalDAV (RFC 4791) server on top of WebDAV (RFC 4918): decoding
+// PROPFIND and REPORT requests and encoding multistatus responses. The resources themselves are served by the handlers
+package caldav
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	NamespaceDAV            = "DAV:"
+	NamespaceCalDAV         = "urn:ietf:params:xml:ns:caldav"
+	NamespaceCalendarServer = "http://calendarserver.org/ns/"
+
+	// Capabilities is the value of the DAV header of the responses to OPTIONS
+	Capabilities = "1, 3, calendar-access"
+
+	timeFormat = "20060102T150405Z"
+)
+
+// names of the properties and elements used by the server
+var (
+	PropResourceType          = xml.Name{Space: NamespaceDAV, Local: "resourcetype"}
+	PropDisplayName           = xml.Name{Space: NamespaceDAV, Local: "displayname"}
+	PropGetETag               = xml.Name{Space: NamespaceDAV, Local: "getetag"}
+	PropGetContentType        = xml.Name{Space: NamespaceDAV, Local: "getcontenttype"}
+	PropGetContentLength      = xml.Name{Space: NamespaceDAV, Local: "getcontentlength"}
+	PropGetLastModified       = xml.Name{Space: NamespaceDAV, Local: "getlastmodified"}
+	PropCurrentUserPrincipal  = xml.Name{Space: NamespaceDAV, Local: "current-user-principal"}
+	PropPrincipalURL          = xml.Name{Space: NamespaceDAV, Local: "principal-URL"}
+	PropOwner                 = xml.Name{Space: NamespaceDAV, Local: "owner"}
+	PropCurrentUserPrivileges = xml.Name{Space: NamespaceDAV, Local: "current-user-privilege-set"}
+	PropSupportedReportSet    = xml.Name{Space: NamespaceDAV, Local: "supported-report-set"}
+
+	PropCalendarHomeSet       = xml.Name{Space: NamespaceCalDAV, Local: "calendar-home-set"}
+	PropCalendarUserAddresses = xml.Name{Space: NamespaceCalDAV, Local: "calendar-user-address-set"}
+	PropCalendarDescription   = xml.Name{Space: NamespaceCalDAV, Local: "calendar-description"}
+	PropSupportedComponents   = xml.Name{Space: NamespaceCalDAV, Local: "supported-calendar-component-set"}
+	PropCalendarData          = xml.Name{Space: NamespaceCalDAV, Local: "calendar-data"}
+
+	// PropCTag changes whenever an object of the calendar changes, clients skip unchanged calendars with it
+	PropCTag = xml.Name{Space: NamespaceCalendarServer, Local: "getctag"}
+
+	ElemHref       = xml.Name{Space: NamespaceDAV, Local: "href"}
+	ElemCollection = xml.Name{Space: NamespaceDAV, Local: "collection"}
+	ElemPrincipal  = xml.Name{Space: NamespaceDAV, Local: "principal"}
+	ElemPrivilege  = xml.Name{Space: NamespaceDAV, Local: "privilege"}
+	ElemRead       = xml.Name{Space: NamespaceDAV, Local: "read"}
+	ElemCalendar   = xml.Name{Space: NamespaceCalDAV, Local: "calendar"}
+	ElemComp       = xml.Name{Space: NamespaceCalDAV, Local: "comp"}
+
+	ElemCalendarQuery    = xml.Name{Space: NamespaceCalDAV, Local: "calendar-query"}
+	ElemCalendarMultiget = xml.Name{Space: NamespaceCalDAV, Local: "calendar-multiget"}
+	ElemFreeBusyQuery    = xml.Name{Space: NamespaceCalDAV, Local: "free-busy-query"}
+	ElemReadFreeBusy     = xml.Name{Space: NamespaceCalDAV, Local: "read-free-busy"}
+)
+
+// Calendar is a calendar collection of a principal, Name is its path segment
+type Calendar struct {
+	Name        string
+	DisplayName string
+	Description string
+}
+
+// Object is a calendar object resource, Name is its path segment within the calendar collection. ModTime is zero when unknown
+type Object struct {
+	Name    string
+	ModTime time.Time
+	Data    []byte
+}
+
+// ETag returns the strong entity tag (quoted) of the object, the hash of its data
+func (o *Object) ETag() string {
+	sum := sha256.Sum256(o.Data)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// CTag returns the tag of a calendar holding the objects, it changes whenever an object is added, changed or removed
+func CTag(objects []*Object) string {
+	h := sha256.New()
+	for _, o := range objects {
+		fmt.Fprintf(h, "%s %s\n", o.Name, o.ETag())
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// Property is a WebDAV property, or an element of the value of one
+type Property struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Text     string     `xml:",chardata"`
+	Children []Property `xml:",any"`
+}
+
+// NewProperty returns the property with a text value
+func NewProperty(name xml.Name, text string) Property {
+	return Property{XMLName: name, Text: text}
+}
+
+// NewElement returns the property (or element) made of the children elements
+func NewElement(name xml.Name, children ...Property) Property {
+	return Property{XMLName: name, Children: children}
+}
+
+// NewHref returns the property holding a single href, e.g. current-user-principal
+func NewHref(name xml.Name, href string) Property {
+	return NewElement(name, NewProperty(ElemHref, href))
+}
+
+// NewComponentSet returns the supported-calendar-component-set of the component names, e.g. VEVENT
+func NewComponentSet(components ...string) Property {
+	prop := NewElement(PropSupportedComponents)
+	for _, comp := range components {
+		prop.Children = append(prop.Children, Property{
+			XMLName: ElemComp,
+			Attrs:   []xml.Attr{{Name: xml.Name{Local: "name"}, Value: comp}},
+		})
+	}
+	return prop
+}
+
+// NewReportSet returns the supported-report-set of the reports, e.g. ElemCalendarQuery
+func NewReportSet(reports ...xml.Name) Property {
+	prop := NewElement(PropSupportedReportSet)
+	for _, report := range reports {
+		prop.Children = append(prop.Children, NewElement(
+			xml.Name{Space: NamespaceDAV, Local: "supported-report"},
+			NewElement(xml.Name{Space: NamespaceDAV, Local: "report"}, NewElement(report)),
+		))
+	}
+	return prop
+}
+
+// NewPrivilegeSet returns the current-user-privilege-set of the privileges, e.g. ElemRead
+func NewPrivilegeSet(privileges ...xml.Name) Property {
+	prop := NewElement(PropCurrentUserPrivileges)
+	for _, privilege := range privileges {
+		prop.Children = append(prop.Children, NewElement(ElemPrivilege, NewElement(privilege)))
+	}
+	return prop
+}
+
+// ObjectProperties returns the properties of the calendar object, getlastmodified is left out when its ModTime is unknown
+func ObjectProperties(o *Object) []Property {
+	props := []Property{
+		NewElement(PropResourceType),
+		NewProperty(PropGetETag, o.ETag()),
+		NewProperty(PropGetContentType, "text/calendar; charset=utf-8; component=VEVENT"),
+		NewProperty(PropGetContentLength, fmt.Sprint(len(o.Data))),
+	}
+	if !o.ModTime.IsZero() {
+		props = append(props, NewProperty(PropGetLastModified, o.ModTime.UTC().Format(http.TimeFormat)))
+	}
+	return append(props, NewProperty(PropCalendarData, string(o.Data)))
+}
+
+// MultiStatus is the body of a 207 response
+type MultiStatus struct {
+	XMLName   xml.Name   `xml:"DAV: multistatus"`
+	Responses []Response `xml:"response"`
+}
+
+// Response holds the properties of the resource at Href, or only a Status when the resource couldn't be read
+type Response struct {
+	Href      string     `xml:"href"`
+	PropStats []PropStat `xml:"propstat,omitempty"`
+	Status    string     `xml:"status,omitempty"`
+}
+
+type PropStat struct {
+	Prop   Prop   `xml:"prop"`
+	Status string `xml:"status"`
+}
+
+// Prop is a list of properties, only their names in requests
+type Prop struct {
+	Props []Property `xml:",any"`
+}
+
+// StatusResponse returns the response of a resource that couldn't be read, e.g. a missing one
+func StatusResponse(href string, code int) Response {
+	return Response{Href: href, Status: status(code)}
+}
+
+func status(code int) string {
+	return fmt.Sprintf("HTTP/1.1 %d %s", code, http.StatusText(code))
+}
+
+// PropRequest tells which properties are returned for each resource: all of them, only their names or the listed ones
+type PropRequest struct {
+	AllProp  *struct{} `xml:"DAV: allprop"`
+	PropName *struct{} `xml:"DAV: propname"`
+	Prop     *Prop     `xml:"DAV: prop"`
+}
+
+// Response selects the requested properties out of the resource's props, requested properties the resource doesn't have
+// are reported as not found. calendar-data is only returned when requested by name, as for any expensive property
+func (pr *PropRequest) Response(href string, props []Property) Response {
+	var found, missing []Property
+	switch {
+	case pr.Prop != nil:
+		for _, requested := range pr.Prop.Props {
+			prop, ok := findProperty(props, requested.XMLName)
+			if ok {
+				found = append(found, prop)
+			} else {
+				missing = append(missing, Property{XMLName: requested.XMLName})
+			}
+		}
+	case pr.PropName != nil:
+		for _, prop := range props {
+			found = append(found, Property{XMLName: prop.XMLName})
+		}
+	default:
+		for _, prop := range props {
+			if prop.XMLName != PropCalendarData {
+				found = append(found, prop)
+			}
+		}
+	}
+
+	res := Response{Href: href}
+	if len(found) > 0 {
+		res.PropStats = append(res.PropStats, PropStat{Prop: Prop{Props: found}, Status: status(http.StatusOK)})
+	}
+	if len(missing) > 0 {
+		res.PropStats = append(res.PropStats, PropStat{Prop: Prop{Props: missing}, Status: status(http.StatusNotFound)})
+	}
+	if len(res.PropStats) == 0 {
+		res.Status = status(http.StatusOK)
+	}
+	return res
+}
+
+func findProperty(props []Property, name xml.Name) (Property, bool) {
+	for _, prop := range props {
+		if prop.XMLName == name {
+			return prop, true
+		}
+	}
+	return Property{}, false
+}
+
+// PropFind is the body of a PROPFIND request
+type PropFind struct {
+	XMLName xml.Name `xml:"DAV: propfind"`
+	PropRequest
+}
+
+// ParsePropFind decodes the body of a PROPFIND request, an empty body asks for all properties
+func ParsePropFind(r io.Reader) (*PropFind, error) {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	propfind := &PropFind{}
+	if len(bytes.TrimSpace(body)) == 0 {
+		propfind.AllProp = &struct{}{}
+		return propfind, nil
+	}
+	if err := xml.Unmarshal(body, propfind); err != nil {
+		return nil, fmt.Errorf("invalid propfind: %w", err)
+	}
+	return propfind, nil
+}
+
+// CalendarQuery is the calendar-query REPORT, it lists the objects of a calendar matching the filter
+type CalendarQuery struct {
+	XMLName xml.Name `xml:"urn:ietf:params:xml:ns:caldav calendar-query"`
+	PropRequest
+	Filter CompFilter `xml:"urn:ietf:params:xml:ns:caldav filter>comp-filter"`
+}
+
+// CompFilter matches the components named Name, only time ranges are supported out of the possible conditions
+type CompFilter struct {
+	Name        string       `xml:"name,attr"`
+	TimeRange   *TimeRange   `xml:"urn:ietf:params:xml:ns:caldav time-range"`
+	CompFilters []CompFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+// TimeRange is an absolute range of time, a missing bound is open
+type TimeRange struct {
+	Start string `xml:"start,attr"`
+	End   string `xml:"end,attr"`
+}
+
+// Bounds parses the range, zero times stand for the open bounds
+func (tr *TimeRange) Bounds() (start, end time.Time, err error) {
+	if tr.Start != "" {
+		if start, err = time.Parse(timeFormat, tr.Start); err != nil {
+			return start, end, fmt.Errorf("invalid time-range start %q", tr.Start)
+		}
+	}
+	if tr.End != "" {
+		if end, err = time.Parse(timeFormat, tr.End); err != nil {
+			return start, end, fmt.Errorf("invalid time-range end %q", tr.End)
+		}
+	}
+	if !start.IsZero() && !end.IsZero() && !start.Before(end) {
+		return start, end, fmt.Errorf("time-range start must be before its end")
+	}
+	return start, end, nil
+}
+
+// Events tells if the query's filter selects the VEVENTs of a calendar, along with the time range they must overlap if any.
+// Queries for other components (e.g. VTODO) match nothing since calendars only hold events
+func (q *CalendarQuery) Events() (bool, *TimeRange) {
+	if q.Filter.Name != "VCALENDAR" {
+		return false, nil
+	}
+	if len(q.Filter.CompFilters) == 0 {
+		return true, nil
+	}
+	for _, filter := range q.Filter.CompFilters {
+		if filter.Name == "VEVENT" {
+			return true, filter.TimeRange
+		}
+	}
+	return false, nil
+}
+
+// CalendarMultiget is the calendar-multiget REPORT, it returns the objects at Hrefs
+type CalendarMultiget struct {
+	XMLName xml.Name `xml:"urn:ietf:params:xml:ns:caldav calendar-multiget"`
+	PropRequest
+	Hrefs []string `xml:"DAV: href"`
+}
+
+// FreeBusyQuery is the free-busy-query REPORT, its answer is a VFREEBUSY over the time range
+type FreeBusyQuery struct {
+	XMLName   xml.Name  `xml:"urn:ietf:params:xml:ns:caldav free-busy-query"`
+	TimeRange TimeRange `xml:"urn:ietf:params:xml:ns:caldav time-range"`
+}
+
+// Report is the body of a REPORT request, only one of the reports is set
+type Report struct {
+	Query    *CalendarQuery
+	Multiget *CalendarMultiget
+	FreeBusy *FreeBusyQuery
+}
+
+// ParseReport decodes the body of a REPORT request, it's one of the calendar-query, calendar-multiget and free-busy-query reports
+func ParseReport(r io.Reader) (*Report, error) {
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("invalid report: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		report := &Report{}
+		switch start.Name {
+		case ElemCalendarQuery:
+			report.Query = &CalendarQuery{}
+			err = decoder.DecodeElement(report.Query, &start)
+		case ElemCalendarMultiget:
+			report.Multiget = &CalendarMultiget{}
+			err = decoder.DecodeElement(report.Multiget, &start)
+		case ElemFreeBusyQuery:
+			report.FreeBusy = &FreeBusyQuery{}
+			err = decoder.DecodeElement(report.FreeBusy, &start)
+		default:
+			return nil, fmt.Errorf("unsupported report %s", start.Name.Local)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", start.Name.Local, err)
+		}
+		return report, nil
+	}
+}
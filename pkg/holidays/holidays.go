@@ -0,0 +1,200 @@
+// Package holidays computes public holidays of a country or region offline from an embedded dataset.
+// Holidays are given as rules (fixed dates, nth weekday of a month, days relative to easter sunday) so any year can be computed
+package holidays
+
+import (
+	_ "embed"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+)
+
+//go:embed holidays.json
+var dataset []byte
+
+const (
+	// observedNearestWeekday moves a holiday falling on saturday to the friday before and one on sunday to the monday after
+	observedNearestWeekday = "nearest_weekday"
+	// observedNextWeekday moves a holiday falling on a weekend to the next weekday that is not a holiday already
+	observedNextWeekday = "next_weekday"
+)
+
+type Region struct {
+	Code string `json:"code" example:"DE-BY"`
+	Name string `json:"name" example:"Germany, Bavaria"`
+} // @name HolidayRegion
+
+type Holiday struct {
+	Date time.Time `json:"date" example:"2025-10-03T00:00:00Z"`
+	Name string    `json:"name" example:"Tag der Deutschen Einheit"`
+} // @name Holiday
+
+// rule is a holiday of the dataset, it occurs on
+//   - Month/Day, when only those are set
+//   - the Nth Weekday of Month (-1 being the last one), or when Day is set too the first Weekday on or after Day (Nth > 0)
+//     or the last one on or before Day (Nth < 0)
+//   - Easter days after easter sunday (negative for days before it)
+type rule struct {
+	Name     string     `json:"name"`
+	Month    time.Month `json:"month"`
+	Day      int        `json:"day"`
+	Weekday  string     `json:"weekday"`
+	Nth      int        `json:"nth"`
+	Easter   *int       `json:"easter"`
+	Observed string     `json:"observed"`
+}
+
+type region struct {
+	Name     string `json:"name"`
+	Extends  string `json:"extends"` // holidays of the parent region apply too, e.g. DE-BY extends DE
+	Holidays []rule `json:"holidays"`
+}
+
+var regions map[string]*region
+
+func init() {
+	if err := json.Unmarshal(dataset, &regions); err != nil {
+		panic("holidays: invalid dataset: " + err.Error())
+	}
+}
+
+// IsValidRegion checks if the region code (e.g. "US", "DE-BY") is part of the dataset
+func IsValidRegion(code string) bool {
+	_, ok := regions[code]
+	return ok
+}
+
+// Regions returns every region of the dataset sorted by code
+func Regions() []Region {
+	res := make([]Region, 0, len(regions))
+	for code, r := range regions {
+		res = append(res, Region{Code: code, Name: r.Name})
+	}
+	slices.SortFunc(res, func(a, b Region) int {
+		return strings.Compare(a.Code, b.Code)
+	})
+	return res
+}
+
+// Between returns the holidays of the region from fromDate to toDate (both included, dates are UTC midnight) sorted by date.
+// A holiday moved to a weekday (observed) is returned on both dates, an unknown region has no holidays
+func Between(code string, fromDate, toDate time.Time) []Holiday {
+	if !IsValidRegion(code) {
+		return nil
+	}
+	var res []Holiday
+	// observed dates can move a holiday into the year before or after
+	for year := fromDate.Year() - 1; year <= toDate.Year()+1; year++ {
+		for _, h := range ofYear(code, year) {
+			if !h.Date.Before(fromDate) && !h.Date.After(toDate) {
+				res = append(res, h)
+			}
+		}
+	}
+	return res
+}
+
+// ofYear computes the holidays of the region and of the ones it extends in the year
+func ofYear(code string, year int) []Holiday {
+	var rules []rule
+	for r := regions[code]; r != nil; r = regions[r.Extends] {
+		rules = append(rules, r.Holidays...)
+	}
+	// moved in date order, so when christmas and boxing day both fall on a weekend they move to monday and tuesday
+	slices.SortStableFunc(rules, func(a, b rule) int {
+		return a.date(year).Compare(b.date(year))
+	})
+
+	holidays := make([]Holiday, 0, len(rules))
+	taken := make(map[time.Time]bool, len(rules))
+	for _, r := range rules {
+		date := r.date(year)
+		holidays = append(holidays, Holiday{Date: date, Name: r.Name})
+		taken[date] = true
+	}
+	for _, r := range rules {
+		if date, ok := r.observedDate(r.date(year), taken); ok {
+			taken[date] = true
+			holidays = append(holidays, Holiday{Date: date, Name: r.Name + " (observed)"})
+		}
+	}
+	sortHolidays(holidays)
+	return holidays
+}
+
+func sortHolidays(holidays []Holiday) {
+	slices.SortStableFunc(holidays, func(a, b Holiday) int {
+		return a.Date.Compare(b.Date)
+	})
+}
+
+// date computes the date of the rule in the year
+func (r rule) date(year int) time.Time {
+	if r.Easter != nil {
+		return easter(year).AddDate(0, 0, *r.Easter)
+	}
+	if r.Weekday == "" {
+		return time.Date(year, r.Month, r.Day, 0, 0, 0, 0, time.UTC)
+	}
+
+	weekday := parseWeekday(r.Weekday)
+	if r.Day != 0 {
+		anchor := time.Date(year, r.Month, r.Day, 0, 0, 0, 0, time.UTC)
+		if r.Nth < 0 {
+			return anchor.AddDate(0, 0, -((int(anchor.Weekday()) - int(weekday) + 7) % 7))
+		}
+		return anchor.AddDate(0, 0, (int(weekday)-int(anchor.Weekday())+7)%7)
+	}
+	if r.Nth < 0 {
+		last := time.Date(year, r.Month+1, 0, 0, 0, 0, 0, time.UTC)
+		return last.AddDate(0, 0, -((int(last.Weekday())-int(weekday)+7)%7)+7*(r.Nth+1))
+	}
+	first := time.Date(year, r.Month, 1, 0, 0, 0, 0, time.UTC)
+	return first.AddDate(0, 0, (int(weekday)-int(first.Weekday())+7)%7+7*(r.Nth-1))
+}
+
+// observedDate returns the weekday the holiday on date is observed on when it falls on a weekend
+func (r rule) observedDate(date time.Time, taken map[time.Time]bool) (time.Time, bool) {
+	if date.Weekday() != time.Saturday && date.Weekday() != time.Sunday {
+		return date, false
+	}
+	switch r.Observed {
+	case observedNearestWeekday:
+		if date.Weekday() == time.Saturday {
+			return date.AddDate(0, 0, -1), true
+		}
+		return date.AddDate(0, 0, 1), true
+	case observedNextWeekday:
+		for date = date.AddDate(0, 0, 1); date.Weekday() == time.Saturday || date.Weekday() == time.Sunday || taken[date]; {
+			date = date.AddDate(0, 0, 1)
+		}
+		return date, true
+	}
+	return date, false
+}
+
+// easter computes easter sunday of the (gregorian) year with the anonymous gregorian algorithm
+func easter(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+func parseWeekday(weekday string) time.Weekday {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(d.String(), weekday) {
+			return d
+		}
+	}
+	return time.Sunday
+}
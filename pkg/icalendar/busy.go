@@ -0,0 +1,416 @@
+package icalendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/teambition/rrule-go"
+)
+
+const (
+	dateFormat        = "20060102"
+	utcDateTimeFormat = "20060102T150405Z"
+
+	// maxOccurrences bounds the expansion of a single recurring event within the range
+	maxOccurrences = 5000
+	// maxIterations bounds the occurrences of a recurring event gone through, including the ones before the range
+	maxIterations = 100 * maxOccurrences
+)
+
+// rruleWeekdays are the weekdays of rrule by their index, Monday first
+var rruleWeekdays = []rrule.Weekday{rrule.MO, rrule.TU, rrule.WE, rrule.TH, rrule.FR, rrule.SA, rrule.SU}
+
+// Period is an absolute [Start, End) range of time
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// BusyEvent is a VEVENT of a calendar along with its overridden occurrences (same UID), Periods are the occurrences
+// blocking time. They are empty when the event is cancelled or transparent, i.e. it doesn't block anything (anymore)
+type BusyEvent struct {
+	UID     string
+	Summary string
+	Periods []Period
+}
+
+// duration of an event, Days are nominal (a day is 23 hours on the day clocks are moved) and added before Exact
+type duration struct {
+	days  int
+	exact time.Duration
+}
+
+func (d duration) after(start time.Time) time.Time {
+	return start.AddDate(0, 0, d.days).Add(d.exact)
+}
+
+// max returns the longest the duration can be, days around a change of the clocks last 25 hours
+func (d duration) max() time.Duration {
+	return time.Duration(d.days)*25*time.Hour + d.exact
+}
+
+// Busy expands the VEVENTs of the calendar into the periods they block within [from, to), one BusyEvent per UID in file order.
+// RRULE, RDATE, EXDATE and occurrences overridden by a RECURRENCE-ID are taken into account, floating times and all-day dates
+// are interpreted in loc. Events that can't be interpreted are left out and reported in the warnings
+func Busy(cal *ical.Calendar, loc *time.Location, from, to time.Time) ([]BusyEvent, []string) {
+	var warnings []string
+	var uids []string
+	masters := make(map[string]ical.Event)
+	overrides := make(map[string][]ical.Event)
+	for _, event := range cal.Events() {
+		uid, _ := event.Props.Text(ical.PropUID)
+		if uid == "" {
+			summary, _ := event.Props.Text(ical.PropSummary)
+			warnings = append(warnings, fmt.Sprintf("event %q has no UID, skipped", summary))
+			continue
+		}
+		_, isMaster := masters[uid]
+		if _, ok := overrides[uid]; !ok && !isMaster {
+			uids = append(uids, uid)
+		}
+		if event.Props.Get(ical.PropRecurrenceID) != nil {
+			overrides[uid] = append(overrides[uid], event)
+			continue
+		}
+		if isMaster {
+			warnings = append(warnings, fmt.Sprintf("event %s is given more than once, the last one is used", uid))
+		}
+		masters[uid] = event
+	}
+
+	res := make([]BusyEvent, 0, len(uids))
+	for _, uid := range uids {
+		busy, err := expand(masters[uid], overrides[uid], loc, from, to)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("event %s skipped: %s", uid, err))
+			continue
+		}
+		if len(busy.Periods) > maxOccurrences {
+			busy.Periods = busy.Periods[:maxOccurrences]
+			warnings = append(warnings, fmt.Sprintf("event %s has too many occurrences, only the first %d are used", uid, maxOccurrences))
+		}
+		res = append(res, busy)
+	}
+	return res, warnings
+}
+
+// expand computes the busy periods of the master event (nil when the calendar only has overridden occurrences)
+// and of its overridden occurrences within [from, to)
+func expand(master ical.Event, overrides []ical.Event, loc *time.Location, from, to time.Time) (BusyEvent, error) {
+	busy := BusyEvent{}
+	overridden := make(map[int64]bool, len(overrides))
+	for _, o := range overrides {
+		recurrenceID, err := parseDateTime(o.Props.Get(ical.PropRecurrenceID), loc)
+		if err != nil {
+			return busy, fmt.Errorf("invalid RECURRENCE-ID: %w", err)
+		}
+		overridden[recurrenceID.Unix()] = true
+	}
+
+	if master.Component != nil {
+		busy.UID, _ = master.Props.Text(ical.PropUID)
+		busy.Summary, _ = master.Props.Text(ical.PropSummary)
+		starts, dur, err := occurrences(master, loc, from, to)
+		if err != nil {
+			return busy, err
+		}
+		if isBusy(master) {
+			for _, start := range starts {
+				if end := dur.after(start); !overridden[start.Unix()] && end.After(from) && end.After(start) {
+					busy.Periods = append(busy.Periods, Period{Start: start, End: end})
+				}
+			}
+		}
+	}
+
+	for _, o := range overrides {
+		if busy.UID == "" {
+			busy.UID, _ = o.Props.Text(ical.PropUID)
+			busy.Summary, _ = o.Props.Text(ical.PropSummary)
+		}
+		if !isBusy(o) {
+			continue
+		}
+		start, dur, err := eventStart(o, loc)
+		if err != nil {
+			return busy, err
+		}
+		if end := dur.after(start); start.Before(to) && end.After(from) && end.After(start) {
+			busy.Periods = append(busy.Periods, Period{Start: start, End: end})
+		}
+	}
+	return busy, nil
+}
+
+// occurrences returns the starts of the event's occurrences overlapping [from, to) (just DTSTART for a non recurring event) and their
+// duration. A rule without COUNT is expanded from its last period before the range rather than from DTSTART, and the expansion stops
+// after maxOccurrences+1 starts (the caller warns about the extra one) or maxIterations occurrences gone through
+func occurrences(event ical.Event, loc *time.Location, from, to time.Time) ([]time.Time, duration, error) {
+	start, dur, err := eventStart(event, loc)
+	if err != nil {
+		return nil, dur, err
+	}
+	rdates, err := parseDateTimeList(event.Props.Values(ical.PropRecurrenceDates), start.Location())
+	if err != nil {
+		return nil, dur, fmt.Errorf("invalid RDATE: %w", err)
+	}
+	ruleProp := event.Props.Get(ical.PropRecurrenceRule)
+	if ruleProp == nil && len(rdates) == 0 {
+		if start.Before(to) {
+			return []time.Time{start}, dur, nil
+		}
+		return nil, dur, nil
+	}
+
+	set := rrule.Set{}
+	set.DTStart(start)
+	set.RDate(start)
+	if ruleProp != nil {
+		option, err := rrule.StrToROptionInLocation(ruleProp.Value, start.Location())
+		if err != nil {
+			return nil, dur, fmt.Errorf("invalid RRULE: %w", err)
+		}
+		if option.Freq == rrule.MINUTELY || option.Freq == rrule.SECONDLY {
+			return nil, dur, fmt.Errorf("RRULE with FREQ=%s is not supported", option.Freq)
+		}
+		if option.Freq == rrule.HOURLY && !reachesHour(start.Hour(), max(option.Interval, 1), option.Byhour) {
+			return nil, dur, fmt.Errorf("RRULE never occurs, INTERVAL hours from DTSTART never fall on BYHOUR")
+		}
+		option.Dtstart = start
+		fastForward(option, from.Add(-dur.max()))
+		rule, err := rrule.NewRRule(*option)
+		if err != nil {
+			return nil, dur, fmt.Errorf("invalid RRULE: %w", err)
+		}
+		set.RRule(rule)
+	}
+	for _, rdate := range rdates {
+		set.RDate(rdate)
+	}
+	exdates, err := parseDateTimeList(event.Props.Values(ical.PropExceptionDates), start.Location())
+	if err != nil {
+		return nil, dur, fmt.Errorf("invalid EXDATE: %w", err)
+	}
+	for _, exdate := range exdates {
+		set.ExDate(exdate)
+	}
+
+	var starts []time.Time
+	next := set.Iterator()
+	for i := 0; len(starts) <= maxOccurrences; i++ {
+		t, ok := next()
+		if !ok || !t.Before(to) {
+			break
+		}
+		if i == maxIterations {
+			return nil, dur, fmt.Errorf("too many occurrences to expand, more than %d", maxIterations)
+		}
+		if dur.after(t).After(from) {
+			starts = append(starts, t)
+		}
+	}
+	return starts, dur, nil
+}
+
+// reachesHour tells if stepping interval hours from the hour falls on one of the hours (any when empty), rrule doesn't stop looking
+func reachesHour(hour, interval int, hours []int) bool {
+	if len(hours) == 0 {
+		return true
+	}
+	// the hours reached are the ones congruent to hour modulo gcd(interval, 24)
+	step := interval
+	for b := 24; b != 0; {
+		step, b = b, step%b
+	}
+	for _, h := range hours {
+		if ((h-hour)%step+step)%step == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// fastForward moves the DTSTART of a rule without COUNT to the start of its last period (of FREQ and INTERVAL) not after since,
+// so that a rule started long ago isn't expanded from its first occurrence. The parts of the rule DTSTART implies (e.g. the day of
+// a monthly rule) are made explicit first, the rule has the same occurrences from the new DTSTART on
+func fastForward(option *rrule.ROption, since time.Time) {
+	start := option.Dtstart
+	if option.Count > 0 || !since.After(start) {
+		return
+	}
+	if len(option.Byweekno) == 0 && len(option.Byyearday) == 0 && len(option.Bymonthday) == 0 &&
+		len(option.Byweekday) == 0 && len(option.Byeaster) == 0 {
+		switch option.Freq {
+		case rrule.YEARLY:
+			if len(option.Bymonth) == 0 {
+				option.Bymonth = []int{int(start.Month())}
+			}
+			option.Bymonthday = []int{start.Day()}
+		case rrule.MONTHLY:
+			option.Bymonthday = []int{start.Day()}
+		case rrule.WEEKLY:
+			option.Byweekday = []rrule.Weekday{rruleWeekdays[(int(start.Weekday())+6)%7]}
+		}
+	}
+	if len(option.Byhour) == 0 && option.Freq < rrule.HOURLY {
+		option.Byhour = []int{start.Hour()}
+	}
+	if len(option.Byminute) == 0 && option.Freq < rrule.MINUTELY {
+		option.Byminute = []int{start.Minute()}
+	}
+	if len(option.Bysecond) == 0 && option.Freq < rrule.SECONDLY {
+		option.Bysecond = []int{start.Second()}
+	}
+
+	// periods are counted on the wall clock like rrule does, their average length gives a count a few periods off at most
+	interval := max(option.Interval, 1)
+	year, month, day := start.Date()
+	var period time.Duration
+	var periodStart func(n int) time.Time
+	switch option.Freq {
+	case rrule.YEARLY:
+		period = 8766 * time.Hour // 365.25 days
+		periodStart = func(n int) time.Time {
+			return time.Date(year+n*interval, time.January, 1, 0, 0, 0, 0, start.Location())
+		}
+	case rrule.MONTHLY:
+		period = 8766 * time.Hour / 12
+		periodStart = func(n int) time.Time {
+			return time.Date(year, month+time.Month(n*interval), 1, 0, 0, 0, 0, start.Location())
+		}
+	case rrule.WEEKLY:
+		period = 7 * 24 * time.Hour
+		weekStart := day - ((int(start.Weekday())+6)%7-option.Wkst.Day()+7)%7
+		periodStart = func(n int) time.Time {
+			return time.Date(year, month, weekStart+n*interval*7, 0, 0, 0, 0, start.Location())
+		}
+	case rrule.DAILY:
+		period = 24 * time.Hour
+		periodStart = func(n int) time.Time {
+			return time.Date(year, month, day+n*interval, 0, 0, 0, 0, start.Location())
+		}
+	case rrule.HOURLY:
+		period = time.Hour
+		periodStart = func(n int) time.Time {
+			return time.Date(year, month, day, start.Hour()+n*interval, 0, 0, 0, start.Location())
+		}
+	default:
+		return
+	}
+	n := int(since.Sub(start) / (period * time.Duration(interval)))
+	for !periodStart(n + 1).After(since) {
+		n++
+	}
+	for n > 0 && periodStart(n).After(since) {
+		n--
+	}
+	if n > 0 {
+		option.Dtstart = periodStart(n)
+	}
+}
+
+// eventStart returns the DTSTART of the event and its duration, from DTEND or DURATION. All-day events last a day by default,
+// others without an end take no time
+func eventStart(event ical.Event, loc *time.Location) (time.Time, duration, error) {
+	startProp := event.Props.Get(ical.PropDateTimeStart)
+	if startProp == nil {
+		return time.Time{}, duration{}, fmt.Errorf("no DTSTART")
+	}
+	start, err := parseDateTime(startProp, loc)
+	if err != nil {
+		return time.Time{}, duration{}, fmt.Errorf("invalid DTSTART: %w", err)
+	}
+	allDay := isDate(startProp)
+
+	if endProp := event.Props.Get(ical.PropDateTimeEnd); endProp != nil {
+		end, err := parseDateTime(endProp, loc)
+		if err != nil {
+			return time.Time{}, duration{}, fmt.Errorf("invalid DTEND: %w", err)
+		}
+		if allDay {
+			return start, duration{days: int(end.Sub(start).Round(24*time.Hour) / (24 * time.Hour))}, nil
+		}
+		return start, duration{exact: end.Sub(start)}, nil
+	}
+	if durProp := event.Props.Get(ical.PropDuration); durProp != nil {
+		d, err := durProp.Duration()
+		if err != nil {
+			return time.Time{}, duration{}, fmt.Errorf("invalid DURATION: %w", err)
+		}
+		return start, duration{days: int(d / (24 * time.Hour)), exact: d % (24 * time.Hour)}, nil
+	}
+	if allDay {
+		return start, duration{days: 1}, nil
+	}
+	return start, duration{}, nil
+}
+
+// isBusy tells if the event blocks time, i.e. it's neither transparent nor cancelled
+func isBusy(event ical.Event) bool {
+	if transp, _ := event.Props.Text(ical.PropTransparency); strings.EqualFold(transp, "TRANSPARENT") {
+		return false
+	}
+	status, _ := event.Props.Text(ical.PropStatus)
+	return !strings.EqualFold(status, string(ical.EventCancelled))
+}
+
+func isDate(prop *ical.Prop) bool {
+	return prop.ValueType() == ical.ValueDate || len(prop.Value) == len(dateFormat)
+}
+
+// parseDateTime parses a DATE or DATE-TIME property, dates are midnight in loc, times are UTC, in their TZID or else floating (in loc)
+func parseDateTime(prop *ical.Prop, loc *time.Location) (time.Time, error) {
+	values, err := parseDateTimeList([]ical.Prop{*prop}, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(values) != 1 {
+		return time.Time{}, fmt.Errorf("expected a single value, got %q", prop.Value)
+	}
+	return values[0], nil
+}
+
+// parseDateTimeList parses properties with comma separated DATE or DATE-TIME values (e.g. EXDATE), PERIOD values count by their start
+func parseDateTimeList(props []ical.Prop, loc *time.Location) ([]time.Time, error) {
+	var res []time.Time
+	for _, prop := range props {
+		valueLoc := loc
+		if tzid := prop.Params.Get(ical.PropTimezoneID); tzid != "" {
+			var err error
+			if valueLoc, err = location(tzid); err != nil {
+				return nil, err
+			}
+		}
+		for _, value := range strings.Split(prop.Value, ",") {
+			value, _, _ = strings.Cut(strings.TrimSpace(value), "/")
+			var t time.Time
+			var err error
+			switch {
+			case len(value) == len(dateFormat):
+				t, err = time.ParseInLocation(dateFormat, value, loc)
+			case strings.HasSuffix(value, "Z"):
+				t, err = time.Parse(utcDateTimeFormat, value)
+			default:
+				t, err = time.ParseInLocation(localDateTimeFormat, value, valueLoc)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid date or time %q", value)
+			}
+			res = append(res, t)
+		}
+	}
+	return res, nil
+}
+
+// location loads the TZID, some clients prefix the IANA name (e.g. "/mozilla.org/20050126_1/Europe/Berlin")
+func location(tzid string) (*time.Location, error) {
+	parts := strings.Split(strings.Trim(tzid, "/"), "/")
+	for i := range parts {
+		if loc, err := time.LoadLocation(strings.Join(parts[i:], "/")); err == nil {
+			return loc, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown timezone %q", tzid)
+}
@@ -0,0 +1,269 @@
+package icalendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-ical"
+)
+
+// decodeEvents parses the VEVENTs (lines separated by \n) as a calendar
+func decodeEvents(t *testing.T, events string) *ical.Calendar {
+	t.Helper()
+	data := "BEGIN:VCALENDAR\nVERSION:2.0\nPRODID:-//calendly-api//test//EN\n" + events + "END:VCALENDAR\n"
+	cal, err := ical.NewDecoder(strings.NewReader(strings.ReplaceAll(data, "\n", "\r\n"))).Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cal
+}
+
+// busyPeriods renders the periods as "uid start/end" in UTC, in the order Busy returns them
+func busyPeriods(events []BusyEvent) []string {
+	var periods []string
+	for _, e := range events {
+		for _, p := range e.Periods {
+			periods = append(periods, e.UID+" "+p.Start.UTC().Format(time.RFC3339)+"/"+p.End.UTC().Format(time.RFC3339))
+		}
+	}
+	return periods
+}
+
+func TestBusy(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	jan6 := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		events  string
+		loc     *time.Location
+		to      time.Time // 3 days after from (jan 6) by default
+		want    []string
+		warning string
+	}{
+		{
+			name: "single event",
+			events: `BEGIN:VEVENT
+UID:single
+DTSTART:20250106T090000Z
+DTEND:20250106T100000Z
+END:VEVENT
+`,
+			want: []string{"single 2025-01-06T09:00:00Z/2025-01-06T10:00:00Z"},
+		},
+		{
+			name: "EXDATE matching a TZID DTSTART",
+			events: `BEGIN:VEVENT
+UID:standup
+DTSTART;TZID=Europe/Berlin:20250106T090000
+DTEND;TZID=Europe/Berlin:20250106T093000
+RRULE:FREQ=DAILY;COUNT=4
+EXDATE;TZID=Europe/Berlin:20250107T090000
+EXDATE:20250108T080000Z
+END:VEVENT
+`,
+			to: jan6.AddDate(0, 0, 4),
+			want: []string{
+				"standup 2025-01-06T08:00:00Z/2025-01-06T08:30:00Z",
+				"standup 2025-01-09T08:00:00Z/2025-01-09T08:30:00Z",
+			},
+		},
+		{
+			name: "RECURRENCE-ID overrides replace their occurrence",
+			events: `BEGIN:VEVENT
+UID:weekly
+DTSTART:20250106T090000Z
+DTEND:20250106T100000Z
+RRULE:FREQ=DAILY;COUNT=3
+END:VEVENT
+BEGIN:VEVENT
+UID:weekly
+RECURRENCE-ID:20250107T090000Z
+DTSTART:20250107T140000Z
+DTEND:20250107T150000Z
+END:VEVENT
+BEGIN:VEVENT
+UID:weekly
+RECURRENCE-ID:20250108T090000Z
+DTSTART:20250108T090000Z
+DTEND:20250108T100000Z
+STATUS:CANCELLED
+END:VEVENT
+`,
+			want: []string{
+				"weekly 2025-01-06T09:00:00Z/2025-01-06T10:00:00Z",
+				"weekly 2025-01-07T14:00:00Z/2025-01-07T15:00:00Z",
+			},
+		},
+		{
+			name: "overridden occurrence without its master",
+			events: `BEGIN:VEVENT
+UID:orphan
+RECURRENCE-ID:20250107T090000Z
+DTSTART:20250107T110000Z
+DTEND:20250107T120000Z
+END:VEVENT
+`,
+			want: []string{"orphan 2025-01-07T11:00:00Z/2025-01-07T12:00:00Z"},
+		},
+		{
+			name: "all-day event in the query location",
+			events: `BEGIN:VEVENT
+UID:vacation
+DTSTART;VALUE=DATE:20250106
+DTEND;VALUE=DATE:20250108
+END:VEVENT
+BEGIN:VEVENT
+UID:floating
+DTSTART:20250106T090000
+DTEND:20250106T100000
+END:VEVENT
+`,
+			loc: newYork,
+			want: []string{
+				"vacation 2025-01-06T05:00:00Z/2025-01-08T05:00:00Z",
+				"floating 2025-01-06T14:00:00Z/2025-01-06T15:00:00Z",
+			},
+		},
+		{
+			name: "event started before the range",
+			events: `BEGIN:VEVENT
+UID:overnight
+DTSTART:20250105T220000Z
+DTEND:20250106T020000Z
+END:VEVENT
+BEGIN:VEVENT
+UID:ended
+DTSTART:20250105T220000Z
+DTEND:20250106T000000Z
+END:VEVENT
+`,
+			want: []string{"overnight 2025-01-05T22:00:00Z/2025-01-06T02:00:00Z"},
+		},
+		{
+			name: "recurring event started long before the range",
+			events: `BEGIN:VEVENT
+UID:nightly
+DTSTART;TZID=Europe/Berlin:20000101T233000
+DURATION:PT2H
+RRULE:FREQ=DAILY
+END:VEVENT
+BEGIN:VEVENT
+UID:monthly
+DTSTART:20000131T090000Z
+DTEND:20000131T100000Z
+RRULE:FREQ=MONTHLY
+END:VEVENT
+`,
+			to: jan6.AddDate(0, 0, 2),
+			want: []string{
+				"nightly 2025-01-05T22:30:00Z/2025-01-06T00:30:00Z",
+				"nightly 2025-01-06T22:30:00Z/2025-01-07T00:30:00Z",
+				"nightly 2025-01-07T22:30:00Z/2025-01-08T00:30:00Z",
+			},
+		},
+		{
+			name: "events not blocking time",
+			events: `BEGIN:VEVENT
+UID:transparent
+DTSTART:20250106T090000Z
+DTEND:20250106T100000Z
+TRANSP:TRANSPARENT
+END:VEVENT
+BEGIN:VEVENT
+UID:cancelled
+DTSTART:20250106T090000Z
+DTEND:20250106T100000Z
+STATUS:CANCELLED
+END:VEVENT
+BEGIN:VEVENT
+UID:later
+DTSTART:20250110T090000Z
+DTEND:20250110T100000Z
+END:VEVENT
+`,
+		},
+		{
+			name: "event without UID",
+			events: `BEGIN:VEVENT
+SUMMARY:Lunch
+DTSTART:20250106T120000Z
+DTEND:20250106T130000Z
+END:VEVENT
+`,
+			warning: `event "Lunch" has no UID, skipped`,
+		},
+		{
+			name: "unsupported rule",
+			events: `BEGIN:VEVENT
+UID:minutely
+DTSTART:20250106T090000Z
+DTEND:20250106T090100Z
+RRULE:FREQ=MINUTELY
+END:VEVENT
+`,
+			warning: "event minutely skipped: RRULE with FREQ=MINUTELY is not supported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, to := tt.loc, tt.to
+			if loc == nil {
+				loc = time.UTC
+			}
+			if to.IsZero() {
+				to = jan6.AddDate(0, 0, 3)
+			}
+			events, warnings := Busy(decodeEvents(t, tt.events), loc, jan6, to)
+			if got := busyPeriods(events); strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("periods:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+			if tt.warning == "" && len(warnings) > 0 {
+				t.Errorf("unexpected warnings %q", warnings)
+			}
+			if tt.warning != "" && (len(warnings) != 1 || warnings[0] != tt.warning) {
+				t.Errorf("warnings = %q, want %q", warnings, tt.warning)
+			}
+		})
+	}
+}
+
+func TestBusyOccurrenceCaps(t *testing.T) {
+	tests := []struct {
+		name    string
+		rrule   string
+		from    time.Time
+		periods int
+		warning string
+	}{
+		{
+			name:    "occurrences within the range",
+			rrule:   "FREQ=HOURLY",
+			from:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			periods: maxOccurrences,
+			warning: "event hourly has too many occurrences, only the first 5000 are used",
+		},
+		{
+			name:    "occurrences before the range",
+			rrule:   "FREQ=HOURLY;COUNT=1000000",
+			from:    time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC),
+			warning: "event hourly skipped: too many occurrences to expand, more than 500000",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cal := decodeEvents(t, "BEGIN:VEVENT\nUID:hourly\nDTSTART:19000101T000000Z\nDURATION:PT30M\nRRULE:"+tt.rrule+"\nEND:VEVENT\n")
+			events, warnings := Busy(cal, time.UTC, tt.from, tt.from.AddDate(1, 0, 0))
+			if got := len(busyPeriods(events)); got != tt.periods {
+				t.Errorf("got %d periods, want %d", got, tt.periods)
+			}
+			if len(warnings) != 1 || warnings[0] != tt.warning {
+				t.Errorf("warnings = %q, want %q", warnings, tt.warning)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package icalendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+)
+
+const (
+	// MethodRequest and MethodReply are the iTIP methods (RFC 5546) of a request (for free/busy time or to take part in an event)
+	// and of its reply
+	MethodRequest = "REQUEST"
+	MethodReply   = "REPLY"
+
+	// FreeBusyFree and FreeBusyBusy are the FBTYPEs of the FREEBUSY periods
+	FreeBusyFree = "FREE"
+	FreeBusyBusy = "BUSY"
+)
+
+// FreeBusyRequest is the VFREEBUSY of an iTIP REQUEST asking for the free/busy time of the attendees within [Start, End)
+type FreeBusyRequest struct {
+	UID       string
+	Organizer string
+	Start     time.Time
+	End       time.Time
+	Attendees []string // calendar addresses, e.g. mailto:jane@example.com
+}
+
+// ParseFreeBusyRequest reads the VFREEBUSY of the calendar, it needs a DTSTART, a DTEND after it and at least one ATTENDEE
+func ParseFreeBusyRequest(cal *ical.Calendar) (*FreeBusyRequest, error) {
+	var comp *ical.Component
+	for _, child := range cal.Children {
+		if child.Name == ical.CompFreeBusy {
+			comp = child
+			break
+		}
+	}
+	if comp == nil {
+		return nil, fmt.Errorf("no VFREEBUSY component")
+	}
+
+	req := &FreeBusyRequest{}
+	req.UID, _ = comp.Props.Text(ical.PropUID)
+	if organizer := comp.Props.Get(ical.PropOrganizer); organizer != nil {
+		req.Organizer = organizer.Value
+	}
+	for _, name := range []string{ical.PropDateTimeStart, ical.PropDateTimeEnd} {
+		prop := comp.Props.Get(name)
+		if prop == nil {
+			return nil, fmt.Errorf("no %s", name)
+		}
+		t, err := parseDateTime(prop, time.UTC)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		if name == ical.PropDateTimeStart {
+			req.Start = t
+		} else {
+			req.End = t
+		}
+	}
+	if !req.Start.Before(req.End) {
+		return nil, fmt.Errorf("DTSTART must be before DTEND")
+	}
+	for _, attendee := range comp.Props.Values(ical.PropAttendee) {
+		req.Attendees = append(req.Attendees, attendee.Value)
+	}
+	if len(req.Attendees) == 0 {
+		return nil, fmt.Errorf("no ATTENDEE")
+	}
+	return req, nil
+}
+
+// NewFreeBusy returns a VFREEBUSY of [start, end) listing the free and the busy periods, in UTC as RFC 5545 requires
+func NewFreeBusy(uid string, start, end, stamp time.Time, free, busy []Period) *ical.Component {
+	comp := ical.NewComponent(ical.CompFreeBusy)
+	comp.Props.SetText(ical.PropUID, uid)
+	comp.Props.SetDateTime(ical.PropDateTimeStamp, stamp.UTC())
+	comp.Props.SetDateTime(ical.PropDateTimeStart, start.UTC())
+	comp.Props.SetDateTime(ical.PropDateTimeEnd, end.UTC())
+	addFreeBusy(comp.Props, FreeBusyFree, free)
+	addFreeBusy(comp.Props, FreeBusyBusy, busy)
+	return comp
+}
+
+// addFreeBusy adds a FREEBUSY property of the fbType listing the periods, if there are any
+func addFreeBusy(props ical.Props, fbType string, periods []Period) {
+	if len(periods) == 0 {
+		return
+	}
+	values := make([]string, 0, len(periods))
+	for _, p := range periods {
+		values = append(values, p.Start.UTC().Format(utcDateTimeFormat)+"/"+p.End.UTC().Format(utcDateTimeFormat))
+	}
+	prop := ical.NewProp(ical.PropFreeBusy)
+	prop.Params.Set(ical.ParamFreeBusyType, fbType)
+	prop.Value = strings.Join(values, ",")
+	props.Add(prop)
+}
+
+// SetCalendarAddress sets the calendar user address property (e.g. ATTENDEE, ORGANIZER), an email is turned into a mailto: URI
+func SetCalendarAddress(props ical.Props, name, address string) {
+	props.Set(calendarUser(name, address, ""))
+}
+
+// calendarUser returns the calendar user address property with the user's common name (CN) if any
+func calendarUser(name, address, commonName string) *ical.Prop {
+	if !strings.Contains(address, ":") {
+		address = "mailto:" + address
+	}
+	prop := ical.NewProp(name)
+	prop.Value = address
+	if commonName != "" {
+		prop.Params.Set(ical.ParamCommonName, commonName)
+	}
+	return prop
+}
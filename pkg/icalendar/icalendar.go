@@ -0,0 +1,131 @@
+// Package icalendar builds RFC 5545 iCalendar objects on top of go-ical, it's shared by the calendar feed and the
+// other iCalendar speaking endpoints
+package icalendar
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/emersion/go-ical"
+)
+
+const (
+	// ProductID identifies this service as the creator of the iCalendar objects
+	ProductID = "-//calendly-api//calendly-api//EN"
+	// UIDDomain is the right hand side of the UIDs of the objects this service creates
+	UIDDomain = "calendly-api"
+	// ContentType is the MIME type of iCalendar objects
+	ContentType = "text/calendar; charset=utf-8"
+
+	localDateTimeFormat = "20060102T150405"
+)
+
+// NewCalendar returns an empty VCALENDAR with the mandatory VERSION and PRODID
+func NewCalendar() *ical.Calendar {
+	cal := ical.NewCalendar()
+	cal.Props.SetText(ical.PropVersion, "2.0")
+	cal.Props.SetText(ical.PropProductID, ProductID)
+	return cal
+}
+
+// Encode serializes the calendar
+func Encode(cal *ical.Calendar) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := ical.NewEncoder(&buf).Encode(cal); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UID returns a globally unique and stable UID for the object of the kind (e.g. "booking") with the id
+func UID(kind, id string) string {
+	return fmt.Sprintf("%s-%s@%s", kind, id, UIDDomain)
+}
+
+// SetName sets the display name of the calendar, as NAME and as the X-WR-CALNAME most clients still read
+func SetName(cal *ical.Calendar, name string) {
+	cal.Props.SetText(ical.PropName, name)
+	prop := ical.NewProp("X-WR-CALNAME")
+	prop.SetText(name)
+	prop.Params.Del(ical.ParamValue)
+	cal.Props.Set(prop)
+}
+
+// SetInteger sets the INTEGER property (e.g. SEQUENCE)
+func SetInteger(props ical.Props, name string, n int) {
+	setRaw(props, name, strconv.Itoa(n))
+}
+
+// NewEvent returns a VEVENT from start to end, the times are written in their location (with a TZID) unless it's UTC
+func NewEvent(uid string, start, end, stamp time.Time) *ical.Event {
+	event := ical.NewEvent()
+	event.Props.SetText(ical.PropUID, uid)
+	event.Props.SetDateTime(ical.PropDateTimeStamp, stamp.UTC())
+	event.Props.SetDateTime(ical.PropDateTimeStart, start)
+	event.Props.SetDateTime(ical.PropDateTimeEnd, end)
+	return event
+}
+
+// AddTimezone adds the VTIMEZONE of loc covering [from, to) to the calendar, UTC times need none
+func AddTimezone(cal *ical.Calendar, loc *time.Location, from, to time.Time) {
+	if loc == time.UTC {
+		return
+	}
+	cal.Children = append(cal.Children, Timezone(loc, from, to))
+}
+
+// Timezone builds the VTIMEZONE of loc covering [from, to): one STANDARD or DAYLIGHT observance for the offset in effect
+// at from and one for every transition until to, found through the zone bounds of the tz database
+func Timezone(loc *time.Location, from, to time.Time) *ical.Component {
+	tz := ical.NewComponent(ical.CompTimezone)
+	tz.Props.SetText(ical.PropTimezoneID, loc.String())
+
+	t := from.In(loc)
+	zoneStart, zoneEnd := t.ZoneBounds()
+	if zoneStart.IsZero() {
+		// the zone has always had this offset
+		zoneStart = t
+	}
+	_, offsetFrom := zoneStart.Add(-time.Nanosecond).Zone()
+	for {
+		name, offset := zoneStart.Zone()
+		tz.Children = append(tz.Children, observance(zoneStart, name, offsetFrom, offset, zoneStart.IsDST()))
+		if zoneEnd.IsZero() || !zoneEnd.Before(to) {
+			break
+		}
+		offsetFrom = offset
+		zoneStart, zoneEnd = zoneEnd.In(loc).ZoneBounds()
+	}
+	return tz
+}
+
+// observance is a STANDARD or DAYLIGHT sub-component starting at start, its DTSTART is the local time before the onset
+func observance(start time.Time, name string, offsetFrom, offsetTo int, dst bool) *ical.Component {
+	comp := ical.NewComponent(ical.CompTimezoneStandard)
+	if dst {
+		comp.Name = ical.CompTimezoneDaylight
+	}
+	setRaw(comp.Props, ical.PropDateTimeStart, start.UTC().Add(time.Duration(offsetFrom)*time.Second).Format(localDateTimeFormat))
+	setRaw(comp.Props, ical.PropTimezoneOffsetFrom, utcOffset(offsetFrom))
+	setRaw(comp.Props, ical.PropTimezoneOffsetTo, utcOffset(offsetTo))
+	comp.Props.SetText(ical.PropTimezoneName, name)
+	return comp
+}
+
+// setRaw sets the already formatted value of the property in its default value type
+func setRaw(props ical.Props, name, value string) {
+	prop := ical.NewProp(name)
+	prop.Value = value
+	props.Set(prop)
+}
+
+// utcOffset formats the offset in seconds east of UTC as a UTC-OFFSET value (e.g. +0530)
+func utcOffset(offset int) string {
+	sign := '+'
+	if offset < 0 {
+		sign, offset = '-', -offset
+	}
+	return fmt.Sprintf("%c%02d%02d", sign, offset/3600, offset/60%60)
+}
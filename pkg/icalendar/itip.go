@@ -0,0 +1,22 @@
+package icalendar
+
+import (
+	"github.com/emersion/go-ical"
+)
+
+// MethodCancel is the iTIP method (RFC 5546) cancelling an event sent before with MethodRequest
+const MethodCancel = "CANCEL"
+
+// SetOrganizer sets the ORGANIZER of the event, an email is turned into a mailto: URI
+func SetOrganizer(props ical.Props, address, commonName string) {
+	props.Set(calendarUser(ical.PropOrganizer, address, commonName))
+}
+
+// AddAttendee adds an ATTENDEE who already accepted the event, so that no reply is expected from them
+func AddAttendee(props ical.Props, address, commonName string) {
+	prop := calendarUser(ical.PropAttendee, address, commonName)
+	prop.Params.Set(ical.ParamRole, "REQ-PARTICIPANT")
+	prop.Params.Set(ical.ParamParticipationStatus, "ACCEPTED")
+	prop.Params.Set(ical.ParamRSVP, "FALSE")
+	props.Add(prop)
+}